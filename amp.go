@@ -0,0 +1,37 @@
+package urlmeta
+
+import (
+	"context"
+	"net/url"
+)
+
+// WithPreferAMP extracts metadata from the page's AMP version (advertised via
+// <link rel="amphtml">) when one exists. AMP pages are usually much lighter
+// and carry the same meta tags. The result keeps the original page URL;
+// if the AMP fetch fails the original page's metadata is returned with a
+// WarningAMPFailed warning.
+func WithPreferAMP(prefer bool) Option {
+	return func(c *Client) {
+		c.preferAMP = prefer
+	}
+}
+
+// extractAMP re-extracts metadata from original.AMPURL, falling back to
+// original on failure
+func (c *Client) extractAMP(ctx context.Context, original *Metadata, parsedURL *url.URL) *Metadata {
+	page, err := c.fetchHTML(ctx, original.AMPURL)
+	if err != nil {
+		original.addWarning(WarningAMPFailed, "%v", err)
+		return original
+	}
+
+	amp := c.buildMetadata(page, parsedURL)
+	amp.URL = original.URL
+	amp.AMPURL = original.AMPURL
+	if amp.CanonicalURL == "" {
+		amp.CanonicalURL = original.CanonicalURL
+	}
+	amp.Warnings = append(original.Warnings, amp.Warnings...)
+
+	return amp
+}
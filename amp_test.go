@@ -0,0 +1,104 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func newAMPServer(t *testing.T, ampStatus int) (*httptest.Server, *int32) {
+	t.Helper()
+	var ampHits int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/article":
+			w.Write([]byte(`<html><head>
+				<title>Full Article</title>
+				<link rel="canonical" href="/article">
+				<link rel="amphtml" href="/article/amp">
+			</head><body></body></html>`))
+		case "/article/amp":
+			atomic.AddInt32(&ampHits, 1)
+			w.WriteHeader(ampStatus)
+			w.Write([]byte(`<html amp><head>
+				<title>AMP Article</title>
+				<meta name="description" content="From AMP">
+				<meta property="og:image" content="cover.jpg">
+			</head><body></body></html>`))
+		}
+	}))
+
+	return server, &ampHits
+}
+
+func TestAMPURLDetection(t *testing.T) {
+	server, ampHits := newAMPServer(t, http.StatusOK)
+	defer server.Close()
+
+	metadata, err := Extract(server.URL + "/article")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.AMPURL != server.URL+"/article/amp" {
+		t.Errorf("Expected AMP URL '%s', got '%s'", server.URL+"/article/amp", metadata.AMPURL)
+	}
+
+	if metadata.Title != "Full Article" {
+		t.Errorf("Expected original title without WithPreferAMP, got '%s'", metadata.Title)
+	}
+
+	if *ampHits != 0 {
+		t.Errorf("Expected AMP page not to be fetched, got %d hits", *ampHits)
+	}
+}
+
+func TestWithPreferAMP(t *testing.T) {
+	server, _ := newAMPServer(t, http.StatusOK)
+	defer server.Close()
+
+	client := NewClient(WithPreferAMP(true))
+	metadata, err := client.Extract(server.URL + "/article")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.Title != "AMP Article" || metadata.Description != "From AMP" {
+		t.Errorf("Expected metadata from AMP page, got title '%s' description '%s'", metadata.Title, metadata.Description)
+	}
+
+	if metadata.URL != server.URL+"/article" {
+		t.Errorf("Expected original URL to be kept, got '%s'", metadata.URL)
+	}
+
+	if metadata.CanonicalURL != server.URL+"/article" {
+		t.Errorf("Expected canonical URL from original page, got '%s'", metadata.CanonicalURL)
+	}
+
+	// Relative URLs on the AMP page resolve against the AMP URL
+	if len(metadata.Images) != 1 || metadata.Images[0].URL != server.URL+"/article/cover.jpg" {
+		t.Errorf("Expected AMP-relative image URL, got %+v", metadata.Images)
+	}
+}
+
+func TestWithPreferAMPFallback(t *testing.T) {
+	server, _ := newAMPServer(t, http.StatusInternalServerError)
+	defer server.Close()
+
+	client := NewClient(WithPreferAMP(true))
+	metadata, err := client.Extract(server.URL + "/article")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.Title != "Full Article" {
+		t.Errorf("Expected fallback to original page, got '%s'", metadata.Title)
+	}
+
+	if !metadata.HasWarning(WarningAMPFailed) {
+		t.Errorf("Expected amp_failed warning, got %v", metadata.Warnings)
+	}
+}
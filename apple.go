@@ -0,0 +1,254 @@
+package urlmeta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Apple embed player sizes: a single song or episode gets the compact
+// player, everything else the full one
+const (
+	appleEmbedWidth        = 660
+	appleEmbedHeight       = 450
+	appleEmbedHeightSingle = 175
+)
+
+// appleCountry matches the storefront path segment, e.g. "us"
+var appleCountry = regexp.MustCompile(`^[a-z]{2}$`)
+
+// appleID matches numeric catalog IDs, with the "id" prefix podcast URLs use
+var appleID = regexp.MustCompile(`^(?:id)?(\d+)$`)
+
+// appleMusicKinds are the music.apple.com pages with a numeric catalog ID
+var appleMusicKinds = map[string]bool{"album": true, "song": true, "artist": true}
+
+// appleExtractor is the SiteExtractor for Apple Podcasts and Apple Music
+type appleExtractor struct {
+	lookupBase string
+}
+
+// AppleExtractor returns a SiteExtractor for podcasts.apple.com shows and
+// episodes and music.apple.com albums, songs and artists, which have no
+// oEmbed endpoint. It sets Metadata.OEmbed to a "rich" embed of Apple's
+// player, built from the URL, and reads the title, artist, artwork and
+// release date from the iTunes lookup API. Playlists and stations aren't
+// in the lookup API and are extracted as usual.
+func AppleExtractor() SiteExtractor {
+	return &appleExtractor{lookupBase: "https://itunes.apple.com"}
+}
+
+// Name implements SiteExtractor
+func (a *appleExtractor) Name() string {
+	return "apple"
+}
+
+// Match implements SiteExtractor
+func (a *appleExtractor) Match(u *url.URL) bool {
+	return appleItem(u) != nil
+}
+
+// appleTarget is an Apple Podcasts or Apple Music URL
+type appleTarget struct {
+	podcast bool
+	country string
+	kind    string // podcast, album, song or artist
+	id      string // catalog ID of the show, album, song or artist
+	trackID string // episode or song within a show or album (?i=)
+}
+
+// appleItem returns the item u links to, or nil
+func appleItem(u *url.URL) *appleTarget {
+	host := strings.ToLower(u.Hostname())
+	if host != "podcasts.apple.com" && host != "music.apple.com" {
+		return nil
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	target := &appleTarget{podcast: host == "podcasts.apple.com", country: "us"}
+	if appleCountry.MatchString(parts[0]) {
+		target.country, parts = parts[0], parts[1:]
+	}
+	// <kind>/<slug>/<id>, the slug being optional
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil
+	}
+	target.kind = parts[0]
+	if target.podcast != (target.kind == "podcast") || (!target.podcast && !appleMusicKinds[target.kind]) {
+		return nil
+	}
+	m := appleID.FindStringSubmatch(parts[len(parts)-1])
+	if m == nil {
+		return nil
+	}
+	target.id = m[1]
+
+	if i := u.Query().Get("i"); i != "" && target.kind != "artist" {
+		if m := appleID.FindStringSubmatch(i); m != nil {
+			target.trackID = m[1]
+		}
+	}
+	return target
+}
+
+// iTunesLookup is a response of the iTunes lookup API
+type iTunesLookup struct {
+	Results []iTunesItem `json:"results"`
+}
+
+// iTunesItem is a show, episode, album, song or artist in a lookup
+// response
+type iTunesItem struct {
+	WrapperType      string `json:"wrapperType"`
+	Kind             string `json:"kind"`
+	TrackID          int64  `json:"trackId"`
+	TrackName        string `json:"trackName"`
+	CollectionName   string `json:"collectionName"`
+	ArtistName       string `json:"artistName"`
+	Description      string `json:"description"`
+	ArtworkURL100    string `json:"artworkUrl100"`
+	ArtworkURL600    string `json:"artworkUrl600"`
+	ReleaseDate      string `json:"releaseDate"`
+	PrimaryGenreName string `json:"primaryGenreName"`
+	TrackTimeMillis  int    `json:"trackTimeMillis"`
+	CollectionURL    string `json:"collectionViewUrl"`
+}
+
+// Extract implements SiteExtractor
+func (a *appleExtractor) Extract(ctx context.Context, client *http.Client, u *url.URL) (*Metadata, error) {
+	target := appleItem(u)
+
+	item, err := a.lookup(ctx, client, target)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, providerURL := "Apple Music", "https://music.apple.com"
+	if target.podcast {
+		provider, providerURL = "Apple Podcasts", "https://podcasts.apple.com"
+	}
+
+	metadata := &Metadata{
+		Title:           item.title(),
+		Description:     cleanText(stripTags(item.Description)),
+		ProviderName:    provider,
+		ProviderURL:     providerURL,
+		ProviderDisplay: strings.TrimPrefix(providerURL, "https://"),
+		SiteName:        provider,
+		Type:            item.ogType(),
+	}
+	if item.WrapperType != "artist" {
+		metadata.Author = item.ArtistName
+	}
+	if item.PrimaryGenreName != "" {
+		metadata.Keywords = []string{item.PrimaryGenreName}
+	}
+	if item.Kind == "song" {
+		metadata.Music = &MusicInfo{Duration: item.TrackTimeMillis / 1000, Album: item.CollectionURL}
+	}
+	if item.ReleaseDate != "" {
+		setAPIDates(metadata, item.ReleaseDate, "")
+	}
+
+	artwork := Image{URL: item.ArtworkURL600, Width: 600, Height: 600, Source: ImageSourceAPI}
+	if artwork.URL == "" {
+		artwork = Image{URL: item.ArtworkURL100, Width: 100, Height: 100, Source: ImageSourceAPI}
+	}
+	if artwork.URL != "" {
+		metadata.Images = []Image{artwork}
+	}
+
+	height := appleEmbedHeight
+	if target.trackID != "" || target.kind == "song" {
+		height = appleEmbedHeightSingle
+	}
+	metadata.OEmbed = &OEmbed{
+		Type:         "rich",
+		Version:      "1.0",
+		Title:        metadata.Title,
+		AuthorName:   metadata.Author,
+		ProviderName: provider,
+		ProviderURL:  providerURL,
+		ThumbnailURL: artwork.URL,
+		Width:        appleEmbedWidth,
+		Height:       height,
+		HTML: fmt.Sprintf(`<iframe src="%s" height="%d" frameborder="0" allow="autoplay *; encrypted-media *; fullscreen *; clipboard-write" sandbox="allow-forms allow-popups allow-same-origin allow-scripts allow-storage-access-by-user-activation allow-top-navigation-by-user-activation" style="width:100%%;max-width:%dpx;overflow:hidden;border-radius:10px;"></iframe>`,
+			html.EscapeString(appleEmbedURL(u, target)), height, appleEmbedWidth),
+	}
+	if artwork.URL != "" {
+		metadata.OEmbed.ThumbnailWidth, metadata.OEmbed.ThumbnailHeight = artwork.Width, artwork.Height
+	}
+
+	return metadata, nil
+}
+
+// lookup returns the catalog item target links to. Episodes aren't looked
+// up by ID, so they are searched among the show's recent episodes, and the
+// show stands in for older ones.
+func (a *appleExtractor) lookup(ctx context.Context, client *http.Client, target *appleTarget) (*iTunesItem, error) {
+	query := url.Values{"id": {target.id}, "country": {target.country}}
+	if target.trackID != "" {
+		if target.podcast {
+			query.Set("entity", "podcastEpisode")
+			query.Set("limit", "200")
+		} else {
+			query.Set("id", target.trackID)
+		}
+	}
+
+	var lookup iTunesLookup
+	if err := getJSON(ctx, client, a.lookupBase+"/lookup?"+query.Encode(), nil, &lookup); err != nil {
+		return nil, err
+	}
+	if len(lookup.Results) == 0 {
+		return nil, errors.New("apple: item not found")
+	}
+
+	if target.podcast && target.trackID != "" {
+		for i, item := range lookup.Results {
+			if strconv.FormatInt(item.TrackID, 10) == target.trackID {
+				return &lookup.Results[i], nil
+			}
+		}
+	}
+	return &lookup.Results[0], nil
+}
+
+// title returns the item's display name
+func (i *iTunesItem) title() string {
+	switch i.WrapperType {
+	case "collection":
+		return i.CollectionName
+	case "artist":
+		return i.ArtistName
+	}
+	return i.TrackName
+}
+
+// ogType returns the og:type of the item: music.song and music.album for
+// songs and albums, website for podcasts, episodes and artists
+func (i *iTunesItem) ogType() string {
+	switch {
+	case i.Kind == "song":
+		return "music.song"
+	case i.WrapperType == "collection":
+		return "music.album"
+	}
+	return "website"
+}
+
+// appleEmbedURL returns the player URL for u: the same page on the embed
+// host, keeping only the selected episode or song
+func appleEmbedURL(u *url.URL, target *appleTarget) string {
+	embed := &url.URL{Scheme: "https", Host: "embed." + strings.ToLower(u.Hostname()), Path: u.Path}
+	if target.trackID != "" {
+		embed.RawQuery = "i=" + target.trackID
+	}
+	return embed.String()
+}
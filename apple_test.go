@@ -0,0 +1,145 @@
+package urlmeta
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func newITunesServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		w.Header().Set("Content-Type", "text/javascript")
+		switch {
+		case r.URL.Path != "/lookup":
+			http.NotFound(w, r)
+		case query.Get("id") == "1440833098":
+			w.Write([]byte(`{"resultCount":1,"results":[{"wrapperType":"track","kind":"song","trackId":1440833098,
+				"trackName":"Blue in Green","collectionName":"Kind of Blue","artistName":"Miles Davis",
+				"artworkUrl100":"https://is1-ssl.mzstatic.com/image/100x100bb.jpg","releaseDate":"1959-08-17T07:00:00Z",
+				"primaryGenreName":"Jazz","trackTimeMillis":337000,"collectionViewUrl":"https://music.apple.com/us/album/kind-of-blue/1440833098"}]}`))
+		case query.Get("id") == "1200361736" && query.Get("entity") == "podcastEpisode":
+			w.Write([]byte(`{"resultCount":2,"results":[
+				{"wrapperType":"track","kind":"podcast","trackId":1200361736,"trackName":"The Daily","artistName":"The New York Times",
+				"artworkUrl600":"https://is1-ssl.mzstatic.com/image/600x600bb.jpg","primaryGenreName":"Daily News"},
+				{"wrapperType":"podcastEpisode","kind":"podcast-episode","trackId":1000650000000,"trackName":"An Episode",
+				"description":"<p>What happened today.</p>","releaseDate":"2024-03-01T10:00:00Z","artworkUrl600":"https://is1-ssl.mzstatic.com/image/ep600x600bb.jpg"}]}`))
+		case query.Get("id") == "1200361736":
+			w.Write([]byte(`{"resultCount":1,"results":[{"wrapperType":"track","kind":"podcast","trackId":1200361736,
+				"trackName":"The Daily","artistName":"The New York Times","artworkUrl600":"https://is1-ssl.mzstatic.com/image/600x600bb.jpg"}]}`))
+		default:
+			w.Write([]byte(`{"resultCount":0,"results":[]}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestAppleItem(t *testing.T) {
+	tests := []struct {
+		url     string
+		kind    string
+		id      string
+		trackID string
+		country string
+	}{
+		{"https://podcasts.apple.com/us/podcast/the-daily/id1200361736", "podcast", "1200361736", "", "us"},
+		{"https://podcasts.apple.com/gb/podcast/the-daily/id1200361736?i=1000650000000", "podcast", "1200361736", "1000650000000", "gb"},
+		{"https://podcasts.apple.com/podcast/id1200361736", "podcast", "1200361736", "", "us"},
+		{"https://music.apple.com/us/album/kind-of-blue/1440833098?i=1440833098", "album", "1440833098", "1440833098", "us"},
+		{"https://music.apple.com/us/song/blue-in-green/1440833098", "song", "1440833098", "", "us"},
+		{"https://music.apple.com/us/artist/miles-davis/44984", "artist", "44984", "", "us"},
+		{"https://music.apple.com/us/playlist/jazz-essentials/pl.9a4b6e2b2b2d4c1c8c9a1d0f4d0f2c1a", "", "", "", ""},
+		{"https://music.apple.com/us/podcast/the-daily/id1200361736", "", "", "", ""},
+		{"https://podcasts.apple.com/us/browse", "", "", "", ""},
+		{"https://www.apple.com/us/album/kind-of-blue/1440833098", "", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		target := appleItem(u)
+		if target == nil {
+			if tt.kind != "" {
+				t.Errorf("appleItem(%s) = nil, want %s %s", tt.url, tt.kind, tt.id)
+			}
+			continue
+		}
+		if target.kind != tt.kind || target.id != tt.id || target.trackID != tt.trackID || target.country != tt.country {
+			t.Errorf("appleItem(%s) = %+v, want %s %s %s in %s", tt.url, target, tt.kind, tt.id, tt.trackID, tt.country)
+		}
+	}
+}
+
+func TestAppleExtractorSong(t *testing.T) {
+	server := newITunesServer(t)
+	client := NewClient(WithSiteExtractors(&appleExtractor{lookupBase: server.URL}))
+
+	metadata, err := client.Extract("https://music.apple.com/us/album/kind-of-blue/1440833098?i=1440833098")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.Title != "Blue in Green" || metadata.Author != "Miles Davis" || metadata.ProviderName != "Apple Music" {
+		t.Errorf("Unexpected song fields: %q by %q from %q", metadata.Title, metadata.Author, metadata.ProviderName)
+	}
+	if metadata.Music == nil || metadata.Music.Duration != 337 {
+		t.Errorf("Expected the song duration, got %+v", metadata.Music)
+	}
+	if metadata.Type != "music.song" {
+		t.Errorf("Expected og:type music.song, got %q", metadata.Type)
+	}
+	if metadata.PublishedAt == nil || metadata.PublishedAt.Year() != 1959 {
+		t.Errorf("Expected the release date, got %q", metadata.PublishedTime)
+	}
+
+	oembed := metadata.OEmbed
+	if oembed == nil {
+		t.Fatal("Expected a synthesized oEmbed")
+	}
+	if !strings.Contains(oembed.HTML, `src="https://embed.music.apple.com/us/album/kind-of-blue/1440833098?i=1440833098"`) {
+		t.Errorf("Unexpected embed HTML: %s", oembed.HTML)
+	}
+	if oembed.Height != appleEmbedHeightSingle || oembed.ThumbnailURL != "https://is1-ssl.mzstatic.com/image/100x100bb.jpg" {
+		t.Errorf("Expected the compact player with artwork, got %+v", oembed)
+	}
+}
+
+func TestAppleExtractorPodcast(t *testing.T) {
+	server := newITunesServer(t)
+	extractor := &appleExtractor{lookupBase: server.URL}
+	client := NewClient(WithSiteExtractors(extractor))
+
+	metadata, err := client.Extract("https://podcasts.apple.com/us/podcast/the-daily/id1200361736")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if metadata.Title != "The Daily" || metadata.OEmbed.Height != appleEmbedHeight {
+		t.Errorf("Expected the show with the full player, got %q, %+v", metadata.Title, metadata.OEmbed)
+	}
+	if len(metadata.Images) != 1 || metadata.Images[0].Width != 600 {
+		t.Errorf("Expected the show artwork, got %+v", metadata.Images)
+	}
+	if metadata.Type != "website" || metadata.OEmbed.Type != "rich" {
+		t.Errorf("Expected og:type website with a rich embed, got %q and %q", metadata.Type, metadata.OEmbed.Type)
+	}
+
+	metadata, err = client.Extract("https://podcasts.apple.com/us/podcast/the-daily/id1200361736?i=1000650000000")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if metadata.Title != "An Episode" || metadata.Description != "What happened today." {
+		t.Errorf("Expected the episode, got %q: %q", metadata.Title, metadata.Description)
+	}
+	if !strings.Contains(metadata.OEmbed.HTML, "https://embed.podcasts.apple.com/us/podcast/the-daily/id1200361736?i=1000650000000") {
+		t.Errorf("Unexpected embed HTML: %s", metadata.OEmbed.HTML)
+	}
+
+	missing, _ := url.Parse("https://music.apple.com/us/album/unknown/1")
+	if _, err := extractor.Extract(context.Background(), client.pipelineClient(), missing); err == nil {
+		t.Error("Expected an unknown item to fail the extractor")
+	}
+}
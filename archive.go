@@ -0,0 +1,126 @@
+package urlmeta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// FallbackSource provides an alternative copy of a page, tried when the
+// page itself can't be fetched or yields no metadata (see
+// WithFallbackSources)
+type FallbackSource interface {
+	// Name identifies the source in warnings and logs
+	Name() string
+
+	// FallbackURL returns the URL of a copy of targetURL, or "" when the
+	// source has none. The copy must be the page's HTML as served.
+	FallbackURL(ctx context.Context, targetURL string) (string, error)
+}
+
+// WithFallbackSources sets the sources tried, in order, when fetching a
+// page fails or its metadata has no title, description or image, e.g.
+// WithFallbackSources(WaybackFallback(), ArchiveTodayFallback()). The first
+// copy yielding metadata wins; the result keeps the original URL, with
+// relative links resolved against it, and carries a WarningFallbackUsed.
+// Invalid URLs, host policy and SSRF errors are never retried through a
+// fallback. No fallbacks are used by default.
+func WithFallbackSources(sources ...FallbackSource) Option {
+	return func(c *Client) {
+		c.fallbackSources = sources
+	}
+}
+
+// mirrorSource is a FallbackSource deriving copy URLs from a template
+type mirrorSource struct {
+	name     string
+	template string
+}
+
+// MirrorFallback returns a FallbackSource for a mirror or cache whose copy
+// URLs are template with "{url}" replaced by the page URL, or "{query}" by
+// the page URL escaped as a query value, e.g.
+// "https://mirror.internal/fetch?u={query}".
+func MirrorFallback(name, template string) FallbackSource {
+	return &mirrorSource{name: name, template: template}
+}
+
+// WaybackFallback returns a FallbackSource for the Internet Archive's
+// Wayback Machine, using the most recent snapshot as originally served
+func WaybackFallback() FallbackSource {
+	return MirrorFallback("wayback", "https://web.archive.org/web/2id_/{url}")
+}
+
+// ArchiveTodayFallback returns a FallbackSource for archive.today, using
+// the most recent snapshot
+func ArchiveTodayFallback() FallbackSource {
+	return MirrorFallback("archive.today", "https://archive.ph/newest/{url}")
+}
+
+// Name implements FallbackSource
+func (m *mirrorSource) Name() string {
+	return m.name
+}
+
+// FallbackURL implements FallbackSource
+func (m *mirrorSource) FallbackURL(_ context.Context, targetURL string) (string, error) {
+	return strings.NewReplacer("{url}", targetURL, "{query}", url.QueryEscape(targetURL)).Replace(m.template), nil
+}
+
+// needsFallback reports whether the primary result calls for the fallback
+// sources: a fetch failure other than a refusal, or empty metadata, with
+// ctx still live
+func (c *Client) needsFallback(ctx context.Context, metadata *Metadata, err error) bool {
+	if len(c.fallbackSources) == 0 || ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return !errors.Is(err, ErrInvalidURL) &&
+			!errors.Is(err, ErrUnsupportedScheme) &&
+			!errors.Is(err, ErrHostNotAllowed) &&
+			!errors.Is(err, ErrBlockedAddress)
+	}
+	return metadata.Title == "" && metadata.Description == "" && len(metadata.Images) == 0
+}
+
+// extractFallback tries the fallback sources in order, returning metadata
+// from the first copy of targetURL that yields any. cause is why the
+// primary result was rejected, for the warning.
+func (c *Client) extractFallback(ctx context.Context, targetURL string, parsedURL *url.URL, cause string) (*Metadata, error) {
+	var errs []error
+	for _, source := range c.fallbackSources {
+		fallbackURL, err := source.FallbackURL(ctx, targetURL)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source.Name(), err))
+			continue
+		}
+		if fallbackURL == "" {
+			continue
+		}
+
+		c.logDebug(ctx, "urlmeta: trying fallback source", "url", targetURL, "source", source.Name(), "fallback_url", fallbackURL)
+		page, err := c.fetchHTML(ctx, fallbackURL)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source.Name(), err))
+			continue
+		}
+
+		// Treat the copy as the original page
+		page.finalURL = parsedURL
+		metadata := c.buildMetadata(page, parsedURL)
+		recordPageDebug(ctx, page)
+		if metadata.Title == "" && metadata.Description == "" && len(metadata.Images) == 0 {
+			continue
+		}
+
+		metadata.addWarning(WarningFallbackUsed, "page taken from %s (%s): %s", source.Name(), fallbackURL, cause)
+		return metadata, nil
+	}
+
+	if len(errs) == 0 {
+		return nil, errors.New("no fallback source had a usable copy")
+	}
+	return nil, errors.Join(errs...)
+}
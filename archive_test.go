@@ -0,0 +1,123 @@
+package urlmeta
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// testSource serves copies from a test server, or fails
+type testSource struct {
+	name string
+	base string
+	err  error
+}
+
+func (s *testSource) Name() string { return s.name }
+
+func (s *testSource) FallbackURL(_ context.Context, targetURL string) (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	return s.base + "/copy?u=" + url.QueryEscape(targetURL), nil
+}
+
+func TestWithFallbackSources(t *testing.T) {
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gone":
+			w.WriteHeader(http.StatusGone)
+		case "/empty":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html><head></head><body></body></html>"))
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html><head><title>Live</title></head></html>"))
+		}
+	}))
+	defer origin.Close()
+
+	archive := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Archived</title><meta property="og:image" content="/cover.jpg"></head></html>`))
+	}))
+	defer archive.Close()
+
+	client := NewClient(WithFallbackSources(
+		&testSource{name: "broken", err: errors.New("lookup failed")},
+		&testSource{name: "archive", base: archive.URL},
+	))
+
+	for _, path := range []string{"/gone", "/empty"} {
+		metadata, err := client.Extract(origin.URL + path)
+		if err != nil {
+			t.Fatalf("Extract(%s) failed: %v", path, err)
+		}
+		if metadata.Title != "Archived" || !metadata.HasWarning(WarningFallbackUsed) {
+			t.Errorf("%s: expected the archived copy, got %+v", path, metadata)
+		}
+		if metadata.URL != origin.URL+path {
+			t.Errorf("%s: expected the original URL, got %s", path, metadata.URL)
+		}
+		if len(metadata.Images) != 1 || metadata.Images[0].URL != origin.URL+"/cover.jpg" {
+			t.Errorf("%s: expected links resolved against the original URL, got %+v", path, metadata.Images)
+		}
+	}
+
+	metadata, err := client.Extract(origin.URL + "/live")
+	if err != nil || metadata.Title != "Live" || metadata.HasWarning(WarningFallbackUsed) {
+		t.Errorf("Expected the live page, got %+v (%v)", metadata, err)
+	}
+}
+
+func TestFallbackSourcesNotUsed(t *testing.T) {
+	var calls int
+	archive := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer archive.Close()
+
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer origin.Close()
+
+	// The primary error is kept when every fallback fails
+	client := NewClient(WithFallbackSources(&testSource{name: "archive", base: archive.URL}))
+	if _, err := client.Extract(origin.URL); !errors.Is(err, &ErrHTTPStatus{Code: http.StatusInternalServerError}) {
+		t.Errorf("Expected the primary error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected one fallback request, got %d", calls)
+	}
+
+	// Refused hosts are not fetched through a fallback
+	calls = 0
+	client = NewClient(WithBlockedHosts([]string{"blocked.example"}), WithFallbackSources(&testSource{name: "archive", base: archive.URL}))
+	if _, err := client.Extract("https://blocked.example/"); !errors.Is(err, ErrHostNotAllowed) || calls != 0 {
+		t.Errorf("Expected ErrHostNotAllowed without fallback, got %v (%d calls)", err, calls)
+	}
+}
+
+func TestMirrorFallback(t *testing.T) {
+	target := "https://example.com/a?b=c"
+	tests := []struct {
+		source FallbackSource
+		want   string
+	}{
+		{WaybackFallback(), "https://web.archive.org/web/2id_/https://example.com/a?b=c"},
+		{ArchiveTodayFallback(), "https://archive.ph/newest/https://example.com/a?b=c"},
+		{MirrorFallback("mirror", "https://mirror.internal/fetch?u={query}"), "https://mirror.internal/fetch?u=https%3A%2F%2Fexample.com%2Fa%3Fb%3Dc"},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.source.FallbackURL(context.Background(), target)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %q (%v), want %q", tt.source.Name(), got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// hostCredential is an Authorization value sent to matching hosts
+type hostCredential struct {
+	hosts []string
+	set   func(*http.Request)
+}
+
+// WithBasicAuth sends HTTP basic auth credentials to hosts matching hosts,
+// using the same pattern syntax as WithAllowedHosts (e.g. "wiki.corp" or
+// "*.corp.example.com"). Credentials are never sent to other hosts, such as
+// third-party image or oEmbed servers; with no hosts they are not sent at
+// all. When several credentials match a host, the first configured wins.
+func WithBasicAuth(user, pass string, hosts ...string) Option {
+	return func(c *Client) {
+		c.credentials = append(c.credentials, hostCredential{
+			hosts: normalizeHostPatterns(hosts),
+			set:   func(req *http.Request) { req.SetBasicAuth(user, pass) },
+		})
+	}
+}
+
+// WithBearerToken sends "Authorization: Bearer <token>" to hosts matching
+// hosts, with the same matching rules as WithBasicAuth
+func WithBearerToken(token string, hosts ...string) Option {
+	return func(c *Client) {
+		c.credentials = append(c.credentials, hostCredential{
+			hosts: normalizeHostPatterns(hosts),
+			set:   func(req *http.Request) { req.Header.Set("Authorization", "Bearer "+token) },
+		})
+	}
+}
+
+// applyCredentials adds the first matching credential to req unless it
+// already carries an Authorization header
+func (c *Client) applyCredentials(req *http.Request) {
+	if len(c.credentials) == 0 || req.Header.Get("Authorization") != "" {
+		return
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(req.URL.Hostname()), ".")
+	for _, cred := range c.credentials {
+		if matchHostPatterns(host, cred.hosts) {
+			cred.set(req)
+			return
+		}
+	}
+}
+
+// Credential holds the keys an oEmbed provider requires, added as query
+// parameters to its endpoint requests (see WithProviderCredentials)
+type Credential struct {
+	// AccessToken is sent as access_token, e.g. "<app-id>|<client-token>"
+	// for the Instagram and Facebook Graph endpoints
+	AccessToken string
+
+	// APIKey is sent as api_key
+	APIKey string
+
+	// Params holds any other parameters the provider expects
+	Params map[string]string
+}
+
+// WithProviderCredentials adds credentials to the oEmbed requests of the
+// providers named by the map keys (as in OEmbedProvider.Name, compared
+// case-insensitively), e.g. {"Instagram": {AccessToken: "..."}}.
+// Requests are matched to a provider by endpoint URL, so credentials also
+// apply to endpoints found through discovery, and are never sent to other
+// endpoints. Parameters already in the endpoint URL are kept.
+func WithProviderCredentials(credentials map[string]Credential) Option {
+	return func(c *Client) {
+		c.providerCredentials = make(map[string]Credential, len(credentials))
+		for name, cred := range credentials {
+			c.providerCredentials[strings.ToLower(name)] = cred
+		}
+	}
+}
+
+// applyProviderCredentials adds the credentials of the provider owning
+// endpoint to query and returns the values added, to be kept out of error
+// messages and debug output
+func (c *Client) applyProviderCredentials(endpoint *url.URL, query url.Values) []string {
+	if len(c.providerCredentials) == 0 {
+		return nil
+	}
+	name := providerForEndpoint(providerSnapshot(), endpoint)
+	if name == "" {
+		name = providerForEndpoint(c.refreshedProviders(), endpoint)
+	}
+	if name == "" {
+		return nil
+	}
+	cred, ok := c.providerCredentials[strings.ToLower(name)]
+	if !ok {
+		return nil
+	}
+
+	var secrets []string
+	setParam := func(key, value string) {
+		if value != "" && !query.Has(key) {
+			query.Set(key, value)
+			secrets = append(secrets, value)
+		}
+	}
+	setParam("access_token", cred.AccessToken)
+	setParam("api_key", cred.APIKey)
+	for key, value := range cred.Params {
+		setParam(key, value)
+	}
+	return secrets
+}
+
+// redactedError hides credentials in the message of an error whose text
+// includes a request URL, such as a *url.Error
+type redactedError struct {
+	err     error
+	secrets []string
+}
+
+// Error implements error
+func (r *redactedError) Error() string {
+	msg := r.err.Error()
+	for _, secret := range r.secrets {
+		msg = strings.ReplaceAll(msg, url.QueryEscape(secret), "REDACTED")
+		msg = strings.ReplaceAll(msg, secret, "REDACTED")
+	}
+	return msg
+}
+
+// Unwrap returns the original error
+func (r *redactedError) Unwrap() error {
+	return r.err
+}
+
+// redactSecrets returns err with secrets hidden from its message
+func redactSecrets(err error, secrets []string) error {
+	if err == nil || len(secrets) == 0 {
+		return err
+	}
+	return &redactedError{err: err, secrets: secrets}
+}
+
+// providerForEndpoint returns the name of the provider in providers with
+// an endpoint at the same scheme, host and path as endpoint
+func providerForEndpoint(providers []OEmbedProvider, endpoint *url.URL) string {
+	for _, provider := range providers {
+		for _, e := range provider.Endpoints {
+			u, err := url.Parse(e.URL)
+			if err == nil && strings.EqualFold(u.Scheme, endpoint.Scheme) &&
+				strings.EqualFold(u.Host, endpoint.Host) && u.Path == endpoint.Path {
+				return provider.Name
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,147 @@
+package urlmeta
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWithBasicAuthAndBearerToken(t *testing.T) {
+	var authorization atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization.Store(r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	host := mustParseURL(t, server.URL).Hostname()
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected string
+	}{
+		{
+			name:     "basic auth",
+			opts:     []Option{WithBasicAuth("alice", "s3cret", host)},
+			expected: "Basic YWxpY2U6czNjcmV0",
+		},
+		{
+			name:     "bearer token",
+			opts:     []Option{WithBearerToken("tok", "*.example.com", host)},
+			expected: "Bearer tok",
+		},
+		{
+			name:     "first match wins",
+			opts:     []Option{WithBearerToken("first", host), WithBearerToken("second", host)},
+			expected: "Bearer first",
+		},
+		{
+			name:     "other host",
+			opts:     []Option{WithBearerToken("tok", "wiki.corp")},
+			expected: "",
+		},
+		{
+			name:     "no hosts",
+			opts:     []Option{WithBasicAuth("alice", "s3cret")},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authorization.Store("")
+			if _, err := NewClient(tt.opts...).Extract(server.URL); err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+			if got := authorization.Load(); got != tt.expected {
+				t.Errorf("Expected Authorization %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestApplyCredentialsKeepsExplicitHeader(t *testing.T) {
+	client := NewClient(WithBearerToken("tok", "example.com"))
+
+	req := &http.Request{URL: &url.URL{Scheme: "https", Host: "EXAMPLE.com:443"}, Header: http.Header{}}
+	client.applyCredentials(req)
+	if got := req.Header.Get("Authorization"); got != "Bearer tok" {
+		t.Errorf("Expected bearer token for matching host, got %q", got)
+	}
+
+	req.Header.Set("Authorization", "Basic explicit")
+	client.applyCredentials(req)
+	if got := req.Header.Get("Authorization"); got != "Basic explicit" {
+		t.Errorf("Expected explicit Authorization to be kept, got %q", got)
+	}
+}
+
+func TestProviderCredentials(t *testing.T) {
+	restoreProviders(t)
+
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"type":"rich","version":"1.0","title":"Post"}`))
+	}))
+	defer server.Close()
+
+	AddCustomProvider(OEmbedProvider{
+		Name:      "Graph",
+		URL:       server.URL,
+		Endpoints: []OEmbedEndpoint{{Schemes: []string{"https://graph.example.com/p/*"}, URL: server.URL + "/instagram_oembed?fields=title"}},
+	})
+
+	client := NewClient(WithProviderCredentials(map[string]Credential{
+		"graph": {AccessToken: "app|token", Params: map[string]string{"fields": "ignored", "omitscript": "true"}},
+	}))
+	if _, err := client.ExtractOEmbed("https://graph.example.com/p/abc"); err != nil {
+		t.Fatalf("ExtractOEmbed failed: %v", err)
+	}
+	if query.Get("access_token") != "app|token" || query.Get("omitscript") != "true" {
+		t.Errorf("Expected credentials in the query, got %v", query)
+	}
+	if query.Get("fields") != "title" {
+		t.Errorf("Expected endpoint parameters to be kept, got %v", query)
+	}
+
+	// Other endpoints never see the credentials
+	query = nil
+	if _, err := client.fetchOEmbed(context.Background(), server.URL+"/other", "https://example.com/"); err != nil {
+		t.Fatalf("fetchOEmbed failed: %v", err)
+	}
+	if query.Has("access_token") {
+		t.Errorf("Expected no credentials for an unknown endpoint, got %v", query)
+	}
+}
+
+func TestProviderCredentialsRedacted(t *testing.T) {
+	restoreProviders(t)
+
+	AddCustomProvider(OEmbedProvider{
+		Name:      "Closed",
+		URL:       "http://127.0.0.1:1",
+		Endpoints: []OEmbedEndpoint{{Schemes: []string{"https://closed.example.com/*"}, URL: "http://127.0.0.1:1/oembed"}},
+	})
+
+	client := NewClient(WithProviderCredentials(map[string]Credential{"Closed": {APIKey: "s3cr3t/key"}}))
+	_, err := client.fetchOEmbed(context.Background(), "http://127.0.0.1:1/oembed", "https://closed.example.com/x")
+	if err == nil {
+		t.Fatal("Expected a connection error")
+	}
+	if strings.Contains(err.Error(), "s3cr3t") {
+		t.Errorf("Expected the API key to be redacted, got: %v", err)
+	}
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		t.Errorf("Expected the original error to be kept, got %T", err)
+	}
+}
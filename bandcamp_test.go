@@ -0,0 +1,123 @@
+package urlmeta
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func newBandcampServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		switch r.URL.Path {
+		case "/album/night-drive":
+			w.Write([]byte(`<html><head><title>Night Drive | Some Artist</title>
+				<meta property="og:title" content="Night Drive, by Some Artist">
+				<meta property="og:type" content="album">
+				<meta property="og:image" content="https://f4.bcbits.com/img/a123_5.jpg">
+				<meta property="og:video" content="https://bandcamp.com/EmbeddedPlayer/v=2/album=1234567890/size=large/tracklist=false/artwork=small/">
+				</head></html>`))
+		case "/track/intro":
+			w.Write([]byte(`<html><head><meta property="og:title" content="Intro &amp; Outro">
+				<meta property="og:video" content="https://bandcamp.com/EmbeddedPlayer/v=2/track=987654/size=large/"></head></html>`))
+		default:
+			w.Write([]byte(`<html><head><title>Merch</title></head></html>`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestBandcampMatch(t *testing.T) {
+	tests := map[string]bool{
+		"https://someartist.bandcamp.com/album/night-drive": true,
+		"https://someartist.bandcamp.com/track/intro":       true,
+		"https://someartist.bandcamp.com/merch":             false,
+		"https://bandcamp.com/album/night-drive":            false,
+		"https://example.com/album/night-drive":             false,
+	}
+
+	extractor := BandcampExtractor()
+	for rawURL, want := range tests {
+		u, _ := url.Parse(rawURL)
+		if got := extractor.Match(u); got != want {
+			t.Errorf("Match(%s) = %v, want %v", rawURL, got, want)
+		}
+	}
+}
+
+func TestBandcampExtractor(t *testing.T) {
+	server := newBandcampServer(t)
+	client := NewClient()
+	extractor := BandcampExtractor()
+
+	u, _ := url.Parse(server.URL + "/album/night-drive")
+	metadata, err := extractor.Extract(context.Background(), client.pipelineClient(), u)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	oembed := metadata.OEmbed
+	if oembed == nil {
+		t.Fatal("Expected a player embed")
+	}
+	if !strings.Contains(oembed.HTML, `src="https://bandcamp.com/EmbeddedPlayer/album=1234567890/size=large/`) {
+		t.Errorf("Unexpected embed HTML: %s", oembed.HTML)
+	}
+	if oembed.Height != bandcampEmbedHeight || oembed.ThumbnailURL != "https://f4.bcbits.com/img/a123_5.jpg" {
+		t.Errorf("Unexpected embed fields: %+v", oembed)
+	}
+	if metadata.BestTitle() != "Night Drive, by Some Artist" || metadata.ProviderName != "Bandcamp" {
+		t.Errorf("Expected the page metadata, got %q from %q", metadata.Title, metadata.ProviderName)
+	}
+
+	u, _ = url.Parse(server.URL + "/track/intro")
+	metadata, err = extractor.Extract(context.Background(), client.pipelineClient(), u)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if !strings.Contains(metadata.OEmbed.HTML, "/EmbeddedPlayer/track=987654/") || !strings.Contains(metadata.OEmbed.HTML, ">Intro &amp; Outro</a>") {
+		t.Errorf("Unexpected track embed HTML: %s", metadata.OEmbed.HTML)
+	}
+
+	u, _ = url.Parse(server.URL + "/album/no-player")
+	if _, err := extractor.Extract(context.Background(), client.pipelineClient(), u); err == nil {
+		t.Error("Expected a page without a player ID to fail the extractor")
+	}
+}
+
+func TestBandcampExtractorLeavesPostProcessing(t *testing.T) {
+	var imageRequests atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/album/night-drive", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<html><head><meta property="og:title" content="Night Drive">
+			<meta property="og:image" content="/cover.jpg">
+			<meta property="og:video" content="https://bandcamp.com/EmbeddedPlayer/v=2/album=1234567890/"></head></html>`))
+	})
+	mux.HandleFunc("/cover.jpg", func(w http.ResponseWriter, r *http.Request) {
+		imageRequests.Add(1)
+		w.Header().Set("Content-Type", "image/jpeg")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	// Image validation runs once in the pipeline, not in the extractor
+	client := NewClient(WithValidateImages(true))
+	u, _ := url.Parse(server.URL + "/album/night-drive")
+	metadata, err := BandcampExtractor().Extract(context.Background(), client.pipelineClient(), u)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(metadata.Images) != 1 {
+		t.Errorf("Expected the page image, got %+v", metadata.Images)
+	}
+	if n := imageRequests.Load(); n != 0 {
+		t.Errorf("Expected no image requests from the extractor, got %d", n)
+	}
+}
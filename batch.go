@@ -0,0 +1,304 @@
+package urlmeta
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Result holds the extraction outcome for a single URL in a batch
+type Result struct {
+	URL      string        `json:"url"`
+	Metadata *Metadata     `json:"metadata,omitempty"`
+	Error    error         `json:"-"`
+	Duration time.Duration `json:"duration"`
+
+	// Data is the value passed to BatchExtractor.Submit with the URL
+	Data any `json:"-"`
+
+	// DuplicateOf is the earlier input URL this one resolved to the same
+	// page as, with WithDeduplication; the result is shared with it
+	DuplicateOf string `json:"duplicate_of,omitempty"`
+}
+
+// batchConfig holds settings for a single ExtractAll call
+type batchConfig struct {
+	concurrency int
+	timeout     time.Duration
+	queueSize   int
+	dedupe      bool
+}
+
+// BatchOption is a function that configures an ExtractAll call
+type BatchOption func(*batchConfig)
+
+// WithConcurrency sets the number of worker goroutines (default: 4)
+func WithConcurrency(n int) BatchOption {
+	return func(b *batchConfig) {
+		if n > 0 {
+			b.concurrency = n
+		}
+	}
+}
+
+// WithPerURLTimeout bounds the time spent on each URL (default: no per-URL limit
+// beyond the client's HTTP timeout)
+func WithPerURLTimeout(timeout time.Duration) BatchOption {
+	return func(b *batchConfig) {
+		b.timeout = timeout
+	}
+}
+
+// WithQueueSize sets how many submitted URLs, and how many unread results,
+// a BatchExtractor buffers before Submit blocks (default: 100). Ignored by
+// ExtractAll.
+func WithQueueSize(n int) BatchOption {
+	return func(b *batchConfig) {
+		if n > 0 {
+			b.queueSize = n
+		}
+	}
+}
+
+// WithDeduplication makes ExtractAll fetch repeated input URLs once and
+// detect inputs that end up on the same page (same canonical URL, or same
+// final URL after redirects). Later duplicates share the first result and
+// name its input URL in Result.DuplicateOf. Disabled by default.
+func WithDeduplication(dedupe bool) BatchOption {
+	return func(b *batchConfig) {
+		b.dedupe = dedupe
+	}
+}
+
+// ExtractAll extracts metadata for all URLs using a bounded worker pool.
+// Results are returned in the same order as urls. Per-URL failures are
+// reported in Result.Error; the returned error is only non-nil when ctx
+// is cancelled or expires during the batch.
+func (c *Client) ExtractAll(ctx context.Context, urls []string, opts ...BatchOption) ([]Result, error) {
+	cfg := &batchConfig{
+		concurrency: 4,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	results := make([]Result, len(urls))
+	jobs := make(chan int)
+
+	// Indexes to fetch; with deduplication, repeated URLs are fetched once
+	pending := make([]int, 0, len(urls))
+	firstIndex := make(map[string]int, len(urls))
+	for i, u := range urls {
+		key := c.urlKey(u)
+		if _, seen := firstIndex[key]; seen && cfg.dedupe {
+			continue
+		}
+		firstIndex[key] = i
+		pending = append(pending, i)
+	}
+
+	workers := cfg.concurrency
+	if workers > len(pending) {
+		workers = len(pending)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				results[idx] = c.extractOne(ctx, urls[idx], cfg.timeout)
+			}
+		}()
+	}
+
+	var err error
+dispatch:
+	for n, i := range pending {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			err = ctx.Err()
+			for _, j := range pending[n:] {
+				results[j] = Result{URL: urls[j], Error: err}
+			}
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if cfg.dedupe {
+		c.deduplicateResults(urls, results, firstIndex)
+	}
+
+	if err == nil {
+		err = ctx.Err()
+	}
+
+	return results, err
+}
+
+// deduplicateResults fills the results of repeated input URLs from their
+// first occurrence, then marks results resolving to a page already seen
+// earlier in urls as duplicates of it
+func (c *Client) deduplicateResults(urls []string, results []Result, firstIndex map[string]int) {
+	pages := make(map[string]int, len(results))
+	for i := range results {
+		if first := firstIndex[c.urlKey(urls[i])]; first != i {
+			results[i] = results[first]
+			results[i].URL = urls[i]
+			results[i].DuplicateOf = urls[first]
+			continue
+		}
+
+		metadata := results[i].Metadata
+		if metadata == nil {
+			continue
+		}
+		keys := []string{c.urlKey(metadata.CanonicalURL), c.urlKey(metadata.URL)}
+		for _, key := range keys {
+			if first, seen := pages[key]; seen && key != "" {
+				results[i].Metadata = results[first].Metadata
+				results[i].DuplicateOf = urls[first]
+				break
+			}
+		}
+		if results[i].DuplicateOf == "" {
+			for _, key := range keys {
+				if key != "" {
+					pages[key] = i
+				}
+			}
+		}
+	}
+}
+
+// extractOne runs a single extraction for ExtractAll, applying the per-URL timeout
+func (c *Client) extractOne(ctx context.Context, targetURL string, timeout time.Duration) Result {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	metadata, err := c.ExtractContext(ctx, targetURL)
+
+	return Result{
+		URL:      targetURL,
+		Metadata: metadata,
+		Error:    err,
+		Duration: time.Since(start),
+	}
+}
+
+// BatchExtractor runs a long-lived worker pool fed with URLs one at a time,
+// for services extracting a continuous stream of links. Create it with
+// Client.NewBatchExtractor, Submit URLs, read Results, and call Wait once
+// done submitting.
+type BatchExtractor struct {
+	client  *Client
+	ctx     context.Context
+	cfg     *batchConfig
+	jobs    chan batchJob
+	results chan Result
+	wg      sync.WaitGroup
+	done    sync.Once
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// batchJob is a URL submitted to a BatchExtractor
+type batchJob struct {
+	url  string
+	data any
+}
+
+// NewBatchExtractor starts a worker pool using the batch options
+// (WithConcurrency, WithPerURLTimeout, WithQueueSize). Cancelling ctx
+// aborts work in progress; the pool keeps draining the queue, reporting
+// the cancellation error for each URL.
+func (c *Client) NewBatchExtractor(ctx context.Context, opts ...BatchOption) *BatchExtractor {
+	cfg := &batchConfig{
+		concurrency: 4,
+		queueSize:   100,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	b := &BatchExtractor{
+		client:  c,
+		ctx:     ctx,
+		cfg:     cfg,
+		jobs:    make(chan batchJob, cfg.queueSize),
+		results: make(chan Result, cfg.queueSize),
+	}
+
+	for i := 0; i < cfg.concurrency; i++ {
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			for job := range b.jobs {
+				result := c.extractOne(ctx, job.url, cfg.timeout)
+				result.Data = job.data
+				b.results <- result
+			}
+		}()
+	}
+
+	return b
+}
+
+// Submit queues targetURL, waiting while the queue is full. data is handed
+// back in Result.Data, e.g. to tie the result to a database row. It returns
+// ErrBatchClosed after Wait, or the context error once the extractor's
+// context is done.
+func (b *BatchExtractor) Submit(targetURL string, data any) error {
+	return b.SubmitContext(context.Background(), targetURL, data)
+}
+
+// SubmitContext is Submit, giving up when ctx ends while the queue is full
+func (b *BatchExtractor) SubmitContext(ctx context.Context, targetURL string, data any) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return ErrBatchClosed
+	}
+
+	select {
+	case b.jobs <- batchJob{url: targetURL, data: data}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-b.ctx.Done():
+		return b.ctx.Err()
+	}
+}
+
+// Results returns the channel delivering results in completion order. It
+// is closed once Wait has been called and every submitted URL is done.
+// Results must be read: workers block while WithQueueSize results are
+// pending.
+func (b *BatchExtractor) Results() <-chan Result {
+	return b.results
+}
+
+// Wait stops accepting URLs, waits for the submitted ones to finish and
+// closes the Results channel. Read Results from another goroutine, or
+// Wait may block on unread results.
+func (b *BatchExtractor) Wait() {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.jobs)
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+	b.done.Do(func() { close(b.results) })
+}
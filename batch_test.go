@@ -0,0 +1,254 @@
+package urlmeta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExtractAll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, "<html><head><title>%s</title></head></html>", r.URL.Path)
+	}))
+	defer server.Close()
+
+	urls := []string{
+		server.URL + "/one",
+		server.URL + "/missing",
+		server.URL + "/three",
+	}
+
+	client := NewClient()
+	results, err := client.ExtractAll(context.Background(), urls, WithConcurrency(2))
+	if err != nil {
+		t.Fatalf("ExtractAll failed: %v", err)
+	}
+
+	if len(results) != len(urls) {
+		t.Fatalf("Expected %d results, got %d", len(urls), len(results))
+	}
+
+	for i, result := range results {
+		if result.URL != urls[i] {
+			t.Errorf("Result %d: expected URL '%s', got '%s'", i, urls[i], result.URL)
+		}
+	}
+
+	if results[0].Error != nil || results[0].Metadata.Title != "/one" {
+		t.Errorf("Expected title '/one', got %+v", results[0])
+	}
+
+	if results[1].Error == nil {
+		t.Error("Expected error for 404 URL, got nil")
+	}
+
+	if results[2].Error != nil || results[2].Metadata.Title != "/three" {
+		t.Errorf("Expected title '/three', got %+v", results[2])
+	}
+}
+
+func TestExtractAllConcurrencyLimit(t *testing.T) {
+	var inFlight, peak int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	urls := make([]string, 8)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("%s/%d", server.URL, i)
+	}
+
+	client := NewClient()
+	if _, err := client.ExtractAll(context.Background(), urls, WithConcurrency(3)); err != nil {
+		t.Fatalf("ExtractAll failed: %v", err)
+	}
+
+	if peak > 3 {
+		t.Errorf("Expected at most 3 concurrent requests, got %d", peak)
+	}
+}
+
+func TestExtractAllPerURLTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	results, err := client.ExtractAll(context.Background(), []string{server.URL}, WithPerURLTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("ExtractAll failed: %v", err)
+	}
+
+	if results[0].Error == nil {
+		t.Error("Expected per-URL timeout error, got nil")
+	}
+}
+
+func TestExtractAllCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := NewClient()
+	results, err := client.ExtractAll(ctx, []string{"https://example.com", "https://example.org"})
+	if err == nil {
+		t.Error("Expected context error, got nil")
+	}
+
+	for _, result := range results {
+		if result.Error == nil {
+			t.Errorf("Expected error for %s after cancellation", result.URL)
+		}
+	}
+}
+
+func TestBatchExtractor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, "<html><head><title>%s</title></head></html>", r.URL.Path)
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	batch := client.NewBatchExtractor(context.Background(), WithConcurrency(2), WithQueueSize(1))
+
+	collected := make(chan map[int]Result)
+	go func() {
+		results := make(map[int]Result)
+		for r := range batch.Results() {
+			results[r.Data.(int)] = r
+		}
+		collected <- results
+	}()
+
+	paths := []string{"/one", "/missing", "/three", "/four"}
+	for i, path := range paths {
+		if err := batch.Submit(server.URL+path, i); err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+	}
+	batch.Wait()
+	results := <-collected
+
+	if len(results) != len(paths) {
+		t.Fatalf("Expected %d results, got %d", len(paths), len(results))
+	}
+	for i, path := range paths {
+		r := results[i]
+		if r.URL != server.URL+path {
+			t.Errorf("Result %d: expected URL %s, got %s", i, server.URL+path, r.URL)
+		}
+		if path == "/missing" {
+			if r.Error == nil {
+				t.Error("Expected error for 404 URL, got nil")
+			}
+		} else if r.Error != nil || r.Metadata.Title != path {
+			t.Errorf("Expected title %s, got %+v", path, r)
+		}
+	}
+
+	if err := batch.Submit(server.URL, nil); err != ErrBatchClosed {
+		t.Errorf("Expected ErrBatchClosed after Wait, got %v", err)
+	}
+	batch.Wait()
+}
+
+func TestBatchExtractorCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	batch := NewClient().NewBatchExtractor(ctx, WithConcurrency(1), WithQueueSize(1))
+
+	// Nobody reads results: the worker and the queue fill up
+	cancel()
+	for i := 0; i < 5; i++ {
+		if err := batch.Submit("https://example.com", nil); err != nil {
+			if err != context.Canceled {
+				t.Errorf("Expected context.Canceled, got %v", err)
+			}
+			return
+		}
+	}
+	t.Error("Expected Submit to fail once the context is cancelled and the queue is full")
+}
+
+func TestExtractAllDeduplication(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path == "/short" {
+			http.Redirect(w, r, "/article", http.StatusMovedPermanently)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		canonical := ""
+		if r.URL.Path == "/amp" {
+			canonical = `<link rel="canonical" href="/article">`
+		}
+		fmt.Fprintf(w, "<html><head><title>%s</title>%s</head></html>", r.URL.Path, canonical)
+	}))
+	defer server.Close()
+
+	urls := []string{
+		server.URL + "/article",
+		server.URL + "/short",
+		server.URL + "/article",
+		server.URL + "/amp",
+		server.URL + "/other",
+	}
+
+	results, err := NewClient().ExtractAll(context.Background(), urls, WithDeduplication(true))
+	if err != nil {
+		t.Fatalf("ExtractAll failed: %v", err)
+	}
+
+	wantDuplicateOf := []string{"", urls[0], urls[0], urls[0], ""}
+	for i, r := range results {
+		if r.URL != urls[i] {
+			t.Errorf("Result %d: expected URL %s, got %s", i, urls[i], r.URL)
+		}
+		if r.DuplicateOf != wantDuplicateOf[i] {
+			t.Errorf("Result %d: expected DuplicateOf %q, got %q", i, wantDuplicateOf[i], r.DuplicateOf)
+		}
+		if wantDuplicateOf[i] != "" && r.Metadata != results[0].Metadata {
+			t.Errorf("Result %d: expected the shared result", i)
+		}
+	}
+
+	// The repeated /article is fetched once; /short costs a redirect hop
+	if got := requests.Load(); got != 5 {
+		t.Errorf("Expected 5 requests, got %d", got)
+	}
+
+	results, _ = NewClient().ExtractAll(context.Background(), urls)
+	for i, r := range results {
+		if r.DuplicateOf != "" {
+			t.Errorf("Result %d: expected no deduplication by default, got %q", i, r.DuplicateOf)
+		}
+	}
+}
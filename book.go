@@ -0,0 +1,52 @@
+package urlmeta
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Book holds book:* Open Graph properties (og:type book)
+type Book struct {
+	Authors     []string `json:"authors,omitempty"` // author profile URLs or names
+	ISBN        string   `json:"isbn,omitempty"`
+	ReleaseDate string   `json:"release_date,omitempty"`
+}
+
+// book returns metadata.Book, allocating it on first use
+func (m *Metadata) book() *Book {
+	if m.Book == nil {
+		m.Book = &Book{}
+	}
+	return m.Book
+}
+
+// processBook handles the book: Open Graph namespace, reporting whether
+// property was one of them
+func processBook(property, content string, metadata *Metadata, baseURL *url.URL) bool {
+	switch property {
+	case "book:author":
+		b := metadata.book()
+		b.Authors = append(b.Authors, resolveProfileRef(content, baseURL))
+	case "book:isbn":
+		if b := metadata.book(); b.ISBN == "" {
+			b.ISBN = strings.ReplaceAll(content, "-", "")
+		}
+	case "book:release_date":
+		if b := metadata.book(); b.ReleaseDate == "" {
+			b.ReleaseDate = content
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// resolveProfileRef resolves a book:author reference. The spec calls for a
+// profile URL, but many sites put the author's name there instead, which
+// is kept as-is.
+func resolveProfileRef(content string, baseURL *url.URL) string {
+	if strings.HasPrefix(content, "/") || strings.Contains(content, "://") {
+		return resolveURL(content, baseURL)
+	}
+	return content
+}
@@ -0,0 +1,40 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestExtractBook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head>
+			<meta property="og:type" content="book">
+			<meta property="book:author" content="/authors/jane">
+			<meta property="book:author" content="John Smith">
+			<meta property="book:isbn" content="978-3-16-148410-0">
+			<meta property="book:release_date" content="2023-09-12">
+		</head></html>`))
+	}))
+	defer server.Close()
+
+	metadata, err := Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	expected := &Book{
+		Authors:     []string{server.URL + "/authors/jane", "John Smith"},
+		ISBN:        "9783161484100",
+		ReleaseDate: "2023-09-12",
+	}
+	if !reflect.DeepEqual(metadata.Book, expected) {
+		t.Errorf("Expected book %+v, got %+v", expected, metadata.Book)
+	}
+
+	if metadata.Profile != nil {
+		t.Errorf("Expected nil profile, got %+v", metadata.Profile)
+	}
+}
@@ -0,0 +1,55 @@
+package urlmeta
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Branding holds the colors and tile image a site asks browsers to use for
+// their chrome, useful for tinting preview cards
+type Branding struct {
+	ThemeColor    string `json:"theme_color,omitempty"`     // <meta name="theme-color">
+	TileColor     string `json:"tile_color,omitempty"`      // msapplication-TileColor
+	TileImage     string `json:"tile_image,omitempty"`      // msapplication-TileImage, resolved to an absolute URL
+	MaskIconColor string `json:"mask_icon_color,omitempty"` // color attribute of <link rel="mask-icon">
+}
+
+// branding returns metadata.Branding, allocating it on first use
+func (m *Metadata) branding() *Branding {
+	if m.Branding == nil {
+		m.Branding = &Branding{}
+	}
+	return m.Branding
+}
+
+// processBrandingMeta handles theme-color and msapplication-* meta tags.
+// The first value wins, so a media-specific theme-color declared after the
+// default one does not override it.
+func processBrandingMeta(name, content string, metadata *Metadata, baseURL *url.URL) {
+	switch strings.ToLower(name) {
+	case "theme-color":
+		if b := metadata.branding(); b.ThemeColor == "" {
+			b.ThemeColor = content
+		}
+	case "msapplication-tilecolor":
+		if b := metadata.branding(); b.TileColor == "" {
+			b.TileColor = content
+		}
+	case "msapplication-tileimage":
+		if b := metadata.branding(); b.TileImage == "" {
+			b.TileImage = resolveURL(content, baseURL)
+		}
+	}
+}
+
+// processBrandingLink records the color of a Safari pinned tab mask icon
+func processBrandingLink(rel, color string, metadata *Metadata) {
+	color = strings.TrimSpace(color)
+	if color == "" || !hasRelToken(rel, "mask-icon") {
+		return
+	}
+
+	if b := metadata.branding(); b.MaskIconColor == "" {
+		b.MaskIconColor = color
+	}
+}
@@ -0,0 +1,66 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const mockHTMLBranding = `
+<!DOCTYPE html>
+<html>
+<head>
+	<title>Branding Test</title>
+	<meta name="theme-color" content="#4285f4">
+	<meta name="theme-color" media="(prefers-color-scheme: dark)" content="#202124">
+	<meta name="msapplication-TileColor" content="#da532c">
+	<meta name="msapplication-TileImage" content="/mstile-144x144.png">
+	<link rel="mask-icon" href="/safari-pinned-tab.svg" color="#5bbad5">
+</head>
+<body></body>
+</html>
+`
+
+func TestExtractBranding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBranding))
+	}))
+	defer server.Close()
+
+	metadata, err := Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.Branding == nil {
+		t.Fatal("Expected branding to be extracted")
+	}
+
+	expected := Branding{
+		ThemeColor:    "#4285f4",
+		TileColor:     "#da532c",
+		TileImage:     server.URL + "/mstile-144x144.png",
+		MaskIconColor: "#5bbad5",
+	}
+	if *metadata.Branding != expected {
+		t.Errorf("Expected branding %+v, got %+v", expected, *metadata.Branding)
+	}
+}
+
+func TestExtractBrandingAbsent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	metadata, err := Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.Branding != nil {
+		t.Errorf("Expected nil branding, got %+v", metadata.Branding)
+	}
+}
@@ -0,0 +1,112 @@
+package urlmeta
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithCircuitBreaker stops sending requests to a host after threshold
+// consecutive failures, failing them immediately with ErrCircuitOpen for
+// the cooldown period. After the cooldown a single trial request is let
+// through: success closes the circuit, failure opens it for another
+// cooldown. Failures are the transient errors and responses WithRetry
+// would retry (timeouts, connection resets, 429 and 5xx); a request that
+// is retried counts once. Hosts are tracked by host:port. Disabled by
+// default.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		if threshold > 0 {
+			c.breaker = newCircuitBreaker(threshold, cooldown)
+		} else {
+			c.breaker = nil
+		}
+	}
+}
+
+// circuitBreaker tracks consecutive failures per host
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	hosts     map[string]*hostCircuit
+}
+
+// hostCircuit is the state of a single host; hosts without failures have
+// no entry
+type hostCircuit struct {
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// newCircuitBreaker returns a breaker with every circuit closed
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		hosts:     make(map[string]*hostCircuit),
+	}
+}
+
+// allow returns ErrCircuitOpen when req's host must not be contacted
+func (b *circuitBreaker) allow(req *http.Request) error {
+	host := breakerHost(req)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	hc := b.hosts[host]
+	if hc == nil || hc.failures < b.threshold {
+		return nil
+	}
+
+	if wait := time.Until(hc.openUntil); wait > 0 {
+		return fmt.Errorf("%w: %s failed %d times in a row, retry in %v", ErrCircuitOpen, host, hc.failures, wait.Round(time.Second))
+	}
+
+	// Cooldown is over: let one trial request through
+	if hc.probing {
+		return fmt.Errorf("%w: %s is being probed", ErrCircuitOpen, host)
+	}
+	hc.probing = true
+	return nil
+}
+
+// record updates the circuit of req's host with the outcome of req.
+// Outcomes that say nothing about the host's health, such as the caller's
+// context ending, leave the failure count alone.
+func (b *circuitBreaker) record(req *http.Request, resp *http.Response, err error) {
+	host := breakerHost(req)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	hc := b.hosts[host]
+
+	switch {
+	case req.Context().Err() != nil || (err != nil && !isTransientError(err)):
+		if hc != nil {
+			hc.probing = false
+		}
+	case err != nil || isRetryableStatus(resp.StatusCode):
+		if hc == nil {
+			hc = &hostCircuit{}
+			b.hosts[host] = hc
+		}
+		hc.failures++
+		hc.probing = false
+		if hc.failures >= b.threshold {
+			hc.openUntil = time.Now().Add(b.cooldown)
+		}
+	default:
+		delete(b.hosts, host)
+	}
+}
+
+// breakerHost returns the circuit key for req
+func breakerHost(req *http.Request) string {
+	return strings.ToLower(req.URL.Host)
+}
@@ -0,0 +1,126 @@
+package urlmeta
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithCircuitBreaker(t *testing.T) {
+	var healthy atomic.Bool
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithCircuitBreaker(2, 50*time.Millisecond), WithAutoOEmbed(false))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Extract(server.URL); !errors.Is(err, &ErrHTTPStatus{Code: http.StatusBadGateway}) {
+			t.Fatalf("Expected HTTP 502 error, got %v", err)
+		}
+	}
+
+	// Open: fails fast without contacting the host
+	if _, err := client.Extract(server.URL); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected 2 requests to reach the host, got %d", got)
+	}
+
+	// Failed trial request reopens the circuit
+	time.Sleep(60 * time.Millisecond)
+	if _, err := client.Extract(server.URL); errors.Is(err, ErrCircuitOpen) {
+		t.Fatal("Expected a trial request after the cooldown")
+	}
+	if _, err := client.Extract(server.URL); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen after failed trial, got %v", err)
+	}
+
+	// Successful trial request closes it
+	time.Sleep(60 * time.Millisecond)
+	healthy.Store(true)
+	for i := 0; i < 3; i++ {
+		if _, err := client.Extract(server.URL); err != nil {
+			t.Fatalf("Extract failed after recovery: %v", err)
+		}
+	}
+}
+
+func TestCircuitBreakerPerHost(t *testing.T) {
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer bad.Close()
+
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer good.Close()
+
+	client := NewClient(WithCircuitBreaker(1, time.Minute), WithAutoOEmbed(false))
+
+	client.Extract(bad.URL)
+	if _, err := client.Extract(bad.URL); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if _, err := client.Extract(good.URL); err != nil {
+		t.Errorf("Expected other hosts to be unaffected, got %v", err)
+	}
+}
+
+func TestCircuitBreakerIgnoresNonHostFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		ctx     func() (context.Context, context.CancelFunc)
+	}{
+		{
+			name:    "404",
+			handler: func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNotFound) },
+		},
+		{
+			name: "cancelled context",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(50 * time.Millisecond)
+			},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Millisecond)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			client := NewClient(WithCircuitBreaker(1, time.Minute), WithAutoOEmbed(false))
+			for i := 0; i < 3; i++ {
+				ctx, cancel := context.Background(), context.CancelFunc(func() {})
+				if tt.ctx != nil {
+					ctx, cancel = tt.ctx()
+				}
+				_, err := client.ExtractContext(ctx, server.URL)
+				cancel()
+				if errors.Is(err, ErrCircuitOpen) {
+					t.Fatalf("Expected circuit to stay closed, got %v", err)
+				}
+			}
+		})
+	}
+}
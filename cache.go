@@ -0,0 +1,197 @@
+package urlmeta
+
+import (
+	"container/list"
+	"context"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// CacheStats reports cache effectiveness for a Client
+type CacheStats struct {
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Entries int    `json:"entries"`
+}
+
+// HitRatio returns the fraction of lookups served from cache (0 when unused)
+func (s CacheStats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// CacheStore is the storage backend for extraction results.
+// Implementations must be safe for concurrent use. A ttl of zero means the
+// entry has no expiry of its own. Get must return a value the caller may
+// modify, such as a freshly decoded or copied one.
+type CacheStore interface {
+	Get(ctx context.Context, key string) (*Metadata, bool, error)
+	Set(ctx context.Context, key string, value *Metadata, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// MemoryCache is an in-process CacheStore that evicts the least recently
+// used entry once it holds more than its configured size. Values are
+// copied on Set and Get, so callers may modify the metadata they store or
+// receive without affecting the cache, as with stores that serialize.
+type MemoryCache struct {
+	mu      sync.Mutex
+	size    int
+	ll      *list.List
+	entries map[string]*list.Element
+}
+
+// memoryEntry is the value stored in each list element
+type memoryEntry struct {
+	key      string
+	value    *Metadata
+	expireAt time.Time
+}
+
+// NewMemoryCache creates a MemoryCache holding at most size entries
+func NewMemoryCache(size int) *MemoryCache {
+	if size <= 0 {
+		size = 1
+	}
+	return &MemoryCache{
+		size:    size,
+		ll:      list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Get returns the cached metadata for key, evicting it if expired
+func (m *MemoryCache) Get(_ context.Context, key string) (*Metadata, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := elem.Value.(*memoryEntry)
+	if !entry.expireAt.IsZero() && time.Now().After(entry.expireAt) {
+		m.removeElement(elem)
+		return nil, false, nil
+	}
+
+	m.ll.MoveToFront(elem)
+	return cloneMetadata(entry.value), true, nil
+}
+
+// Set stores metadata under key, evicting the least recently used entry when full
+func (m *MemoryCache) Set(_ context.Context, key string, value *Metadata, ttl time.Duration) error {
+	value = cloneMetadata(value)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var expireAt time.Time
+	if ttl > 0 {
+		expireAt = time.Now().Add(ttl)
+	}
+
+	if elem, ok := m.entries[key]; ok {
+		entry := elem.Value.(*memoryEntry)
+		entry.value = value
+		entry.expireAt = expireAt
+		m.ll.MoveToFront(elem)
+		return nil
+	}
+
+	elem := m.ll.PushFront(&memoryEntry{key: key, value: value, expireAt: expireAt})
+	m.entries[key] = elem
+
+	for m.ll.Len() > m.size {
+		m.removeElement(m.ll.Back())
+	}
+	return nil
+}
+
+// Delete removes key from the cache
+func (m *MemoryCache) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if elem, ok := m.entries[key]; ok {
+		m.removeElement(elem)
+	}
+	return nil
+}
+
+// Len returns the number of entries currently held (including expired ones not yet evicted)
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ll.Len()
+}
+
+// removeElement unlinks elem from both the list and the index; caller holds mu
+func (m *MemoryCache) removeElement(elem *list.Element) {
+	m.ll.Remove(elem)
+	delete(m.entries, elem.Value.(*memoryEntry).key)
+}
+
+// cloneMetadata returns a deep copy of m: no slice, map or pointer is
+// shared with it
+func cloneMetadata(m *Metadata) *Metadata {
+	if m == nil {
+		return nil
+	}
+	return deepCopy(reflect.ValueOf(m)).Interface().(*Metadata)
+}
+
+// deepCopy returns a copy of v sharing no slices, maps or pointers with
+// it. Unexported struct fields (such as time.Time's) are copied as is.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		dst := reflect.New(v.Type().Elem())
+		dst.Elem().Set(deepCopy(v.Elem()))
+		return dst
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		dst := reflect.New(v.Type()).Elem()
+		dst.Set(deepCopy(v.Elem()))
+		return dst
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		dst := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			dst.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return dst
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		dst := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			dst.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return dst
+	case reflect.Struct:
+		dst := reflect.New(v.Type()).Elem()
+		dst.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				dst.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return dst
+	}
+	return v
+}
@@ -0,0 +1,272 @@
+package urlmeta
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// RedisCache is a reference CacheStore backed by Redis. It speaks the RESP
+// protocol directly so the package stays free of third-party dependencies;
+// wrap your own client behind CacheStore if you need clustering or sentinel.
+type RedisCache struct {
+	addr        string
+	password    string
+	db          int
+	prefix      string
+	dialTimeout time.Duration
+	pool        chan *redisConn
+}
+
+// RedisOption is a function that configures a RedisCache
+type RedisOption func(*RedisCache)
+
+// WithRedisPassword sets the password sent with AUTH on each new connection
+func WithRedisPassword(password string) RedisOption {
+	return func(r *RedisCache) {
+		r.password = password
+	}
+}
+
+// WithRedisDB selects the logical database (default: 0)
+func WithRedisDB(db int) RedisOption {
+	return func(r *RedisCache) {
+		r.db = db
+	}
+}
+
+// WithRedisKeyPrefix sets the prefix prepended to every key (default: "urlmeta:")
+func WithRedisKeyPrefix(prefix string) RedisOption {
+	return func(r *RedisCache) {
+		r.prefix = prefix
+	}
+}
+
+// WithRedisPoolSize sets the number of idle connections kept open (default: 8)
+func WithRedisPoolSize(size int) RedisOption {
+	return func(r *RedisCache) {
+		if size > 0 {
+			r.pool = make(chan *redisConn, size)
+		}
+	}
+}
+
+// NewRedisCache creates a RedisCache for the server at addr (host:port).
+// Connections are opened lazily on first use.
+func NewRedisCache(addr string, opts ...RedisOption) *RedisCache {
+	r := &RedisCache{
+		addr:        addr,
+		prefix:      "urlmeta:",
+		dialTimeout: 5 * time.Second,
+		pool:        make(chan *redisConn, 8),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Get fetches and decodes the metadata stored under key
+func (r *RedisCache) Get(ctx context.Context, key string) (*Metadata, bool, error) {
+	reply, err := r.do(ctx, "GET", r.prefix+key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	data, ok := reply.([]byte)
+	if !ok {
+		// Nil bulk string: key does not exist
+		return nil, false, nil
+	}
+
+	var metadata Metadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, false, fmt.Errorf("failed to decode cached metadata: %w", err)
+	}
+
+	return &metadata, true, nil
+}
+
+// Set encodes metadata as JSON and stores it under key with the given ttl
+func (r *RedisCache) Set(ctx context.Context, key string, value *Metadata, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata: %w", err)
+	}
+
+	args := []string{"SET", r.prefix + key, string(data)}
+	if ttl > 0 {
+		// Round up, as Redis rejects PX 0
+		ms := (ttl + time.Millisecond - 1) / time.Millisecond
+		args = append(args, "PX", strconv.FormatInt(int64(ms), 10))
+	}
+
+	_, err = r.do(ctx, args...)
+	return err
+}
+
+// Delete removes key from Redis
+func (r *RedisCache) Delete(ctx context.Context, key string) error {
+	_, err := r.do(ctx, "DEL", r.prefix+key)
+	return err
+}
+
+// Close closes all idle pooled connections
+func (r *RedisCache) Close() error {
+	for {
+		select {
+		case conn := <-r.pool:
+			_ = conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// do runs a single command on a pooled connection
+func (r *RedisCache) do(ctx context.Context, args ...string) (interface{}, error) {
+	conn, err := r.getConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else {
+		_ = conn.SetDeadline(time.Time{})
+	}
+
+	reply, err := conn.command(args...)
+	if err != nil {
+		var redisErr redisError
+		if !errors.As(err, &redisErr) {
+			// Connection state is unknown after an I/O error, so drop it
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+
+	r.putConn(conn)
+	return reply, err
+}
+
+// getConn returns an idle pooled connection or dials a new one
+func (r *RedisCache) getConn(ctx context.Context) (*redisConn, error) {
+	select {
+	case conn := <-r.pool:
+		return conn, nil
+	default:
+	}
+
+	dialer := net.Dialer{Timeout: r.dialTimeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", r.addr)
+	if err != nil {
+		return nil, fmt.Errorf("redis dial failed: %w", err)
+	}
+
+	conn := &redisConn{Conn: netConn, reader: bufio.NewReader(netConn)}
+
+	if r.password != "" {
+		if _, err := conn.command("AUTH", r.password); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("redis auth failed: %w", err)
+		}
+	}
+
+	if r.db != 0 {
+		if _, err := conn.command("SELECT", strconv.Itoa(r.db)); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("redis select failed: %w", err)
+		}
+	}
+
+	return conn, nil
+}
+
+// putConn returns conn to the pool, closing it when the pool is full
+func (r *RedisCache) putConn(conn *redisConn) {
+	select {
+	case r.pool <- conn:
+	default:
+		_ = conn.Close()
+	}
+}
+
+// redisError is an error reply (-ERR ...) returned by the server
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// redisConn is a single RESP connection
+type redisConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// command writes args as a RESP array and reads one reply
+func (c *redisConn) command(args ...string) (interface{}, error) {
+	buf := make([]byte, 0, 64)
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, '\r', '\n')
+	for _, arg := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, arg...)
+		buf = append(buf, '\r', '\n')
+	}
+
+	if _, err := c.Write(buf); err != nil {
+		return nil, err
+	}
+
+	return c.readReply()
+}
+
+// readReply parses a single RESP reply. Bulk strings are returned as []byte,
+// nil bulk strings as nil, integers as int64 and simple strings as string.
+func (c *redisConn) readReply() (interface{}, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 {
+		return nil, fmt.Errorf("redis: malformed reply %q", line)
+	}
+	line = line[:len(line)-2]
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed bulk length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(c.reader, data); err != nil {
+			return nil, err
+		}
+		return data[:n], nil
+	default:
+		return nil, fmt.Errorf("redis: unsupported reply type %q", line[0])
+	}
+}
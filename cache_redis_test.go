@@ -0,0 +1,211 @@
+package urlmeta
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis is a minimal in-memory RESP server supporting GET/SET/DEL/AUTH/SELECT
+type fakeRedis struct {
+	listener net.Listener
+	mu       sync.Mutex
+	data     map[string]string
+	ttls     map[string]string
+	password string
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	f := &fakeRedis{
+		listener: listener,
+		data:     make(map[string]string),
+		ttls:     make(map[string]string),
+		password: password,
+	}
+	go f.serve()
+	return f
+}
+
+func (f *fakeRedis) Addr() string { return f.listener.Addr().String() }
+
+func (f *fakeRedis) Close() { f.listener.Close() }
+
+func (f *fakeRedis) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	authed := f.password == ""
+
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		cmd := strings.ToUpper(args[0])
+		if !authed && cmd != "AUTH" {
+			fmt.Fprint(conn, "-NOAUTH Authentication required.\r\n")
+			continue
+		}
+
+		f.mu.Lock()
+		switch cmd {
+		case "AUTH":
+			if args[1] == f.password {
+				authed = true
+				fmt.Fprint(conn, "+OK\r\n")
+			} else {
+				fmt.Fprint(conn, "-WRONGPASS invalid password\r\n")
+			}
+		case "SELECT":
+			fmt.Fprint(conn, "+OK\r\n")
+		case "SET":
+			f.data[args[1]] = args[2]
+			if len(args) == 5 {
+				f.ttls[args[1]] = args[4]
+			}
+			fmt.Fprint(conn, "+OK\r\n")
+		case "GET":
+			if v, ok := f.data[args[1]]; ok {
+				fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(v), v)
+			} else {
+				fmt.Fprint(conn, "$-1\r\n")
+			}
+		case "DEL":
+			_, ok := f.data[args[1]]
+			delete(f.data, args[1])
+			if ok {
+				fmt.Fprint(conn, ":1\r\n")
+			} else {
+				fmt.Fprint(conn, ":0\r\n")
+			}
+		default:
+			fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", cmd)
+		}
+		f.mu.Unlock()
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+
+	args := make([]string, n)
+	for i := range args {
+		line, err = r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func TestRedisCache(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	defer server.Close()
+
+	ctx := context.Background()
+	cache := NewRedisCache(server.Addr(), WithRedisPassword("secret"), WithRedisKeyPrefix("test:"))
+	defer cache.Close()
+
+	if _, ok, err := cache.Get(ctx, "missing"); err != nil || ok {
+		t.Errorf("Expected miss for unknown key, got ok=%v err=%v", ok, err)
+	}
+
+	metadata := &Metadata{
+		Title:    "Cached Title",
+		Images:   []Image{{URL: "https://example.com/a.jpg", Width: 100}},
+		Keywords: []string{"a", "b"},
+	}
+
+	if err := cache.Set(ctx, "page", metadata, 90*time.Second); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	server.mu.Lock()
+	ttl := server.ttls["test:page"]
+	server.mu.Unlock()
+	if ttl != "90000" {
+		t.Errorf("Expected PX 90000, got '%s'", ttl)
+	}
+
+	// Sub-millisecond TTLs round up instead of sending PX 0
+	for ttl, want := range map[time.Duration]string{500 * time.Microsecond: "1", 1500 * time.Microsecond: "2"} {
+		if err := cache.Set(ctx, "short", metadata, ttl); err != nil {
+			t.Fatalf("Set with %v failed: %v", ttl, err)
+		}
+		server.mu.Lock()
+		got := server.ttls["test:short"]
+		server.mu.Unlock()
+		if got != want {
+			t.Errorf("Expected PX %s for %v, got '%s'", want, ttl, got)
+		}
+	}
+
+	got, ok, err := cache.Get(ctx, "page")
+	if err != nil || !ok {
+		t.Fatalf("Expected hit, got ok=%v err=%v", ok, err)
+	}
+
+	if got.Title != "Cached Title" || len(got.Images) != 1 || got.Images[0].Width != 100 {
+		t.Errorf("Decoded metadata mismatch: %+v", got)
+	}
+
+	if err := cache.Delete(ctx, "page"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	if _, ok, _ := cache.Get(ctx, "page"); ok {
+		t.Error("Expected miss after delete")
+	}
+}
+
+func TestRedisCacheWrongPassword(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	defer server.Close()
+
+	cache := NewRedisCache(server.Addr(), WithRedisPassword("wrong"))
+	defer cache.Close()
+
+	if _, _, err := cache.Get(context.Background(), "key"); err == nil {
+		t.Error("Expected auth error, got nil")
+	}
+}
+
+func TestRedisCacheUnreachable(t *testing.T) {
+	cache := NewRedisCache("127.0.0.1:1")
+
+	if _, _, err := cache.Get(context.Background(), "key"); err == nil {
+		t.Error("Expected dial error, got nil")
+	}
+}
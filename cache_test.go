@@ -0,0 +1,273 @@
+package urlmeta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientWithCache(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithCache(10, time.Minute))
+
+	for i := 0; i < 3; i++ {
+		metadata, err := client.Extract(server.URL)
+		if err != nil {
+			t.Fatalf("Extract failed: %v", err)
+		}
+		if metadata.Title != "Test Page Title" {
+			t.Errorf("Expected title 'Test Page Title', got '%s'", metadata.Title)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected 1 HTTP request, got %d", requests)
+	}
+
+	stats := client.CacheStats()
+	if stats.Hits != 2 || stats.Misses != 1 {
+		t.Errorf("Expected 2 hits and 1 miss, got %+v", stats)
+	}
+
+	if stats.Entries != 1 {
+		t.Errorf("Expected 1 cache entry, got %d", stats.Entries)
+	}
+}
+
+func TestClientCacheSkipsErrors(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithCache(10, time.Minute))
+	client.Extract(server.URL)
+	client.Extract(server.URL)
+
+	if requests != 2 {
+		t.Errorf("Expected failed results not to be cached, got %d requests", requests)
+	}
+}
+
+func TestMemoryCacheEviction(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(2)
+
+	cache.Set(ctx, "a", &Metadata{Title: "a"}, 0)
+	cache.Set(ctx, "b", &Metadata{Title: "b"}, 0)
+
+	// Touch "a" so that "b" becomes least recently used
+	cache.Get(ctx, "a")
+	cache.Set(ctx, "c", &Metadata{Title: "c"}, 0)
+
+	if _, ok, _ := cache.Get(ctx, "b"); ok {
+		t.Error("Expected 'b' to be evicted")
+	}
+
+	for _, key := range []string{"a", "c"} {
+		if _, ok, _ := cache.Get(ctx, key); !ok {
+			t.Errorf("Expected '%s' to be cached", key)
+		}
+	}
+}
+
+func TestMemoryCacheExpiry(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(10)
+	cache.Set(ctx, "key", &Metadata{Title: "value"}, 20*time.Millisecond)
+
+	if _, ok, _ := cache.Get(ctx, "key"); !ok {
+		t.Fatal("Expected entry before expiry")
+	}
+
+	time.Sleep(40 * time.Millisecond)
+
+	if _, ok, _ := cache.Get(ctx, "key"); ok {
+		t.Error("Expected entry to expire")
+	}
+
+	if cache.Len() != 0 {
+		t.Errorf("Expected expired entry to be removed, got %d entries", cache.Len())
+	}
+}
+
+func TestMemoryCacheDelete(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(10)
+	cache.Set(ctx, "key", &Metadata{Title: "value"}, 0)
+
+	if err := cache.Delete(ctx, "key"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	if _, ok, _ := cache.Get(ctx, "key"); ok {
+		t.Error("Expected entry to be deleted")
+	}
+}
+
+func TestMemoryCacheCopiesValues(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(10)
+
+	published := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	stored := &Metadata{
+		Title:       "value",
+		Images:      []Image{{URL: "https://example.com/a.png"}},
+		PublishedAt: &published,
+		OEmbed:      &OEmbed{Title: "embed"},
+		Microdata:   []MicrodataItem{{Properties: map[string][]interface{}{"name": {"x"}}}},
+	}
+	cache.Set(ctx, "key", stored, 0)
+	stored.Title = "changed after Set"
+
+	got, _, _ := cache.Get(ctx, "key")
+	got.Title = "trimmed"
+	got.Images[0].URL = "https://example.com/b.png"
+	got.Images = append(got.Images, Image{URL: "https://example.com/c.png"})
+	got.OEmbed.Title = "edited"
+	got.Microdata[0].Properties["name"][0] = "y"
+	*got.PublishedAt = time.Time{}
+
+	again, _, _ := cache.Get(ctx, "key")
+	if again.Title != "value" || len(again.Images) != 1 || again.Images[0].URL != "https://example.com/a.png" {
+		t.Errorf("Expected the cached entry to be unaffected by callers, got %+v", again)
+	}
+	if again.OEmbed.Title != "embed" || again.Microdata[0].Properties["name"][0] != "x" || !again.PublishedAt.Equal(published) {
+		t.Error("Expected nested values to be copied too")
+	}
+}
+
+// recordingStore is a CacheStore that records the TTL it was given
+type recordingStore struct {
+	*MemoryCache
+	lastTTL time.Duration
+}
+
+func (r *recordingStore) Set(ctx context.Context, key string, value *Metadata, ttl time.Duration) error {
+	r.lastTTL = ttl
+	return r.MemoryCache.Set(ctx, key, value, ttl)
+}
+
+func TestClientWithCacheStore(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	store := &recordingStore{MemoryCache: NewMemoryCache(10)}
+	client := NewClient(WithCacheStore(store), WithCacheTTL(time.Hour))
+
+	if _, err := client.Extract(server.URL); err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if store.lastTTL != time.Hour {
+		t.Errorf("Expected TTL 1h, got %v", store.lastTTL)
+	}
+
+	if _, ok, _ := store.Get(context.Background(), server.URL); !ok {
+		t.Error("Expected result in custom store")
+	}
+}
+
+func TestCacheHonorsOEmbedCacheAge(t *testing.T) {
+	store := &recordingStore{MemoryCache: NewMemoryCache(10)}
+	client := NewClient(WithCacheStore(store), WithCacheTTL(time.Hour))
+
+	tests := []struct {
+		name     string
+		metadata *Metadata
+		expected time.Duration
+	}{
+		{"no oEmbed", &Metadata{}, time.Hour},
+		{"oEmbed without cache_age", &Metadata{OEmbed: &OEmbed{}}, time.Hour},
+		{"oEmbed with cache_age", &Metadata{OEmbed: &OEmbed{CacheAge: 300}}, 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ttl := client.effectiveCacheTTL(tt.metadata); ttl != tt.expected {
+				t.Errorf("Expected TTL %v, got %v", tt.expected, ttl)
+			}
+		})
+	}
+}
+
+func TestCacheExpiresAt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithCache(10, time.Minute))
+	before := time.Now()
+
+	metadata, err := client.Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.CacheExpiresAt == nil {
+		t.Fatal("Expected CacheExpiresAt to be set")
+	}
+
+	if metadata.CacheExpiresAt.Before(before.Add(time.Minute)) {
+		t.Errorf("Expected expiry at least 1m from now, got %v", metadata.CacheExpiresAt)
+	}
+
+	uncached, err := NewClient().Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if uncached.CacheExpiresAt != nil {
+		t.Error("Expected CacheExpiresAt to be nil without cache")
+	}
+}
+
+func TestCacheStatsHitRatio(t *testing.T) {
+	tests := []struct {
+		stats    CacheStats
+		expected float64
+	}{
+		{CacheStats{}, 0},
+		{CacheStats{Hits: 3, Misses: 1}, 0.75},
+		{CacheStats{Misses: 4}, 0},
+	}
+
+	for _, tt := range tests {
+		if ratio := tt.stats.HitRatio(); ratio != tt.expected {
+			t.Errorf("HitRatio(%+v) = %v, expected %v", tt.stats, ratio, tt.expected)
+		}
+	}
+}
+
+func BenchmarkMemoryCacheGet(b *testing.B) {
+	ctx := context.Background()
+	cache := NewMemoryCache(1000)
+	for i := 0; i < 1000; i++ {
+		cache.Set(ctx, fmt.Sprintf("https://example.com/%d", i), &Metadata{}, time.Minute)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.Get(ctx, fmt.Sprintf("https://example.com/%d", i%1000))
+	}
+}
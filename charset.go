@@ -0,0 +1,82 @@
+package urlmeta
+
+import (
+	"bytes"
+	"io"
+
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/transform"
+)
+
+// charsetPeekSize is how much of a page is examined for a BOM or a
+// <meta charset>, as in the HTML spec's prescan
+const charsetPeekSize = 1024
+
+// charsetReader converts an HTML body to UTF-8 using, in order, a byte
+// order mark, the charset parameter of contentType and a <meta> charset
+// declaration. Undeclared pages are read as UTF-8, except when the start of
+// the page is not valid UTF-8, which suggests windows-1252.
+func charsetReader(r io.Reader, contentType string) io.Reader {
+	prefix, err := readPrefix(r)
+
+	var body io.Reader
+	switch err {
+	case nil:
+		body = io.MultiReader(bytes.NewReader(prefix), r)
+	case io.EOF:
+		body = bytes.NewReader(prefix)
+	default:
+		body = io.MultiReader(bytes.NewReader(prefix), &errReader{err: err})
+	}
+
+	enc, name, certain := charset.DetermineEncoding(prefix, contentType)
+	if enc == nil || name == "utf-8" {
+		return body
+	}
+
+	// DetermineEncoding falls back to windows-1252 for undeclared pages
+	// starting with plain ASCII; UTF-8 is the far better guess there
+	if !certain && name == "windows-1252" && isASCII(prefix) && indexFold(prefix, "charset") < 0 {
+		return body
+	}
+
+	return transform.NewReader(body, enc.NewDecoder())
+}
+
+// readPrefix reads up to charsetPeekSize bytes from r, stopping early once
+// the head has ended so a slowly streamed page isn't waited on (see
+// WithHeadOnly)
+func readPrefix(r io.Reader) ([]byte, error) {
+	prefix := make([]byte, 0, charsetPeekSize)
+	for len(prefix) < charsetPeekSize {
+		n, err := r.Read(prefix[len(prefix):cap(prefix)])
+		prefix = prefix[:len(prefix)+n]
+		if err != nil {
+			return prefix, err
+		}
+		if indexFold(prefix, "</head") >= 0 || indexFold(prefix, "<body") >= 0 {
+			break
+		}
+	}
+	return prefix, nil
+}
+
+// errReader fails every read with err
+type errReader struct {
+	err error
+}
+
+// Read implements io.Reader
+func (e *errReader) Read([]byte) (int, error) {
+	return 0, e.err
+}
+
+// isASCII reports whether b holds only 7-bit bytes
+func isASCII(b []byte) bool {
+	for _, c := range b {
+		if c >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package urlmeta
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+var errStalled = errors.New("stalled")
+
+func TestCharsetReader(t *testing.T) {
+	asciiPrefix := "<html><head><title>" + strings.Repeat("a", charsetPeekSize) + "</title></head><body>"
+
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		want        string
+	}{
+		{"utf-8 header", "<p>Café</p>", "text/html; charset=utf-8", "<p>Café</p>"},
+		{"latin-1 header", "<p>Caf\xe9</p>", "text/html; charset=ISO-8859-1", "<p>Café</p>"},
+		{"meta charset", `<meta charset="windows-1251"><p>` + "\xcf\xf0\xe8\xe2\xe5\xf2</p>", "text/html", `<meta charset="windows-1251"><p>Привет</p>`},
+		{"header wins over meta", `<meta charset="utf-8"><p>Caf` + "\xe9</p>", "text/html; charset=latin1", `<meta charset="utf-8"><p>Café</p>`},
+		{"undeclared utf-8", "<p>Café</p>", "text/html", "<p>Café</p>"},
+		{"undeclared utf-8 after ascii prefix", asciiPrefix + "Café", "text/html", asciiPrefix + "Café"},
+		{"undeclared legacy", "<p>Caf\xe9</p>", "", "<p>Café</p>"},
+		{"unknown charset", "<p>Café</p>", "text/html; charset=x-unknown", "<p>Café</p>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := io.ReadAll(charsetReader(strings.NewReader(tt.body), tt.contentType))
+			if err != nil {
+				t.Fatalf("ReadAll failed: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCharsetReaderStopsAtHead(t *testing.T) {
+	// A reader that fails if read past the first chunk, like a stalled stream
+	r := io.MultiReader(strings.NewReader("<html><head><title>x</title></head><body>"), &errReader{err: errStalled})
+
+	body := charsetReader(r, "text/html")
+	buf := make([]byte, 64)
+	n, err := body.Read(buf)
+	if err != nil || !strings.HasSuffix(string(buf[:n]), "<body>") {
+		t.Errorf("Expected the head without reading further, got %q (%v)", buf[:n], err)
+	}
+}
+
+func TestCharsetReaderError(t *testing.T) {
+	r := io.MultiReader(strings.NewReader("<p>partial"), &errReader{err: errStalled})
+
+	got, err := io.ReadAll(charsetReader(r, "text/html"))
+	if err != errStalled || string(got) != "<p>partial" {
+		t.Errorf("Expected the partial body then the read error, got %q (%v)", got, err)
+	}
+}
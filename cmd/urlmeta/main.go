@@ -0,0 +1,321 @@
+// Command urlmeta extracts link metadata from the command line.
+//
+//	urlmeta extract https://example.com
+//	urlmeta batch -f urls.txt -c 8 -o ndjson
+//
+// Both commands accept -timeout, -user-agent, -oembed=false to skip oEmbed
+// lookups and -o to choose the output format: json, ndjson or table. batch
+// reads one URL per line from -f (stdin when "-" or omitted), skipping blank
+// lines and lines starting with "#", and writes results in input order. The
+// exit status is 1 when any extraction failed.
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alfarisi/urlmeta"
+)
+
+const usage = `Usage:
+  urlmeta extract [flags] <url>
+  urlmeta batch [flags] [-f urls.txt]
+
+Run "urlmeta <command> -h" for the flags of a command.
+`
+
+// Output formats
+const (
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+	formatTable  = "table"
+)
+
+// options holds the flags shared by the commands
+type options struct {
+	timeout   time.Duration
+	userAgent string
+	oembed    bool
+	format    string
+}
+
+// record is the output for one URL
+type record struct {
+	URL      string            `json:"url"`
+	Metadata *urlmeta.Metadata `json:"metadata,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	Duration time.Duration     `json:"duration"`
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command in args and returns the exit status
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	var err error
+	switch args[0] {
+	case "extract":
+		err = runExtract(ctx, args[1:], stdout, stderr)
+	case "batch":
+		err = runBatch(ctx, args[1:], stdin, stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "urlmeta: unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	default:
+		fmt.Fprintf(stderr, "urlmeta: %v\n", err)
+		return 1
+	}
+}
+
+// errUsage reports bad arguments, already explained on stderr
+var errUsage = errors.New("usage error")
+
+// errFailed reports that some URLs of a batch failed; details are in the
+// output
+var errFailed = errors.New("some extractions failed")
+
+// newFlagSet returns a flag set for command with the shared flags bound
+// to opts
+func newFlagSet(command string, opts *options, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("urlmeta "+command, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.DurationVar(&opts.timeout, "timeout", 10*time.Second, "timeout per URL")
+	fs.StringVar(&opts.userAgent, "user-agent", "", "User-Agent to send (default: the urlmeta User-Agent)")
+	fs.BoolVar(&opts.oembed, "oembed", true, "look up oEmbed data")
+	return fs
+}
+
+// parseFlags parses args, reporting bad flags (already explained on
+// stderr by fs) as errUsage
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return errUsage
+	}
+	return err
+}
+
+// checkFormat validates opts.format
+func checkFormat(opts options, stderr io.Writer) error {
+	switch opts.format {
+	case formatJSON, formatNDJSON, formatTable:
+		return nil
+	}
+	fmt.Fprintf(stderr, "urlmeta: unknown output format %q (want json, ndjson or table)\n", opts.format)
+	return errUsage
+}
+
+// newClient creates a client for opts
+func newClient(opts options) *urlmeta.Client {
+	clientOpts := []urlmeta.Option{
+		urlmeta.WithTimeout(opts.timeout),
+	}
+	if !opts.oembed {
+		clientOpts = append(clientOpts, urlmeta.WithStrategy(urlmeta.StrategyHTMLOnly))
+	}
+	if opts.userAgent != "" {
+		clientOpts = append(clientOpts, urlmeta.WithUserAgent(opts.userAgent))
+	}
+	return urlmeta.NewClient(clientOpts...)
+}
+
+// runExtract implements "urlmeta extract <url>"
+func runExtract(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var opts options
+	fs := newFlagSet("extract", &opts, stderr)
+	fs.StringVar(&opts.format, "o", formatJSON, "output format: json, ndjson or table")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(stderr, "urlmeta extract: expected exactly one URL")
+		return errUsage
+	}
+	if err := checkFormat(opts, stderr); err != nil {
+		return err
+	}
+
+	metadata, err := newClient(opts).ExtractContext(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	switch opts.format {
+	case formatTable:
+		return writeMetadataTable(stdout, metadata)
+	case formatNDJSON:
+		return json.NewEncoder(stdout).Encode(metadata)
+	default:
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(metadata)
+	}
+}
+
+// runBatch implements "urlmeta batch"
+func runBatch(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var (
+		opts        options
+		file        string
+		concurrency int
+	)
+	fs := newFlagSet("batch", &opts, stderr)
+	fs.StringVar(&opts.format, "o", formatNDJSON, "output format: json, ndjson or table")
+	fs.StringVar(&file, "f", "-", `file with one URL per line ("-" for stdin)`)
+	fs.IntVar(&concurrency, "c", 4, "URLs processed concurrently")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkFormat(opts, stderr); err != nil {
+		return err
+	}
+
+	input := stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		input = f
+	}
+
+	urls, err := readURLs(input)
+	if err != nil {
+		return err
+	}
+	urls = append(urls, fs.Args()...)
+
+	results, err := newClient(opts).ExtractAll(ctx, urls,
+		urlmeta.WithConcurrency(concurrency),
+		urlmeta.WithPerURLTimeout(opts.timeout),
+	)
+	if err != nil {
+		return err
+	}
+
+	records := make([]record, len(results))
+	failed := false
+	for i, result := range results {
+		records[i] = record{URL: result.URL, Metadata: result.Metadata, Duration: result.Duration}
+		if result.Error != nil {
+			records[i].Error = result.Error.Error()
+			failed = true
+		}
+	}
+
+	if err := writeRecords(stdout, opts.format, records); err != nil {
+		return err
+	}
+	if failed {
+		return errFailed
+	}
+	return nil
+}
+
+// readURLs reads one URL per line, skipping blank lines and # comments
+func readURLs(r io.Reader) ([]string, error) {
+	var urls []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	return urls, scanner.Err()
+}
+
+// writeRecords writes batch results in format
+func writeRecords(w io.Writer, format string, records []record) error {
+	switch format {
+	case formatTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "URL\tSTATUS\tTITLE\tDURATION")
+		for _, r := range records {
+			status, title := "ok", ""
+			if r.Error != "" {
+				status, title = "error", r.Error
+			} else if r.Metadata != nil {
+				title = r.Metadata.BestTitle()
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.URL, status, title, r.Duration.Round(time.Millisecond))
+		}
+		return tw.Flush()
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	default:
+		enc := json.NewEncoder(w)
+		for _, r := range records {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// writeMetadataTable writes the main fields of metadata as a two-column
+// table, leaving out empty ones
+func writeMetadataTable(w io.Writer, metadata *urlmeta.Metadata) error {
+	rows := [][2]string{
+		{"Title", metadata.BestTitle()},
+		{"Description", metadata.BestDescription()},
+		{"URL", metadata.URL},
+		{"Canonical URL", metadata.CanonicalURL},
+		{"Type", metadata.Type},
+		{"Site", metadata.SiteName},
+		{"Provider", metadata.ProviderName},
+		{"Author", metadata.Author},
+		{"Published", metadata.PublishedTime},
+		{"Language", metadata.Language},
+	}
+	if img := metadata.BestImage(urlmeta.ImageCriteria{}); img != nil {
+		rows = append(rows, [2]string{"Image", img.URL})
+	}
+	if metadata.OEmbed != nil {
+		rows = append(rows, [2]string{"oEmbed", metadata.OEmbed.Type})
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		if row[1] != "" {
+			fmt.Fprintf(tw, "%s\t%s\n", row[0], row[1])
+		}
+	}
+	return tw.Flush()
+}
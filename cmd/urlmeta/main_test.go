@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Page ` + r.URL.Path + `</title></head></html>`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func runCommand(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestExtract(t *testing.T) {
+	server := newTestServer(t)
+
+	code, out, errOut := runCommand("", "extract", server.URL+"/a")
+	if code != 0 {
+		t.Fatalf("Expected success, got %d: %s", code, errOut)
+	}
+	var metadata struct{ Title string }
+	if err := json.Unmarshal([]byte(out), &metadata); err != nil || metadata.Title != "Page /a" {
+		t.Errorf("Expected the metadata as JSON, got %s (%v)", out, err)
+	}
+
+	code, out, _ = runCommand("", "extract", "-o", "table", "-oembed=false", server.URL+"/a")
+	if code != 0 || !strings.Contains(out, "Title") || !strings.Contains(out, "Page /a") {
+		t.Errorf("Expected a table, got %d: %s", code, out)
+	}
+
+	if code, _, errOut := runCommand("", "extract", server.URL+"/missing"); code != 1 || !strings.Contains(errOut, "404") {
+		t.Errorf("Expected a failure, got %d: %s", code, errOut)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	server := newTestServer(t)
+	input := "# pages\n" + server.URL + "/a\n\n" + server.URL + "/missing\n" + server.URL + "/b\n"
+
+	code, out, _ := runCommand(input, "batch", "-c", "2", "-o", "ndjson")
+	if code != 1 {
+		t.Errorf("Expected exit status 1 with a failed URL, got %d", code)
+	}
+
+	var records []record
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		var r record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("Invalid NDJSON line %q: %v", scanner.Text(), err)
+		}
+		records = append(records, r)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %d: %s", len(records), out)
+	}
+	if records[0].Metadata == nil || records[0].Metadata.Title != "Page /a" || records[2].Metadata.Title != "Page /b" {
+		t.Errorf("Expected results in input order, got %+v", records)
+	}
+	if records[1].Error == "" || records[1].Metadata != nil {
+		t.Errorf("Expected an error record, got %+v", records[1])
+	}
+
+	code, out, _ = runCommand(server.URL+"/a\n", "batch", "-o", "table")
+	if code != 0 || !strings.Contains(out, "STATUS") || !strings.Contains(out, "Page /a") {
+		t.Errorf("Expected a table, got %d: %s", code, out)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	tests := []struct {
+		args []string
+		code int
+	}{
+		{nil, 2},
+		{[]string{"unknown"}, 2},
+		{[]string{"extract"}, 2},
+		{[]string{"extract", "-o", "xml", "https://example.com"}, 2},
+		{[]string{"batch", "-bogus"}, 2},
+		{[]string{"extract", "-h"}, 0},
+		{[]string{"help"}, 0},
+	}
+
+	for _, tt := range tests {
+		if code, _, _ := runCommand("", tt.args...); code != tt.code {
+			t.Errorf("run(%q) = %d, want %d", tt.args, code, tt.code)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+// Command urlmetad serves the urlmeta extraction API over HTTP.
+//
+//	urlmetad -addr :8080 -cache redis -redis-addr localhost:6379
+//	curl 'localhost:8080/extract?url=https://example.com'
+//
+// Every flag can also be set from the environment as URLMETAD_<FLAG>, with
+// dashes as underscores (e.g. URLMETAD_REDIS_ADDR); flags win over the
+// environment. The server answers GET /extract (see package httpapi) and
+// GET /healthz, plus GET /metrics with -metrics. SIGINT and SIGTERM stop
+// accepting connections and wait for in-flight requests to finish.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/alfarisi/urlmeta"
+	"github.com/alfarisi/urlmeta/httpapi"
+	"github.com/alfarisi/urlmeta/prommetrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// envPrefix prefixes the environment variables mirroring the flags
+const envPrefix = "URLMETAD_"
+
+// config holds the server settings
+type config struct {
+	addr            string
+	shutdownTimeout time.Duration
+	metrics         bool
+
+	timeout   time.Duration
+	userAgent string
+	maxAge    time.Duration
+
+	cache         string
+	cacheSize     int
+	cacheTTL      time.Duration
+	redisAddr     string
+	redisPassword string
+	redisDB       int
+
+	rateLimit     float64
+	rateBurst     int
+	maxConcurrent int
+
+	ssrfProtection bool
+	allowedHosts   string
+	blockedHosts   string
+}
+
+func main() {
+	logger := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+
+	cfg, err := parseConfig(os.Args[1:], os.Getenv)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if err := run(cfg, logger); err != nil {
+		logger.Error("urlmetad: exiting", "error", err)
+		os.Exit(1)
+	}
+}
+
+// parseConfig reads the settings from args, falling back to the
+// environment looked up with getenv
+func parseConfig(args []string, getenv func(string) string) (config, error) {
+	var cfg config
+	fs := flag.NewFlagSet("urlmetad", flag.ContinueOnError)
+
+	fs.StringVar(&cfg.addr, "addr", ":8080", "listen address")
+	fs.DurationVar(&cfg.shutdownTimeout, "shutdown-timeout", 15*time.Second, "how long to wait for in-flight requests on shutdown")
+	fs.BoolVar(&cfg.metrics, "metrics", false, "serve Prometheus metrics on /metrics")
+
+	fs.DurationVar(&cfg.timeout, "timeout", 10*time.Second, "extraction timeout")
+	fs.StringVar(&cfg.userAgent, "user-agent", "", "User-Agent sent to sites (default: the urlmeta User-Agent)")
+	fs.DurationVar(&cfg.maxAge, "max-age", 0, "Cache-Control max-age of successful responses (0: no header)")
+
+	fs.StringVar(&cfg.cache, "cache", "memory", "result cache: memory, redis or none")
+	fs.IntVar(&cfg.cacheSize, "cache-size", 10000, "entries kept by the memory cache")
+	fs.DurationVar(&cfg.cacheTTL, "cache-ttl", time.Hour, "how long results are cached")
+	fs.StringVar(&cfg.redisAddr, "redis-addr", "localhost:6379", "Redis address (host:port)")
+	fs.StringVar(&cfg.redisPassword, "redis-password", "", "Redis password (prefer the environment variable)")
+	fs.IntVar(&cfg.redisDB, "redis-db", 0, "Redis database")
+
+	fs.Float64Var(&cfg.rateLimit, "rate-limit", 0, "outbound requests per second (0: unlimited)")
+	fs.IntVar(&cfg.rateBurst, "rate-burst", 10, "outbound request burst")
+	fs.IntVar(&cfg.maxConcurrent, "max-concurrent", 0, "outbound requests in flight (0: unlimited)")
+
+	fs.BoolVar(&cfg.ssrfProtection, "ssrf-protection", true, "refuse to connect to internal addresses")
+	fs.StringVar(&cfg.allowedHosts, "allowed-hosts", "", "comma-separated hosts that may be fetched, e.g. *.example.com")
+	fs.StringVar(&cfg.blockedHosts, "blocked-hosts", "", "comma-separated hosts that may not be fetched")
+
+	// Environment first, so flags given on the command line override it
+	var envErr error
+	fs.VisitAll(func(f *flag.Flag) {
+		name := envPrefix + strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+		if value := getenv(name); value != "" && envErr == nil {
+			if err := fs.Set(f.Name, value); err != nil {
+				envErr = fmt.Errorf("invalid %s: %w", name, err)
+			}
+		}
+	})
+	if envErr != nil {
+		return cfg, envErr
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+
+	switch cfg.cache {
+	case "memory", "redis", "none":
+	default:
+		return cfg, fmt.Errorf("unknown cache %q (want memory, redis or none)", cfg.cache)
+	}
+
+	return cfg, nil
+}
+
+// newClient creates the extraction client for cfg
+func newClient(cfg config, logger *slog.Logger) *urlmeta.Client {
+	opts := []urlmeta.Option{
+		urlmeta.WithTimeout(cfg.timeout),
+		urlmeta.WithLogger(logger),
+		urlmeta.WithSSRFProtection(cfg.ssrfProtection),
+		urlmeta.WithRateLimit(cfg.rateLimit, cfg.rateBurst),
+		urlmeta.WithMaxConcurrentRequests(cfg.maxConcurrent),
+	}
+	if cfg.userAgent != "" {
+		opts = append(opts, urlmeta.WithUserAgent(cfg.userAgent))
+	}
+	if hosts := splitList(cfg.allowedHosts); len(hosts) > 0 {
+		opts = append(opts, urlmeta.WithAllowedHosts(hosts))
+	}
+	if hosts := splitList(cfg.blockedHosts); len(hosts) > 0 {
+		opts = append(opts, urlmeta.WithBlockedHosts(hosts))
+	}
+
+	switch cfg.cache {
+	case "memory":
+		opts = append(opts, urlmeta.WithCache(cfg.cacheSize, cfg.cacheTTL))
+	case "redis":
+		opts = append(opts,
+			urlmeta.WithCacheStore(urlmeta.NewRedisCache(cfg.redisAddr,
+				urlmeta.WithRedisPassword(cfg.redisPassword),
+				urlmeta.WithRedisDB(cfg.redisDB),
+			)),
+			urlmeta.WithCacheTTL(cfg.cacheTTL),
+		)
+	}
+
+	if cfg.metrics {
+		opts = append(opts, urlmeta.WithMetrics(prommetrics.New(prometheus.DefaultRegisterer)))
+	}
+
+	return urlmeta.NewClient(opts...)
+}
+
+// newMux routes the API, health check and metrics endpoints
+func newMux(cfg config, client *urlmeta.Client) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/", httpapi.NewHandler(client, httpapi.Options{
+		Timeout: cfg.timeout,
+		MaxAge:  cfg.maxAge,
+	}))
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok\n"))
+	})
+	if cfg.metrics {
+		mux.Handle("GET /metrics", promhttp.Handler())
+	}
+	return mux
+}
+
+// run serves until SIGINT or SIGTERM, then shuts down gracefully
+func run(cfg config, logger *slog.Logger) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := &http.Server{
+		Addr:              cfg.addr,
+		Handler:           newMux(cfg, newClient(cfg, logger)),
+		ReadHeaderTimeout: 10 * time.Second,
+		// Leave room for the extraction itself
+		WriteTimeout: cfg.timeout + 10*time.Second,
+		ErrorLog:     slog.NewLogLogger(logger.Handler(), slog.LevelWarn),
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		logger.Info("urlmetad: listening", "addr", cfg.addr, "cache", cfg.cache)
+		errc <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Info("urlmetad: shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.shutdownTimeout)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
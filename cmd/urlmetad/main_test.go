@@ -0,0 +1,80 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseConfig(t *testing.T) {
+	env := map[string]string{
+		"URLMETAD_ADDR":            ":9000",
+		"URLMETAD_CACHE":           "redis",
+		"URLMETAD_REDIS_ADDR":      "redis:6379",
+		"URLMETAD_RATE_LIMIT":      "5",
+		"URLMETAD_CACHE_TTL":       "30m",
+		"URLMETAD_SSRF_PROTECTION": "false",
+	}
+
+	cfg, err := parseConfig([]string{"-addr", ":9001", "-timeout", "3s"}, func(key string) string { return env[key] })
+	if err != nil {
+		t.Fatalf("parseConfig failed: %v", err)
+	}
+
+	if cfg.addr != ":9001" {
+		t.Errorf("Expected the flag to override the environment, got %s", cfg.addr)
+	}
+	if cfg.cache != "redis" || cfg.redisAddr != "redis:6379" || cfg.cacheTTL != 30*time.Minute {
+		t.Errorf("Expected cache settings from the environment, got %+v", cfg)
+	}
+	if cfg.rateLimit != 5 || cfg.ssrfProtection || cfg.timeout != 3*time.Second {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	noEnv := func(string) string { return "" }
+
+	if _, err := parseConfig([]string{"-cache", "disk"}, noEnv); err == nil {
+		t.Error("Expected an error for an unknown cache")
+	}
+	if _, err := parseConfig(nil, func(key string) string {
+		if key == "URLMETAD_TIMEOUT" {
+			return "soon"
+		}
+		return ""
+	}); err == nil {
+		t.Error("Expected an error for an invalid environment value")
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	got := splitList(" a.com, ,*.b.com,")
+	if want := []string{"a.com", "*.b.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestMux(t *testing.T) {
+	cfg, err := parseConfig([]string{"-cache", "none"}, func(string) string { return "" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := newMux(cfg, newClient(cfg, slog.New(slog.NewTextHandler(io.Discard, nil))))
+
+	for path, status := range map[string]int{
+		"/healthz": http.StatusOK,
+		"/extract": http.StatusBadRequest, // missing url
+		"/metrics": http.StatusNotFound,
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != status {
+			t.Errorf("GET %s: expected %d, got %d", path, status, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package urlmeta
+
+import (
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// readingWordsPerMinute is the average adult silent reading speed used to
+// estimate ReadingTime
+const readingWordsPerMinute = 200
+
+// nonContentElements are skipped when counting words: they hold code,
+// navigation or page chrome rather than the main text
+var nonContentElements = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Svg:      true,
+	atom.Nav:      true,
+	atom.Header:   true,
+	atom.Footer:   true,
+	atom.Aside:    true,
+	atom.Form:     true,
+}
+
+// WithContentAnalysis counts the words in the page's main text and fills
+// Metadata.WordCount and Metadata.ReadingTime. The main text is the first
+// <article>, else <main>, else <body>, ignoring scripts, navigation,
+// headers, footers and sidebars. Has no effect with WithHeadOnly, since the
+// body is never read.
+func WithContentAnalysis(enabled bool) Option {
+	return func(c *Client) {
+		c.contentAnalysis = enabled
+	}
+}
+
+// analyzeContent sets WordCount and ReadingTime from the document's main text
+func analyzeContent(doc *html.Node, metadata *Metadata) {
+	root := findElement(doc, atom.Article)
+	if root == nil {
+		root = findElement(doc, atom.Main)
+	}
+	if root == nil {
+		root = findElement(doc, atom.Body)
+	}
+	if root == nil {
+		return
+	}
+
+	metadata.WordCount = countWords(root)
+	metadata.ReadingTime = estimateReadingTime(metadata.WordCount)
+}
+
+// findElement returns the first element of type a in document order
+func findElement(n *html.Node, a atom.Atom) *html.Node {
+	return findNode(n, func(n *html.Node) bool { return n.DataAtom == a })
+}
+
+// countWords counts whitespace-separated words in the text beneath n
+func countWords(n *html.Node) int {
+	switch n.Type {
+	case html.TextNode:
+		return len(strings.Fields(n.Data))
+	case html.ElementNode:
+		if nonContentElements[n.DataAtom] {
+			return 0
+		}
+	}
+
+	count := 0
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		count += countWords(c)
+	}
+	return count
+}
+
+// estimateReadingTime converts a word count to a reading time, rounded to
+// the second
+func estimateReadingTime(words int) time.Duration {
+	return (time.Duration(words) * time.Minute / readingWordsPerMinute).Round(time.Second)
+}
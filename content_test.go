@@ -0,0 +1,92 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+func TestContentAnalysis(t *testing.T) {
+	body := strings.Repeat("word ", 400)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Article</title></head><body>
+			<nav>Home About Contact</nav>
+			<article><p>` + body + `</p><script>var ignored = "not counted";</script></article>
+			<footer>Copyright notice here</footer>
+		</body></html>`))
+	}))
+	defer server.Close()
+
+	metadata, err := NewClient(WithContentAnalysis(true)).Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.WordCount != 400 {
+		t.Errorf("Expected 400 words, got %d", metadata.WordCount)
+	}
+
+	if metadata.ReadingTime != 2*time.Minute {
+		t.Errorf("Expected reading time 2m, got %v", metadata.ReadingTime)
+	}
+
+	plain, err := Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if plain.WordCount != 0 || plain.ReadingTime != 0 {
+		t.Errorf("Expected no content analysis by default, got %d words", plain.WordCount)
+	}
+}
+
+func TestAnalyzeContentRoots(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected int
+	}{
+		{"article preferred", `<body>one two <main>three <article>four five</article></main></body>`, 2},
+		{"main fallback", `<body>one two <main>three four five</main></body>`, 3},
+		{"body fallback", `<body><header>skip me</header>one two three<aside>and me</aside></body>`, 3},
+		{"empty body", `<body></body>`, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := html.Parse(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+
+			metadata := &Metadata{}
+			analyzeContent(doc, metadata)
+
+			if metadata.WordCount != tt.expected {
+				t.Errorf("Expected %d words, got %d", tt.expected, metadata.WordCount)
+			}
+		})
+	}
+}
+
+func TestEstimateReadingTime(t *testing.T) {
+	tests := []struct {
+		words    int
+		expected time.Duration
+	}{
+		{0, 0},
+		{100, 30 * time.Second},
+		{1000, 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := estimateReadingTime(tt.words); got != tt.expected {
+			t.Errorf("estimateReadingTime(%d) = %v, expected %v", tt.words, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+)
+
+// presetCookies are cookies to seed the jar with for a URL
+type presetCookies struct {
+	u       *url.URL
+	cookies []*http.Cookie
+}
+
+// WithCookieJar stores cookies set by responses and sends them on later
+// requests, so consent walls and region gates that set a cookie and
+// redirect can be traversed. The jar is shared by every call on the
+// client. No cookies are kept by default.
+func WithCookieJar(jar http.CookieJar) Option {
+	return func(c *Client) {
+		c.cookieJar = jar
+	}
+}
+
+// WithCookies sends cookies to rawURL (and wherever the jar's rules allow,
+// e.g. subpaths), such as a pre-accepted consent cookie. A cookie jar is
+// created if none was set with WithCookieJar. Invalid URLs are ignored.
+func WithCookies(rawURL string, cookies []*http.Cookie) Option {
+	return func(c *Client) {
+		u, err := url.Parse(normalizeURL(rawURL))
+		if err != nil || u.Host == "" {
+			return
+		}
+		c.cookies = append(c.cookies, presetCookies{u: u, cookies: cookies})
+	}
+}
+
+// configureCookies installs the cookie jar on the HTTP client and seeds it
+func (c *Client) configureCookies() {
+	if len(c.cookies) > 0 && c.cookieJar == nil {
+		// cookiejar.New only fails on invalid options
+		c.cookieJar, _ = cookiejar.New(nil)
+	}
+	if c.cookieJar == nil {
+		return
+	}
+
+	for _, preset := range c.cookies {
+		c.cookieJar.SetCookies(preset.u, preset.cookies)
+	}
+	c.httpClient.Jar = c.cookieJar
+}
@@ -0,0 +1,64 @@
+package urlmeta
+
+import (
+	"errors"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"testing"
+)
+
+// consentWall redirects to itself after setting a consent cookie until
+// the cookie is sent back
+func consentWall() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("consent"); err != nil {
+			http.SetCookie(w, &http.Cookie{Name: "consent", Value: "yes", Path: "/"})
+			http.Redirect(w, r, r.URL.Path, http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+}
+
+func TestWithCookieJar(t *testing.T) {
+	server := consentWall()
+	defer server.Close()
+
+	if _, err := NewClient().Extract(server.URL); !errors.Is(err, ErrTooManyRedirects) {
+		t.Fatalf("Expected ErrTooManyRedirects without a jar, got %v", err)
+	}
+
+	jar, _ := cookiejar.New(nil)
+	metadata, err := NewClient(WithCookieJar(jar)).Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if metadata.Title != "Test Page Title" {
+		t.Errorf("Expected the real page behind the consent wall, got title %q", metadata.Title)
+	}
+}
+
+func TestWithCookies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c, err := r.Cookie("region"); err != nil || c.Value != "us" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithCookies(server.URL, []*http.Cookie{{Name: "region", Value: "us"}}))
+	if _, err := client.Extract(server.URL + "/article"); err != nil {
+		t.Errorf("Extract failed: %v", err)
+	}
+
+	// Cookies are scoped to their host
+	other := NewClient(WithCookies("https://example.com", []*http.Cookie{{Name: "region", Value: "us"}}))
+	if _, err := other.Extract(server.URL); !errors.Is(err, &ErrHTTPStatus{Code: http.StatusForbidden}) {
+		t.Errorf("Expected HTTP 403 error, got %v", err)
+	}
+}
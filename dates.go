@@ -0,0 +1,247 @@
+package urlmeta
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// DateSource identifies where PublishedTime was found, from most to least
+// reliable
+type DateSource string
+
+const (
+	// DateSourceAPI is a date reported by a site's API (see SiteExtractor)
+	DateSourceAPI DateSource = "api"
+	// DateSourceOpenGraph is article:published_time
+	DateSourceOpenGraph DateSource = "og"
+	// DateSourceJSONLD is datePublished in a JSON-LD block
+	DateSourceJSONLD DateSource = "json-ld"
+	// DateSourceMicrodata is an itemprop="datePublished" value
+	DateSourceMicrodata DateSource = "microdata"
+	// DateSourceMeta is a conventional meta name such as "date" or "sailthru.date"
+	DateSourceMeta DateSource = "meta"
+	// DateSourceTimeElement is the datetime attribute of a <time> element
+	DateSourceTimeElement DateSource = "time"
+	// DateSourceURL is a /yyyy/mm/dd/ pattern in the URL path
+	DateSourceURL DateSource = "url"
+)
+
+// publishedDateMetaNames are meta names (lowercased) commonly carrying the
+// publication date, in order of preference
+var publishedDateMetaNames = []string{
+	"article:published_time",
+	"article.published",
+	"parsely-pub-date",
+	"sailthru.date",
+	"citation_publication_date",
+	"dc.date.issued",
+	"dcterms.created",
+	"dc.date",
+	"publish-date",
+	"publish_date",
+	"publishdate",
+	"pubdate",
+	"date",
+}
+
+// urlDatePattern matches /2024/05/12/ style dates in a URL path
+var urlDatePattern = regexp.MustCompile(`(?:^|/)((?:19|20)\d{2})[/-](0[1-9]|1[0-2])[/-](0[1-9]|[12]\d|3[01])(?:[/.-]|$)`)
+
+// detectPublishedTime fills PublishedTime and PublishedTimeSource, trying
+// each source from most to least reliable
+func detectPublishedTime(doc *html.Node, metadata *Metadata, pageURL *url.URL) {
+	if metadata.PublishedTime != "" {
+		metadata.PublishedTimeSource = DateSourceOpenGraph
+		return
+	}
+
+	candidates := []struct {
+		source DateSource
+		find   func() string
+	}{
+		{DateSourceJSONLD, func() string { return jsonLDString(doc, "datePublished") }},
+		{DateSourceMicrodata, func() string { return microdataDatePublished(doc, metadata.Microdata) }},
+		{DateSourceMeta, func() string { return metaPublishedDate(doc) }},
+		{DateSourceTimeElement, func() string { return timeElementDate(doc) }},
+		{DateSourceURL, func() string { return urlPathDate(pageURL) }},
+	}
+
+	for _, candidate := range candidates {
+		if value := strings.TrimSpace(candidate.find()); value != "" {
+			metadata.PublishedTime = value
+			metadata.PublishedTimeSource = candidate.source
+			return
+		}
+	}
+}
+
+// jsonLDString returns the first string value of key found anywhere in the
+// page's JSON-LD blocks, including nested objects and @graph arrays
+func jsonLDString(doc *html.Node, key string) string {
+	var found string
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if found != "" {
+			return
+		}
+		if n.Type == html.ElementNode && n.DataAtom == atom.Script &&
+			strings.EqualFold(strings.TrimSpace(getAttr(n, "type")), "application/ld+json") {
+			var data interface{}
+			if err := json.Unmarshal([]byte(textContent(n)), &data); err == nil {
+				found = findJSONString(data, key)
+			}
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	return found
+}
+
+// findJSONString searches decoded JSON depth-first for a string under key
+func findJSONString(v interface{}, key string) string {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if s, ok := v[key].(string); ok && s != "" {
+			return s
+		}
+		for _, child := range v {
+			if s := findJSONString(child, key); s != "" {
+				return s
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if s := findJSONString(child, key); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// microdataDatePublished returns datePublished from microdata items, or
+// from a bare itemprop="datePublished" outside any itemscope
+func microdataDatePublished(doc *html.Node, items []MicrodataItem) string {
+	var search func(items []MicrodataItem) string
+	search = func(items []MicrodataItem) string {
+		for i := range items {
+			if s := items[i].String("datePublished"); s != "" {
+				return s
+			}
+			for _, values := range items[i].Properties {
+				for _, v := range values {
+					if nested, ok := v.(*MicrodataItem); ok {
+						if s := search([]MicrodataItem{*nested}); s != "" {
+							return s
+						}
+					}
+				}
+			}
+		}
+		return ""
+	}
+	if s := search(items); s != "" {
+		return s
+	}
+
+	if n := findNode(doc, func(n *html.Node) bool {
+		return hasToken(getAttr(n, "itemprop"), "datePublished")
+	}); n != nil {
+		return microdataValue(n, nil)
+	}
+	return ""
+}
+
+// metaPublishedDate returns the content of the most preferred date meta name
+func metaPublishedDate(doc *html.Node) string {
+	values := make(map[string]string)
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.DataAtom == atom.Meta {
+			name := strings.ToLower(strings.TrimSpace(getAttr(n, "name")))
+			if name == "" {
+				name = strings.ToLower(strings.TrimSpace(getAttr(n, "property")))
+			}
+			if _, seen := values[name]; !seen && name != "" {
+				values[name] = strings.TrimSpace(getAttr(n, "content"))
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	for _, name := range publishedDateMetaNames {
+		if v := values[name]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// timeElementDate returns the datetime of a <time> marked as the publication
+// date (pubdate attribute or a "published" class), else of the first <time>
+func timeElementDate(doc *html.Node) string {
+	isTime := func(n *html.Node) bool {
+		return n.DataAtom == atom.Time && strings.TrimSpace(getAttr(n, "datetime")) != ""
+	}
+
+	if n := findNode(doc, func(n *html.Node) bool {
+		return isTime(n) && (hasAttr(n, "pubdate") || strings.Contains(strings.ToLower(getAttr(n, "class")), "publish"))
+	}); n != nil {
+		return getAttr(n, "datetime")
+	}
+
+	if n := findNode(doc, isTime); n != nil {
+		return getAttr(n, "datetime")
+	}
+	return ""
+}
+
+// urlPathDate returns the date encoded in a /yyyy/mm/dd/ URL path as
+// "yyyy-mm-dd", or ""
+func urlPathDate(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	m := urlDatePattern.FindStringSubmatch(u.Path)
+	if m == nil {
+		return ""
+	}
+	return m[1] + "-" + m[2] + "-" + m[3]
+}
+
+// findNode returns the first element in document order satisfying match
+func findNode(n *html.Node, match func(*html.Node) bool) *html.Node {
+	if n.Type == html.ElementNode && match(n) {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findNode(c, match); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// hasToken reports whether the space-separated list contains token
+func hasToken(list, token string) bool {
+	for _, t := range strings.Fields(list) {
+		if t == token {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestDetectPublishedTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		head     string
+		body     string
+		path     string
+		expected string
+		source   DateSource
+	}{
+		{
+			name:     "open graph wins",
+			head:     `<meta property="article:published_time" content="2024-01-01T10:00:00Z"><meta name="date" content="2023-01-01">`,
+			expected: "2024-01-01T10:00:00Z",
+			source:   DateSourceOpenGraph,
+		},
+		{
+			name:     "json-ld graph",
+			head:     `<script type="application/ld+json">{"@graph":[{"@type":"WebPage"},{"@type":"NewsArticle","datePublished":"2024-02-02"}]}</script><meta name="date" content="2023-01-01">`,
+			expected: "2024-02-02",
+			source:   DateSourceJSONLD,
+		},
+		{
+			name:     "microdata",
+			body:     `<article itemscope itemtype="https://schema.org/BlogPosting"><time itemprop="datePublished" datetime="2024-03-03">March 3</time></article>`,
+			expected: "2024-03-03",
+			source:   DateSourceMicrodata,
+		},
+		{
+			name:     "bare itemprop",
+			head:     `<meta itemprop="datePublished" content="2024-03-04">`,
+			expected: "2024-03-04",
+			source:   DateSourceMicrodata,
+		},
+		{
+			name:     "meta name preference",
+			head:     `<meta name="date" content="2023-01-01"><meta name="sailthru.date" content="2024-04-04 08:00:00">`,
+			expected: "2024-04-04 08:00:00",
+			source:   DateSourceMeta,
+		},
+		{
+			name:     "pubdate time element",
+			body:     `<time datetime="2020-01-01">old</time><time pubdate datetime="2024-05-05T12:00:00+02:00">May 5</time>`,
+			expected: "2024-05-05T12:00:00+02:00",
+			source:   DateSourceTimeElement,
+		},
+		{
+			name:     "first time element",
+			body:     `<time>no datetime</time><time datetime="2024-06-06">June 6</time>`,
+			expected: "2024-06-06",
+			source:   DateSourceTimeElement,
+		},
+		{
+			name:     "url path",
+			path:     "/news/2024/07/08/some-story",
+			expected: "2024-07-08",
+			source:   DateSourceURL,
+		},
+		{
+			name: "nothing",
+			path: "/about/2024/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+				w.Write([]byte("<html><head>" + tt.head + "</head><body>" + tt.body + "</body></html>"))
+			}))
+			defer server.Close()
+
+			metadata, err := Extract(server.URL + tt.path)
+			if err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+
+			if metadata.PublishedTime != tt.expected {
+				t.Errorf("Expected published time %q, got %q", tt.expected, metadata.PublishedTime)
+			}
+
+			if metadata.PublishedTimeSource != tt.source {
+				t.Errorf("Expected source %q, got %q", tt.source, metadata.PublishedTimeSource)
+			}
+		})
+	}
+}
+
+func TestURLPathDate(t *testing.T) {
+	tests := map[string]string{
+		"/2024/05/12/title":        "2024-05-12",
+		"/blog/2024-05-12-title":   "2024-05-12",
+		"/2024/05/12":              "2024-05-12",
+		"/2024/13/12/invalid":      "",
+		"/products/12345/05/12/x":  "",
+		"/archive/2024/05/":        "",
+		"/post/20240512/something": "",
+	}
+
+	for path, expected := range tests {
+		if got := urlPathDate(mustParseURL(t, "https://example.com"+path)); got != expected {
+			t.Errorf("urlPathDate(%q) = %q, expected %q", path, got, expected)
+		}
+	}
+}
+
+func mustParseURL(t *testing.T, rawURL string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("Failed to parse %q: %v", rawURL, err)
+	}
+	return u
+}
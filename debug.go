@@ -0,0 +1,168 @@
+package urlmeta
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+
+	"golang.org/x/net/html"
+)
+
+// maxDebugCapture bounds how much of a page is buffered to find its head
+const maxDebugCapture = 256 * 1024
+
+// DebugInfo holds the raw material an extraction worked from (only with
+// WithDebug), to diagnose why a field came out empty or wrong
+type DebugInfo struct {
+	// Head is the raw HTML of the page up to and including </head>, as
+	// received (at most 256KB)
+	Head string `json:"head,omitempty"`
+
+	// MetaTags lists every <meta> tag with a name or property, in
+	// document order
+	MetaTags []MetaTag `json:"meta_tags,omitempty"`
+
+	// OEmbedEndpoint is the last oEmbed URL requested, query included
+	OEmbedEndpoint string `json:"oembed_endpoint,omitempty"`
+
+	// OEmbedJSON is the raw oEmbed response body when it is valid JSON,
+	// whether or not it decoded into an OEmbed
+	OEmbedJSON json.RawMessage `json:"oembed_json,omitempty"`
+}
+
+// MetaTag is a <meta> tag as written in the page
+type MetaTag struct {
+	Name     string `json:"name,omitempty"`
+	Property string `json:"property,omitempty"`
+	Content  string `json:"content"`
+}
+
+// WithDebug attaches a DebugInfo to every Metadata with the raw HTML head,
+// the meta tags seen and the oEmbed request and response. It costs extra
+// memory per extraction, so it is meant for troubleshooting. Disabled by
+// default.
+func WithDebug(debug bool) Option {
+	return func(c *Client) {
+		c.debug = debug
+	}
+}
+
+// debugKey is the context key under which extract collects DebugInfo
+type debugKey struct{}
+
+// withDebugInfo returns ctx collecting debug artifacts into info
+func withDebugInfo(ctx context.Context, info *DebugInfo) context.Context {
+	return context.WithValue(ctx, debugKey{}, info)
+}
+
+// debugInfoFrom returns the DebugInfo collected for ctx, or nil
+func debugInfoFrom(ctx context.Context) *DebugInfo {
+	info, _ := ctx.Value(debugKey{}).(*DebugInfo)
+	return info
+}
+
+// recordPageDebug records the head and meta tags of the page extraction
+// is working from
+func recordPageDebug(ctx context.Context, page *htmlPage) {
+	info := debugInfoFrom(ctx)
+	if info == nil {
+		return
+	}
+	info.Head = string(page.rawHead)
+	info.MetaTags = collectMetaTags(page.doc, nil)
+}
+
+// recordOEmbedDebug records an oEmbed request and its raw response
+func recordOEmbedDebug(ctx context.Context, endpoint string, body []byte) {
+	info := debugInfoFrom(ctx)
+	if info == nil {
+		return
+	}
+	info.OEmbedEndpoint = endpoint
+	info.OEmbedJSON = nil
+	if json.Valid(body) {
+		info.OEmbedJSON = json.RawMessage(body)
+	}
+}
+
+// collectMetaTags appends the name/property/content of every <meta> under n
+func collectMetaTags(n *html.Node, tags []MetaTag) []MetaTag {
+	if n.Type == html.ElementNode && n.Data == "meta" {
+		var tag MetaTag
+		for _, attr := range n.Attr {
+			switch attr.Key {
+			case "name":
+				tag.Name = attr.Val
+			case "property":
+				tag.Property = attr.Val
+			case "content":
+				tag.Content = attr.Val
+			}
+		}
+		if tag.Name != "" || tag.Property != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		tags = collectMetaTags(child, tags)
+	}
+	return tags
+}
+
+// debugReader tees r into a headCapture when debug mode is on
+func (c *Client) debugReader(r io.Reader) (io.Reader, *headCapture) {
+	if !c.debug {
+		return r, nil
+	}
+	capture := &headCapture{}
+	return io.TeeReader(r, capture), capture
+}
+
+// headCapture buffers the start of a page, up to maxDebugCapture bytes
+type headCapture struct {
+	buf bytes.Buffer
+}
+
+// Write implements io.Writer, dropping what doesn't fit
+func (h *headCapture) Write(p []byte) (int, error) {
+	if room := maxDebugCapture - h.buf.Len(); room > 0 {
+		if len(p) > room {
+			h.buf.Write(p[:room])
+		} else {
+			h.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+// head returns the captured bytes up to and including </head>, or up to
+// <body> when the head isn't closed
+func (h *headCapture) head() []byte {
+	if h == nil {
+		return nil
+	}
+
+	data := h.buf.Bytes()
+	if i := indexFold(data, "</head"); i >= 0 {
+		if end := bytes.IndexByte(data[i:], '>'); end >= 0 {
+			return data[:i+end+1]
+		}
+	}
+	if i := indexFold(data, "<body"); i >= 0 {
+		return data[:i]
+	}
+	return data
+}
+
+// indexFold returns the index of the first ASCII case-insensitive match
+// of substr in data, or -1
+func indexFold(data []byte, substr string) int {
+	for i := 0; i+len(substr) <= len(data); i++ {
+		if bytes.EqualFold(data[i:i+len(substr)], []byte(substr)) {
+			return i
+		}
+	}
+	return -1
+}
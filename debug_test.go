@@ -0,0 +1,135 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const debugHTML = `<!DOCTYPE html>
+<html><HEAD>
+<meta charset="utf-8">
+<meta property="og:title" content="OG Title">
+<meta name="description" content="A description">
+<link rel="alternate" type="application/json+oembed" href="/oembed">
+</HEAD>
+<body><p>Body text</p></body></html>`
+
+func newDebugServer(t *testing.T, oembedBody string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(debugHTML))
+	})
+	mux.HandleFunc("/oembed", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(oembedBody))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWithDebugHTML(t *testing.T) {
+	server := newDebugServer(t, `{}`)
+
+	client := NewClient(WithDebug(true), WithAutoOEmbed(false))
+	metadata, err := client.Extract(server.URL + "/page")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	debug := metadata.Debug
+	if debug == nil {
+		t.Fatal("Expected Debug to be set")
+	}
+	if !strings.HasPrefix(debug.Head, "<!DOCTYPE html>") || !strings.HasSuffix(debug.Head, "</HEAD>") {
+		t.Errorf("Expected the raw head as received, got %q", debug.Head)
+	}
+	if strings.Contains(debug.Head, "Body text") {
+		t.Error("Expected Head to stop at </head>")
+	}
+
+	want := []MetaTag{
+		{Property: "og:title", Content: "OG Title"},
+		{Name: "description", Content: "A description"},
+	}
+	if len(debug.MetaTags) != len(want) {
+		t.Fatalf("Expected %d meta tags, got %+v", len(want), debug.MetaTags)
+	}
+	for i, tag := range want {
+		if debug.MetaTags[i] != tag {
+			t.Errorf("Meta tag %d: expected %+v, got %+v", i, tag, debug.MetaTags[i])
+		}
+	}
+	if debug.OEmbedEndpoint != "" {
+		t.Errorf("Expected no oEmbed endpoint, got %q", debug.OEmbedEndpoint)
+	}
+}
+
+func TestWithDebugOEmbed(t *testing.T) {
+	// Valid JSON that doesn't decode into an OEmbed still shows up
+	server := newDebugServer(t, `{"type":"rich","version":1}`)
+
+	client := NewClient(WithDebug(true), WithStrategy(StrategyOEmbedFirst))
+	metadata, err := client.Extract(server.URL + "/page")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	debug := metadata.Debug
+	if debug == nil {
+		t.Fatal("Expected Debug to be set")
+	}
+	if !strings.HasPrefix(debug.OEmbedEndpoint, server.URL+"/oembed?") || !strings.Contains(debug.OEmbedEndpoint, "format=json") {
+		t.Errorf("Expected the oEmbed request URL, got %q", debug.OEmbedEndpoint)
+	}
+	if string(debug.OEmbedJSON) != `{"type":"rich","version":1}` {
+		t.Errorf("Expected the raw oEmbed JSON, got %s", debug.OEmbedJSON)
+	}
+	// oEmbed failed, so the page itself was extracted
+	if len(debug.MetaTags) == 0 || debug.Head == "" {
+		t.Error("Expected the fallback page to be captured")
+	}
+}
+
+func TestWithDebugDisabled(t *testing.T) {
+	server := newDebugServer(t, `{}`)
+
+	metadata, err := NewClient(WithAutoOEmbed(false)).Extract(server.URL + "/page")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if metadata.Debug != nil {
+		t.Errorf("Expected no Debug by default, got %+v", metadata.Debug)
+	}
+}
+
+func TestHeadCapture(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"closed head", "<html><head><title>x</title></head><body>y", "<html><head><title>x</title></head>"},
+		{"implicit head", "<title>x</title><BODY>y", "<title>x</title>"},
+		{"no markers", "<title>x</title>", "<title>x</title>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capture := &headCapture{}
+			capture.Write([]byte(tt.in))
+			if got := string(capture.head()); got != tt.want {
+				t.Errorf("head() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	capture := &headCapture{}
+	if n, _ := capture.Write(make([]byte, maxDebugCapture+10)); n != maxDebugCapture+10 || capture.buf.Len() != maxDebugCapture {
+		t.Errorf("Expected capture to stop at %d bytes, got %d", maxDebugCapture, capture.buf.Len())
+	}
+}
@@ -0,0 +1,153 @@
+package urlmeta
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// acceptEncoding is sent on every request that doesn't set its own
+const acceptEncoding = "br, zstd, gzip, deflate"
+
+// decodingTransport advertises brotli, zstd, gzip and deflate support and
+// decodes responses accordingly. net/http only decodes gzip when it adds
+// Accept-Encoding itself, so once we set the header all decoding is ours.
+type decodingTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *decodingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Ranges apply to the encoded bytes, so leave ranged requests alone;
+	// likewise when the caller picked its own encodings
+	if req.Header.Get("Range") != "" || req.Header.Get("Accept-Encoding") != "" {
+		return t.next.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding == "" || encoding == "identity" || req.Method == http.MethodHead {
+		return resp, nil
+	}
+
+	body, ok := newDecodingBody(encoding, resp.Body)
+	if !ok {
+		// Unknown or stacked encoding: hand the body over untouched
+		return resp, nil
+	}
+
+	resp.Body = body
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return resp, nil
+}
+
+// newDecodingBody wraps body with a decoder for encoding
+func newDecodingBody(encoding string, body io.ReadCloser) (io.ReadCloser, bool) {
+	var open func(io.Reader) (io.Reader, func(), error)
+
+	switch encoding {
+	case "br":
+		open = func(r io.Reader) (io.Reader, func(), error) {
+			return brotli.NewReader(r), nil, nil
+		}
+	case "zstd":
+		open = func(r io.Reader) (io.Reader, func(), error) {
+			// A single goroutine and a 64MB window keep memory bounded
+			d, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxWindow(64<<20))
+			if err != nil {
+				return nil, nil, err
+			}
+			return d, d.Close, nil
+		}
+	case "gzip", "x-gzip":
+		open = func(r io.Reader) (io.Reader, func(), error) {
+			gz, err := gzip.NewReader(r)
+			if err != nil {
+				return nil, nil, err
+			}
+			return gz, func() { gz.Close() }, nil
+		}
+	case "deflate":
+		open = openDeflate
+	default:
+		return nil, false
+	}
+
+	return &decodingBody{body: body, open: open}, true
+}
+
+// decodingBody decodes body lazily, so that header parsing errors surface
+// from Read like any other body error
+type decodingBody struct {
+	body    io.ReadCloser
+	open    func(io.Reader) (io.Reader, func(), error)
+	decoder io.Reader
+	release func()
+	err     error
+}
+
+// Read implements io.Reader
+func (d *decodingBody) Read(p []byte) (int, error) {
+	if d.decoder == nil && d.err == nil {
+		d.decoder, d.release, d.err = d.open(d.body)
+	}
+	if d.err != nil {
+		return 0, d.err
+	}
+	return d.decoder.Read(p)
+}
+
+// Close implements io.Closer
+func (d *decodingBody) Close() error {
+	if d.release != nil {
+		d.release()
+	}
+	return d.body.Close()
+}
+
+// openDeflate reads "deflate" content, which per RFC 9110 is zlib-wrapped
+// but is sent as raw DEFLATE by some servers
+func openDeflate(r io.Reader) (io.Reader, func(), error) {
+	br := bufio.NewReader(r)
+	if header, err := br.Peek(2); err == nil && isZlibHeader(header) {
+		z, err := zlib.NewReader(br)
+		if err != nil {
+			return nil, nil, err
+		}
+		return z, func() { z.Close() }, nil
+	}
+
+	f := flate.NewReader(br)
+	return f, func() { f.Close() }, nil
+}
+
+// isZlibHeader reports whether header starts a zlib stream (RFC 1950):
+// compression method 8 and a check value making the pair a multiple of 31
+func isZlibHeader(header []byte) bool {
+	return header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0
+}
+
+// transportOrDefault returns rt, or http.DefaultTransport when rt is nil
+func transportOrDefault(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		return http.DefaultTransport
+	}
+	return rt
+}
@@ -0,0 +1,96 @@
+package urlmeta
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestResponseDecoding(t *testing.T) {
+	encoders := map[string]func(w io.Writer) io.WriteCloser{
+		"br": func(w io.Writer) io.WriteCloser { return brotli.NewWriter(w) },
+		"zstd": func(w io.Writer) io.WriteCloser {
+			z, _ := zstd.NewWriter(w)
+			return z
+		},
+		"gzip":    func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		"deflate": func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) },
+		"raw-deflate": func(w io.Writer) io.WriteCloser {
+			f, _ := flate.NewWriter(w, flate.DefaultCompression)
+			return f
+		},
+	}
+
+	for name, newEncoder := range encoders {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			enc := newEncoder(&buf)
+			enc.Write([]byte(mockHTMLBasic))
+			enc.Close()
+
+			encoding := name
+			if name == "raw-deflate" {
+				encoding = "deflate"
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Accept-Encoding") != acceptEncoding {
+					t.Errorf("Expected Accept-Encoding %q, got %q", acceptEncoding, r.Header.Get("Accept-Encoding"))
+				}
+				w.Header().Set("Content-Type", "text/html")
+				w.Header().Set("Content-Encoding", encoding)
+				w.Write(buf.Bytes())
+			}))
+			defer server.Close()
+
+			metadata, err := Extract(server.URL)
+			if err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+			if metadata.Title != "Test Page Title" {
+				t.Errorf("Expected decoded page, got title %q", metadata.Title)
+			}
+		})
+	}
+}
+
+func TestResponseDecodingCorrupt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write([]byte("not gzip at all"))
+	}))
+	defer server.Close()
+
+	if _, err := Extract(server.URL); err == nil {
+		t.Error("Expected error for corrupt gzip body")
+	}
+}
+
+func TestResponseDecodingPassthrough(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") != "" && r.Header.Get("Accept-Encoding") == acceptEncoding {
+			t.Error("Expected ranged requests not to advertise compression")
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("Range", "bytes=0-99")
+	resp, err := client.do(req)
+	if err != nil {
+		t.Fatalf("do failed: %v", err)
+	}
+	resp.Body.Close()
+}
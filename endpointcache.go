@@ -0,0 +1,112 @@
+package urlmeta
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCachedEndpoints bounds the discovered endpoint cache; expired entries
+// are dropped once it fills up, then arbitrary ones
+const maxCachedEndpoints = 10000
+
+// WithOEmbedEndpointCache remembers, for ttl, the oEmbed endpoint found
+// through a page's <link> tag for that page's host. Further URLs on the
+// host try the endpoint right away instead of fetching the page to
+// discover it; when that fails, discovery runs as usual. Hosts matched by
+// a known provider are not affected. A ttl of zero or less disables the
+// cache (default).
+func WithOEmbedEndpointCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		if ttl > 0 {
+			c.endpointCache = newEndpointCache(ttl)
+		} else {
+			c.endpointCache = nil
+		}
+	}
+}
+
+// endpointCache maps hosts to discovered oEmbed endpoints
+type endpointCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cachedEndpoint
+}
+
+// cachedEndpoint is an endpoint and when it stops being used
+type cachedEndpoint struct {
+	endpoint string
+	expires  time.Time
+}
+
+// newEndpointCache returns an empty cache keeping entries for ttl
+func newEndpointCache(ttl time.Duration) *endpointCache {
+	return &endpointCache{
+		ttl:     ttl,
+		entries: make(map[string]cachedEndpoint),
+	}
+}
+
+// get returns the endpoint cached for targetURL's host, if any
+func (e *endpointCache) get(targetURL string) (string, bool) {
+	host := endpointCacheHost(targetURL)
+	if host == "" {
+		return "", false
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	entry, ok := e.entries[host]
+	if !ok {
+		return "", false
+	}
+	if time.Now().After(entry.expires) {
+		delete(e.entries, host)
+		return "", false
+	}
+	return entry.endpoint, true
+}
+
+// set caches endpoint for targetURL's host
+func (e *endpointCache) set(targetURL, endpoint string) {
+	host := endpointCacheHost(targetURL)
+	if host == "" {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.entries[host]; !ok && len(e.entries) >= maxCachedEndpoints {
+		e.evict()
+	}
+	e.entries[host] = cachedEndpoint{endpoint: endpoint, expires: time.Now().Add(e.ttl)}
+}
+
+// evict makes room for an entry, dropping expired entries or, if there
+// are none, an arbitrary one. The caller holds mu.
+func (e *endpointCache) evict() {
+	now := time.Now()
+	for host, entry := range e.entries {
+		if now.After(entry.expires) {
+			delete(e.entries, host)
+		}
+	}
+	for host := range e.entries {
+		if len(e.entries) < maxCachedEndpoints {
+			break
+		}
+		delete(e.entries, host)
+	}
+}
+
+// endpointCacheHost returns the cache key for targetURL: its host:port
+func endpointCacheHost(targetURL string) string {
+	u, err := url.Parse(targetURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
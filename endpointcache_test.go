@@ -0,0 +1,74 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newDiscoveryServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var pageFetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oembed" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"type":"video","version":"1.0","title":"` + r.URL.Query().Get("url") + `"}`))
+			return
+		}
+		pageFetches.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><link rel="alternate" type="application/json+oembed" href="/oembed?url=page"></head></html>`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &pageFetches
+}
+
+func TestOEmbedEndpointCache(t *testing.T) {
+	server, pageFetches := newDiscoveryServer(t)
+	client := NewClient(WithOEmbedEndpointCache(time.Minute))
+
+	for _, path := range []string{"/videos/1", "/videos/2", "/videos/3"} {
+		oembed, err := client.ExtractOEmbed(server.URL + path)
+		if err != nil {
+			t.Fatalf("ExtractOEmbed(%s) failed: %v", path, err)
+		}
+		if oembed.Title != server.URL+path {
+			t.Errorf("Expected the endpoint queried for %s, got %q", path, oembed.Title)
+		}
+	}
+
+	if n := pageFetches.Load(); n != 1 {
+		t.Errorf("Expected one discovery fetch for the host, got %d", n)
+	}
+}
+
+func TestOEmbedEndpointCacheDisabled(t *testing.T) {
+	server, pageFetches := newDiscoveryServer(t)
+	client := NewClient()
+
+	client.ExtractOEmbed(server.URL + "/videos/1")
+	client.ExtractOEmbed(server.URL + "/videos/2")
+
+	if n := pageFetches.Load(); n != 2 {
+		t.Errorf("Expected discovery for every URL by default, got %d fetches", n)
+	}
+}
+
+func TestEndpointCacheExpiry(t *testing.T) {
+	cache := newEndpointCache(10 * time.Millisecond)
+	cache.set("https://Example.com/a", "https://example.com/oembed")
+
+	if endpoint, ok := cache.get("https://example.com/b"); !ok || endpoint != "https://example.com/oembed" {
+		t.Errorf("Expected the endpoint cached for the host, got %q, %v", endpoint, ok)
+	}
+	if _, ok := cache.get("https://other.example.com/a"); ok {
+		t.Error("Expected no endpoint for another host")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := cache.get("https://example.com/b"); ok {
+		t.Error("Expected the entry to expire")
+	}
+}
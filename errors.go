@@ -0,0 +1,148 @@
+package urlmeta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+)
+
+// Sentinel errors returned (wrapped) by extraction. Use errors.Is to branch
+// on the failure cause; the wrapping error carries the details.
+var (
+	// ErrInvalidURL is returned when the target URL cannot be parsed
+	ErrInvalidURL = errors.New("invalid URL")
+
+	// ErrUnsupportedScheme is returned for URLs that are not http or https
+	ErrUnsupportedScheme = errors.New("unsupported protocol")
+
+	// ErrUnsupportedContentType is returned when the page is not HTML
+	ErrUnsupportedContentType = errors.New("unsupported content type")
+
+	// ErrTimeout is returned when a request exceeds the client timeout or
+	// the context deadline
+	ErrTimeout = errors.New("request timed out")
+
+	// ErrTooManyRedirects is returned when the redirect limit is exceeded
+	ErrTooManyRedirects = errors.New("too many redirects")
+
+	// ErrBodyTooLarge is returned when a response body exceeds the configured
+	// maximum size (see WithMaxBodySize)
+	ErrBodyTooLarge = errors.New("response body too large")
+
+	// ErrBlockedAddress is returned when SSRF protection refuses to connect to
+	// a private, loopback, link-local or otherwise internal address
+	ErrBlockedAddress = errors.New("destination address is not allowed")
+
+	// ErrHostNotAllowed is returned when a host is rejected by WithAllowedHosts
+	// or WithBlockedHosts
+	ErrHostNotAllowed = errors.New("host is not allowed")
+
+	// ErrCircuitOpen is returned without contacting the host when its circuit
+	// breaker is open after repeated failures (see WithCircuitBreaker)
+	ErrCircuitOpen = errors.New("circuit breaker open")
+
+	// ErrNoFavicon is returned by FetchFaviconDataURI when the metadata has
+	// no icon to fetch
+	ErrNoFavicon = errors.New("no favicon available")
+
+	// ErrBatchClosed is returned by BatchExtractor.Submit after Wait
+	ErrBatchClosed = errors.New("batch extractor closed")
+
+	// ErrEmbedPrivate is returned when an oEmbed endpoint answers 401: the
+	// resource exists but is private and can't be embedded
+	ErrEmbedPrivate = errors.New("oEmbed resource is private")
+
+	// ErrEmbedNotFound is returned when an oEmbed endpoint answers 404: the
+	// provider has no resource for the URL
+	ErrEmbedNotFound = errors.New("oEmbed resource not found")
+
+	// ErrEmbedFormatUnsupported is returned when an oEmbed endpoint answers
+	// 501 for both the JSON and XML formats
+	ErrEmbedFormatUnsupported = errors.New("oEmbed format not supported")
+)
+
+// ErrHTTPStatus is returned when the server answers with a non-200 status.
+// Use errors.As to inspect the code.
+type ErrHTTPStatus struct {
+	Code int
+}
+
+// Error implements error
+func (e *ErrHTTPStatus) Error() string {
+	return fmt.Sprintf("HTTP error: %d %s", e.Code, http.StatusText(e.Code))
+}
+
+// Is reports whether target is an *ErrHTTPStatus with the same code, so
+// errors.Is(err, &ErrHTTPStatus{Code: 404}) works
+func (e *ErrHTTPStatus) Is(target error) bool {
+	t, ok := target.(*ErrHTTPStatus)
+	return ok && t.Code == e.Code
+}
+
+// classifyFetchError tags transport errors with ErrTimeout where applicable.
+// Redirect, SSRF and host policy errors already wrap their own sentinels.
+func classifyFetchError(err error) error {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return err
+}
+
+// maxBytesReader reads at most limit bytes from r. When more are available
+// it either fails with ErrBodyTooLarge or, if truncate is set, reports EOF
+// and records that the body was cut short. A limit <= 0 reads r unchanged.
+type maxBytesReader struct {
+	r         io.Reader
+	limit     int64
+	remaining int64
+	truncate  bool
+	truncated bool
+}
+
+// Read implements io.Reader
+func (m *maxBytesReader) Read(p []byte) (int, error) {
+	if m.limit <= 0 {
+		return m.r.Read(p)
+	}
+
+	if m.remaining <= 0 {
+		// Limit reached: probe for one more byte to tell EOF from overflow
+		var probe [1]byte
+		n, err := m.r.Read(probe[:])
+		if n > 0 {
+			if m.truncate {
+				m.truncated = true
+				return 0, io.EOF
+			}
+			return 0, fmt.Errorf("%w: exceeds %d bytes", ErrBodyTooLarge, m.limit)
+		}
+		return 0, err
+	}
+
+	if int64(len(p)) > m.remaining {
+		p = p[:m.remaining]
+	}
+	n, err := m.r.Read(p)
+	m.remaining -= int64(n)
+	return n, err
+}
+
+// limitBody wraps resp.Body with the client's size limit. In strict mode a
+// declared Content-Length above the limit fails immediately without reading;
+// otherwise oversized bodies are truncated at the limit.
+func (c *Client) limitBody(resp *http.Response) (*maxBytesReader, error) {
+	if c.strict && c.maxBodySize > 0 && resp.ContentLength > c.maxBodySize {
+		return nil, fmt.Errorf("%w: Content-Length %d exceeds %d bytes", ErrBodyTooLarge, resp.ContentLength, c.maxBodySize)
+	}
+
+	return &maxBytesReader{
+		r:         resp.Body,
+		limit:     c.maxBodySize,
+		remaining: c.maxBodySize,
+		truncate:  !c.strict,
+	}, nil
+}
@@ -0,0 +1,207 @@
+package urlmeta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaxBytesReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		limit   int64
+		wantErr bool
+	}{
+		{"under limit", "hello", 10, false},
+		{"exactly at limit", "hello", 5, false},
+		{"over limit", "hello world", 5, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &maxBytesReader{r: strings.NewReader(tt.input), limit: tt.limit, remaining: tt.limit}
+			data, err := io.ReadAll(r)
+
+			if tt.wantErr {
+				if !errors.Is(err, ErrBodyTooLarge) {
+					t.Errorf("Expected ErrBodyTooLarge, got: %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(data) != tt.input {
+				t.Errorf("Expected '%s', got '%s'", tt.input, data)
+			}
+		})
+	}
+}
+
+func TestWithMaxBodySize(t *testing.T) {
+	page := mockHTMLBasic + strings.Repeat("<!-- padding -->", 100)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		// Flush headers first so the body is chunked and Content-Length is unknown
+		w.(http.Flusher).Flush()
+		w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithMaxBodySize(256))
+	_, err := client.Extract(server.URL)
+	if !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("Expected ErrBodyTooLarge, got: %v", err)
+	}
+
+	client = NewClient(WithMaxBodySize(int64(len(page))))
+	if _, err := client.Extract(server.URL); err != nil {
+		t.Errorf("Expected body at the limit to succeed, got: %v", err)
+	}
+}
+
+func TestMaxBodySizeContentLength(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithMaxBodySize(16))
+	_, err := client.Extract(server.URL)
+	if !errors.Is(err, ErrBodyTooLarge) || !strings.Contains(err.Error(), "Content-Length") {
+		t.Errorf("Expected Content-Length based ErrBodyTooLarge, got: %v", err)
+	}
+}
+
+func TestMaxBodySizeOEmbed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(mockOEmbedResponse))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithMaxBodySize(32))
+	_, err := client.fetchOEmbed(context.Background(), server.URL+"/oembed", "https://example.com/video")
+	if !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("Expected ErrBodyTooLarge for oEmbed response, got: %v", err)
+	}
+}
+
+func TestSentinelErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{}`))
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		case "/slow":
+			time.Sleep(300 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithTimeout(100*time.Millisecond), WithMaxRedirects(2))
+
+	tests := []struct {
+		name   string
+		url    string
+		target error
+	}{
+		{"invalid URL", "http://[::1", ErrInvalidURL},
+		{"unsupported scheme", "ftp://example.com", ErrUnsupportedScheme},
+		{"HTTP status", server.URL + "/missing", &ErrHTTPStatus{Code: http.StatusNotFound}},
+		{"content type", server.URL + "/json", ErrUnsupportedContentType},
+		{"redirects", server.URL + "/loop", ErrTooManyRedirects},
+		{"timeout", server.URL + "/slow", ErrTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.Extract(tt.url)
+			if !errors.Is(err, tt.target) {
+				t.Errorf("Expected errors.Is(%v, %v)", err, tt.target)
+			}
+		})
+	}
+}
+
+func TestErrHTTPStatusAs(t *testing.T) {
+	var err error = fmt.Errorf("wrapped: %w", &ErrHTTPStatus{Code: http.StatusForbidden})
+
+	var statusErr *ErrHTTPStatus
+	if !errors.As(err, &statusErr) {
+		t.Fatal("Expected errors.As to find *ErrHTTPStatus")
+	}
+
+	if statusErr.Code != http.StatusForbidden {
+		t.Errorf("Expected code 403, got %d", statusErr.Code)
+	}
+
+	if errors.Is(err, &ErrHTTPStatus{Code: http.StatusNotFound}) {
+		t.Error("Expected different status codes not to match")
+	}
+
+	if statusErr.Error() != "HTTP error: 403 Forbidden" {
+		t.Errorf("Unexpected message: %s", statusErr.Error())
+	}
+}
+
+func TestOEmbedStatusErrors(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusUnauthorized, ErrEmbedPrivate},
+		{http.StatusNotFound, ErrEmbedNotFound},
+		{http.StatusNotImplemented, ErrEmbedFormatUnsupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			client := NewClient()
+			_, err := client.fetchOEmbed(context.Background(), server.URL+"/oembed", "https://example.com/video")
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got: %v", tt.want, err)
+			}
+			var status *ErrHTTPStatus
+			if !errors.As(err, &status) || status.Code != tt.status {
+				t.Errorf("Expected the HTTP status to be kept, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestExtractOEmbedPrivate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oembed" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><link rel="alternate" type="application/json+oembed" href="/oembed"></head></html>`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient().ExtractOEmbed(server.URL + "/video")
+	if !errors.Is(err, ErrEmbedPrivate) {
+		t.Errorf("Expected ErrEmbedPrivate from ExtractOEmbed, got: %v", err)
+	}
+}
@@ -1,9 +1,9 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"sync"
 	"time"
 
 	"github.com/alfarisi/urlmeta"
@@ -89,67 +89,38 @@ func processSequential(urls []string) []Result {
 	return results
 }
 
-// processConcurrent processes URLs concurrently with worker pool
+// processConcurrent processes URLs concurrently using the built-in worker pool
 func processConcurrent(urls []string, workers int) []Result {
 	client := urlmeta.NewClient(
 		urlmeta.WithTimeout(10 * time.Second),
 	)
 
-	// Channels for work distribution
-	urlChan := make(chan string, len(urls))
-	resultChan := make(chan Result, len(urls))
-
-	// Start workers
-	var wg sync.WaitGroup
-	for i := 0; i < workers; i++ {
-		wg.Add(1)
-		go worker(i+1, client, urlChan, resultChan, &wg)
-	}
-
-	// Send URLs to workers
-	for _, url := range urls {
-		urlChan <- url
+	batch, err := client.ExtractAll(context.Background(), urls,
+		urlmeta.WithConcurrency(workers),
+		urlmeta.WithPerURLTimeout(15*time.Second),
+	)
+	if err != nil {
+		log.Printf("Batch interrupted: %v", err)
 	}
-	close(urlChan)
-
-	// Wait for all workers to finish
-	go func() {
-		wg.Wait()
-		close(resultChan)
-	}()
 
-	// Collect results
-	results := make([]Result, 0, len(urls))
-	for result := range resultChan {
-		results = append(results, result)
-		if result.Error != nil {
-			fmt.Printf("❌ Worker processed: %s - Error: %v\n", result.URL, result.Error)
+	results := make([]Result, 0, len(batch))
+	for _, r := range batch {
+		results = append(results, Result{
+			URL:      r.URL,
+			Metadata: r.Metadata,
+			Error:    r.Error,
+			Duration: r.Duration,
+		})
+		if r.Error != nil {
+			fmt.Printf("❌ Worker processed: %s - Error: %v\n", r.URL, r.Error)
 		} else {
-			fmt.Printf("✓ Worker processed: %s - %v\n", result.URL, result.Duration)
+			fmt.Printf("✓ Worker processed: %s - %v\n", r.URL, r.Duration)
 		}
 	}
 
 	return results
 }
 
-// worker processes URLs from the channel
-func worker(id int, client *urlmeta.Client, urls <-chan string, results chan<- Result, wg *sync.WaitGroup) {
-	defer wg.Done()
-
-	for url := range urls {
-		start := time.Now()
-		metadata, err := client.Extract(url)
-		duration := time.Since(start)
-
-		results <- Result{
-			URL:      url,
-			Metadata: metadata,
-			Error:    err,
-			Duration: duration,
-		}
-	}
-}
-
 // displayResults shows a summary of the results
 func displayResults(results []Result) {
 	successCount := 0
@@ -175,29 +146,18 @@ func displayResults(results []Result) {
 	}
 }
 
-// Example of custom error handling and retry logic
+// Example of retrying transient failures (timeouts, connection resets,
+// 429 and 5xx responses) with exponential backoff and jitter
 func processWithRetry(url string, maxRetries int) (*urlmeta.Metadata, error) {
 	client := urlmeta.NewClient(
-		urlmeta.WithTimeout(10 * time.Second),
+		urlmeta.WithTimeout(10*time.Second),
+		urlmeta.WithRetry(maxRetries+1, urlmeta.ExponentialBackoff(time.Second, 10*time.Second)),
 	)
 
-	var lastErr error
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		if attempt > 0 {
-			// Exponential backoff
-			backoff := time.Duration(attempt) * time.Second
-			fmt.Printf("Retry attempt %d for %s after %v\n", attempt, url, backoff)
-			time.Sleep(backoff)
-		}
-
-		metadata, err := client.Extract(url)
-		if err == nil {
-			return metadata, nil
-		}
-
-		lastErr = err
-		log.Printf("Attempt %d failed for %s: %v", attempt+1, url, err)
+	metadata, err := client.Extract(url)
+	if err != nil {
+		log.Printf("Giving up on %s: %v", url, err)
+		return nil, err
 	}
-
-	return nil, fmt.Errorf("failed after %d attempts: %w", maxRetries+1, lastErr)
+	return metadata, nil
 }
@@ -0,0 +1,96 @@
+package urlmeta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Expansion is the outcome of Client.ExpandURL
+type Expansion struct {
+	// URL is where the redirects led
+	URL string `json:"url"`
+
+	// StatusCode is the final response status
+	StatusCode int `json:"status_code"`
+
+	// Hops lists the redirects followed, in order
+	Hops []Redirect `json:"hops,omitempty"`
+}
+
+// Redirect is one redirect followed by ExpandURL
+type Redirect struct {
+	// URL is the address that redirected
+	URL string `json:"url"`
+
+	// StatusCode is its redirect status (301, 302, 303, 307 or 308)
+	StatusCode int `json:"status_code"`
+}
+
+// redirectLogKey is the context key under which ExpandURL collects the
+// redirects followed by the HTTP client
+type redirectLogKey struct{}
+
+// recordRedirect adds the hop leading to req to the redirect log carried by
+// its context, if any. Called from the client's CheckRedirect.
+func recordRedirect(req *http.Request, via []*http.Request) {
+	hops, ok := req.Context().Value(redirectLogKey{}).(*[]Redirect)
+	if !ok || req.Response == nil {
+		return
+	}
+	*hops = append(*hops, Redirect{URL: via[len(via)-1].URL.String(), StatusCode: req.Response.StatusCode})
+}
+
+// ExpandURL follows the HTTP redirects of a short link (bit.ly, t.co, ...)
+// and reports where it leads, without downloading or parsing the page. A
+// HEAD request is tried first; GET is used when the server refuses HEAD
+// or answers it with an error status. The final status is reported rather
+// than treated as an error. Redirects done in HTML or JavaScript are not
+// followed. Host policies, SSRF protection and the redirect limit apply as
+// for Extract.
+func (c *Client) ExpandURL(ctx context.Context, shortURL string) (*Expansion, error) {
+	shortURL = normalizeURL(shortURL)
+
+	parsedURL, err := url.Parse(shortURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidURL, err)
+	}
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return nil, fmt.Errorf("%w: %s (only http and https are supported)", ErrUnsupportedScheme, parsedURL.Scheme)
+	}
+
+	expansion, err := c.followRedirects(ctx, http.MethodHead, shortURL)
+	if err == nil && expansion.StatusCode < 400 {
+		return expansion, nil
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	c.logDebug(ctx, "urlmeta: HEAD expansion failed, retrying with GET", "url", shortURL)
+	return c.followRedirects(ctx, http.MethodGet, shortURL)
+}
+
+// followRedirects sends a method request to targetURL and records the
+// redirects on the way to the final response, whose body is discarded
+func (c *Client) followRedirects(ctx context.Context, method, targetURL string) (*Expansion, error) {
+	var hops []Redirect
+	req, err := http.NewRequestWithContext(context.WithValue(ctx, redirectLogKey{}, &hops), method, targetURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch URL: %w", classifyFetchError(err))
+	}
+	resp.Body.Close()
+
+	return &Expansion{
+		URL:        resp.Request.URL.String(),
+		StatusCode: resp.StatusCode,
+		Hops:       hops,
+	}, nil
+}
@@ -0,0 +1,74 @@
+package urlmeta
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestExpandURL(t *testing.T) {
+	var gets atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			gets.Add(1)
+		}
+		switch r.URL.Path {
+		case "/s/abc":
+			http.Redirect(w, r, "/r/abc", http.StatusMovedPermanently)
+		case "/r/abc":
+			http.Redirect(w, r, "/article?id=1", http.StatusFound)
+		case "/nohead":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			http.Redirect(w, r, "/article", http.StatusTemporaryRedirect)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html></html>"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithMaxRedirects(5))
+
+	expansion, err := client.ExpandURL(context.Background(), server.URL+"/s/abc")
+	if err != nil {
+		t.Fatalf("ExpandURL failed: %v", err)
+	}
+	want := &Expansion{
+		URL:        server.URL + "/article?id=1",
+		StatusCode: http.StatusOK,
+		Hops: []Redirect{
+			{URL: server.URL + "/s/abc", StatusCode: http.StatusMovedPermanently},
+			{URL: server.URL + "/r/abc", StatusCode: http.StatusFound},
+		},
+	}
+	if !reflect.DeepEqual(expansion, want) {
+		t.Errorf("got %+v, want %+v", expansion, want)
+	}
+	if gets.Load() != 0 {
+		t.Errorf("Expected HEAD requests only, got %d GETs", gets.Load())
+	}
+
+	expansion, err = client.ExpandURL(context.Background(), server.URL+"/nohead")
+	if err != nil {
+		t.Fatalf("ExpandURL failed: %v", err)
+	}
+	if expansion.URL != server.URL+"/article" || len(expansion.Hops) != 1 || gets.Load() == 0 {
+		t.Errorf("Expected the GET fallback, got %+v", expansion)
+	}
+
+	if _, err := client.ExpandURL(context.Background(), server.URL+"/loop"); !errors.Is(err, ErrTooManyRedirects) {
+		t.Errorf("Expected ErrTooManyRedirects, got %v", err)
+	}
+	if _, err := client.ExpandURL(context.Background(), "ftp://example.com/x"); !errors.Is(err, ErrUnsupportedScheme) {
+		t.Errorf("Expected ErrUnsupportedScheme, got %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package urlmeta
+
+import (
+	"net/url"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// maxFallbackDescriptionLength caps descriptions taken from body text, in runes
+const maxFallbackDescriptionLength = 300
+
+// WithDescriptionFallback fills an empty Description with the first body
+// paragraph of at least minLength characters, skipping navigation, headers,
+// footers and sidebars. The text is truncated at a word boundary to 300
+// characters. A minLength of zero or less disables the fallback (default).
+func WithDescriptionFallback(minLength int) Option {
+	return func(c *Client) {
+		c.descriptionMinLength = minLength
+	}
+}
+
+// TitleFallback is a source tried, in order, when a page has no usable title
+type TitleFallback int
+
+const (
+	// TitleFallbackH1 uses the text of the first <h1>
+	TitleFallbackH1 TitleFallback = iota
+	// TitleFallbackURLSlug humanizes the last path segment of the URL,
+	// e.g. "/blog/my-first-post.html" becomes "My first post"
+	TitleFallbackURLSlug
+)
+
+// genericTitles are placeholder titles treated as missing by title fallbacks
+var genericTitles = map[string]bool{
+	"untitled":          true,
+	"untitled document": true,
+	"home":              true,
+	"homepage":          true,
+	"home page":         true,
+	"index":             true,
+	"new page":          true,
+	"welcome":           true,
+}
+
+// WithTitleFallbacks sets the sources tried, in order, when the page has no
+// title or only a generic one such as "Untitled" or "Home". The first
+// source yielding text wins; if none does, the original title is kept. No
+// fallbacks are used by default.
+func WithTitleFallbacks(fallbacks ...TitleFallback) Option {
+	return func(c *Client) {
+		c.titleFallbacks = fallbacks
+	}
+}
+
+// isGenericTitle reports whether title is empty or a known placeholder
+func isGenericTitle(title string) bool {
+	return title == "" || genericTitles[strings.ToLower(strings.Join(strings.Fields(title), " "))]
+}
+
+// fallbackTitle returns the first non-empty title produced by fallbacks
+func fallbackTitle(fallbacks []TitleFallback, doc *html.Node, pageURL *url.URL) string {
+	for _, fallback := range fallbacks {
+		var title string
+		switch fallback {
+		case TitleFallbackH1:
+			if h1 := findElement(doc, atom.H1); h1 != nil {
+				title = strings.Join(strings.Fields(textContent(h1)), " ")
+			}
+		case TitleFallbackURLSlug:
+			title = humanizeSlug(pageURL)
+		}
+		if title != "" && !isGenericTitle(title) {
+			return title
+		}
+	}
+	return ""
+}
+
+// humanizeSlug turns the last path segment of u into a readable title,
+// dropping any file extension and replacing dashes and underscores
+func humanizeSlug(u *url.URL) string {
+	segment := path.Base(strings.TrimRight(u.EscapedPath(), "/"))
+	if segment == "." || segment == "/" || segment == "" {
+		return ""
+	}
+
+	if unescaped, err := url.PathUnescape(segment); err == nil {
+		segment = unescaped
+	}
+	segment = strings.TrimSuffix(segment, path.Ext(segment))
+
+	words := strings.FieldsFunc(segment, func(r rune) bool {
+		return r == '-' || r == '_' || r == '+' || unicode.IsSpace(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+
+	title := []rune(strings.Join(words, " "))
+	title[0] = unicode.ToUpper(title[0])
+	return string(title)
+}
+
+// firstParagraph returns the whitespace-normalized text of the first <p>
+// outside page chrome with at least minLength runes, or ""
+func firstParagraph(n *html.Node, minLength int) string {
+	if n.Type == html.ElementNode {
+		if strippedElements[n.DataAtom] || isUnlikelyCandidate(n) {
+			return ""
+		}
+		if n.DataAtom == atom.P {
+			text := strings.Join(strings.Fields(textContent(n)), " ")
+			if utf8.RuneCountInString(text) >= minLength {
+				return text
+			}
+			return ""
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if text := firstParagraph(c, minLength); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// truncateAtWord shortens s to at most maxRunes runes, cutting at the last
+// space and appending an ellipsis when anything was removed
+func truncateAtWord(s string, maxRunes int) string {
+	if utf8.RuneCountInString(s) <= maxRunes {
+		return s
+	}
+
+	runes := []rune(s)
+	cut := string(runes[:maxRunes])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,;:.-") + "…"
+}
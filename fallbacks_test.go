@@ -0,0 +1,182 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestDescriptionFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Blog</title></head><body>
+			<nav><p>Home, archive, about me and everything else you might want</p></nav>
+			<p>Short intro.</p>
+			<p>This is the first   substantial paragraph
+			of the post, long enough to be used as a description.</p>
+			<p>This one comes later and should be ignored.</p>
+		</body></html>`))
+	}))
+	defer server.Close()
+
+	metadata, err := NewClient(WithDescriptionFallback(40)).Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	expected := "This is the first substantial paragraph of the post, long enough to be used as a description."
+	if metadata.Description != expected {
+		t.Errorf("Expected description %q, got %q", expected, metadata.Description)
+	}
+
+	plain, err := Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if plain.Description != "" {
+		t.Errorf("Expected no fallback by default, got %q", plain.Description)
+	}
+}
+
+func TestDescriptionFallbackKeepsMetaDescription(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	metadata, err := NewClient(WithDescriptionFallback(1)).Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.Description != "This is a test description" {
+		t.Errorf("Expected meta description to win, got %q", metadata.Description)
+	}
+}
+
+func TestTruncateAtWord(t *testing.T) {
+	tests := []struct {
+		input    string
+		max      int
+		expected string
+	}{
+		{"short text", 20, "short text"},
+		{"the quick brown fox jumps", 12, "the quick…"},
+		{"one, two, three", 10, "one, two…"},
+		{"unbrokenlongword", 8, "unbroken…"},
+	}
+
+	for _, tt := range tests {
+		if got := truncateAtWord(tt.input, tt.max); got != tt.expected {
+			t.Errorf("truncateAtWord(%q, %d) = %q, expected %q", tt.input, tt.max, got, tt.expected)
+		}
+	}
+
+	long := strings.Repeat("word ", 100)
+	if got := truncateAtWord(long, maxFallbackDescriptionLength); utf8.RuneCountInString(got) > maxFallbackDescriptionLength+1 {
+		t.Errorf("Expected at most %d runes, got %d", maxFallbackDescriptionLength+1, utf8.RuneCountInString(got))
+	}
+}
+
+func TestTitleFallbacks(t *testing.T) {
+	tests := []struct {
+		name      string
+		page      string
+		path      string
+		fallbacks []TitleFallback
+		expected  string
+	}{
+		{
+			name:      "h1 when title missing",
+			page:      `<html><head></head><body><h1> My  Post </h1></body></html>`,
+			path:      "/posts/ignored-slug",
+			fallbacks: []TitleFallback{TitleFallbackH1, TitleFallbackURLSlug},
+			expected:  "My Post",
+		},
+		{
+			name:      "h1 when title is generic",
+			page:      `<html><head><title>Untitled</title></head><body><h1>Real Title</h1></body></html>`,
+			path:      "/",
+			fallbacks: []TitleFallback{TitleFallbackH1},
+			expected:  "Real Title",
+		},
+		{
+			name:      "slug when no h1",
+			page:      `<html><head><title>Home</title></head><body></body></html>`,
+			path:      "/blog/my-first_post.html",
+			fallbacks: []TitleFallback{TitleFallbackH1, TitleFallbackURLSlug},
+			expected:  "My first post",
+		},
+		{
+			name:      "order is honored",
+			page:      `<html><head></head><body><h1>Heading</h1></body></html>`,
+			path:      "/some-slug",
+			fallbacks: []TitleFallback{TitleFallbackURLSlug, TitleFallbackH1},
+			expected:  "Some slug",
+		},
+		{
+			name:      "real title is kept",
+			page:      `<html><head><title>Proper Title</title></head><body><h1>Heading</h1></body></html>`,
+			path:      "/",
+			fallbacks: []TitleFallback{TitleFallbackH1},
+			expected:  "Proper Title",
+		},
+		{
+			name:      "generic title kept when fallbacks fail",
+			page:      `<html><head><title>Home</title></head><body></body></html>`,
+			path:      "/",
+			fallbacks: []TitleFallback{TitleFallbackH1, TitleFallbackURLSlug},
+			expected:  "Home",
+		},
+		{
+			name:     "disabled by default",
+			page:     `<html><head></head><body><h1>Heading</h1></body></html>`,
+			path:     "/",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+				w.Write([]byte(tt.page))
+			}))
+			defer server.Close()
+
+			metadata, err := NewClient(WithTitleFallbacks(tt.fallbacks...)).Extract(server.URL + tt.path)
+			if err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+
+			if metadata.Title != tt.expected {
+				t.Errorf("Expected title %q, got %q", tt.expected, metadata.Title)
+			}
+		})
+	}
+}
+
+func TestHumanizeSlug(t *testing.T) {
+	tests := []struct {
+		rawURL   string
+		expected string
+	}{
+		{"https://example.com/", ""},
+		{"https://example.com", ""},
+		{"https://example.com/blog/hello-world/", "Hello world"},
+		{"https://example.com/a/caf%C3%A9_au_lait.php", "Café au lait"},
+		{"https://example.com/---", ""},
+	}
+
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.rawURL)
+		if got := humanizeSlug(u); got != tt.expected {
+			t.Errorf("humanizeSlug(%q) = %q, expected %q", tt.rawURL, got, tt.expected)
+		}
+	}
+}
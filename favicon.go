@@ -0,0 +1,161 @@
+package urlmeta
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// WithVerifyFavicon checks the favicon with a HEAD request and clears it when
+// the server returns an error status or a non-image content type. When the
+// page declares no icon link, /favicon.ico and /favicon.svg at the site root
+// are probed instead.
+func WithVerifyFavicon(verify bool) Option {
+	return func(c *Client) {
+		c.verifyFavicon = verify
+	}
+}
+
+// faviconDataURISize is the display size FetchFaviconDataURI picks an icon for
+const faviconDataURISize = 32
+
+// verifyFaviconURL replaces metadata.Favicon with the first candidate that
+// is reachable and looks like an image, or "" if none is
+func (c *Client) verifyFaviconURL(ctx context.Context, metadata *Metadata) {
+	candidates := []string{metadata.Favicon}
+	if metadata.Favicon == "" {
+		pageURL, err := url.Parse(metadata.URL)
+		if err != nil {
+			return
+		}
+		root := &url.URL{Scheme: pageURL.Scheme, Host: pageURL.Host}
+		candidates = []string{
+			root.JoinPath("favicon.ico").String(),
+			root.JoinPath("favicon.svg").String(),
+		}
+	}
+
+	metadata.Favicon = ""
+	for _, candidate := range candidates {
+		if _, ok := c.probeImage(ctx, candidate); ok {
+			metadata.Favicon = candidate
+			return
+		}
+	}
+}
+
+// probeImage checks that imageURL answers with a 2xx status and an image
+// content type, returning the (already closed) response when it does.
+// Servers that reject HEAD are retried with GET.
+func (c *Client) probeImage(ctx context.Context, imageURL string) (*http.Response, bool) {
+	resp, err := c.probe(ctx, http.MethodHead, imageURL)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+		resp, err = c.probe(ctx, http.MethodGet, imageURL)
+	}
+	if err != nil {
+		return nil, false
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, false
+	}
+
+	if !isImageContentType(resp.Header.Get("Content-Type")) {
+		return nil, false
+	}
+	return resp, true
+}
+
+// probe sends a bodiless request and closes the response immediately
+func (c *Client) probe(ctx context.Context, method, target string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+
+	return resp, nil
+}
+
+// isImageContentType reports whether a Content-Type header denotes an image.
+// application/octet-stream is tolerated since many servers send .ico files
+// that way.
+func isImageContentType(contentType string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+	return strings.HasPrefix(mediaType, "image/") || mediaType == "application/octet-stream"
+}
+
+// FetchFaviconDataURI downloads the best icon of metadata (see BestIcon,
+// falling back to Favicon) and returns it as a base64 "data:" URI, so UIs
+// can inline small icons instead of hot-linking them. Icons larger than
+// maxBytes fail with ErrBodyTooLarge. A maxBytes <= 0 uses the client's
+// WithMaxBodySize limit, or the 10MB default when that is disabled.
+func (c *Client) FetchFaviconDataURI(metadata *Metadata, maxBytes int) (string, error) {
+	return c.FetchFaviconDataURIContext(context.Background(), metadata, maxBytes)
+}
+
+// FetchFaviconDataURIContext is FetchFaviconDataURI with a context
+func (c *Client) FetchFaviconDataURIContext(ctx context.Context, metadata *Metadata, maxBytes int) (string, error) {
+	iconURL := metadata.Favicon
+	if icon := metadata.BestIcon(faviconDataURISize); icon != nil {
+		iconURL = icon.URL
+	}
+	if iconURL == "" {
+		return "", ErrNoFavicon
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, iconURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidURL, err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch favicon: %w", classifyFetchError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", &ErrHTTPStatus{Code: resp.StatusCode}
+	}
+
+	limit := int64(maxBytes)
+	if limit <= 0 {
+		limit = c.maxBodySize
+		if limit <= 0 {
+			limit = defaultMaxBodySize
+		}
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read favicon: %w", classifyFetchError(err))
+	}
+	if int64(len(data)) > limit {
+		return "", fmt.Errorf("%w: favicon exceeds %d bytes", ErrBodyTooLarge, limit)
+	}
+
+	contentType := strings.TrimSpace(strings.SplitN(resp.Header.Get("Content-Type"), ";", 2)[0])
+	if !strings.HasPrefix(strings.ToLower(contentType), "image/") {
+		// Many servers send .ico files as octet-stream or text/plain
+		contentType = http.DetectContentType(data)
+		if strings.HasSuffix(strings.ToLower(req.URL.Path), ".svg") && !strings.HasPrefix(contentType, "image/") {
+			contentType = "image/svg+xml"
+		}
+	}
+	if !strings.HasPrefix(strings.ToLower(contentType), "image/") {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedContentType, contentType)
+	}
+
+	return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
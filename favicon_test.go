@@ -0,0 +1,223 @@
+package urlmeta
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newFaviconServer(page string, icons map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(page))
+			return
+		}
+
+		contentType, ok := icons[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == http.MethodHead && r.URL.Query().Get("nohead") != "" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+	}))
+}
+
+func TestVerifyFavicon(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     string
+		icons    map[string]string
+		expected string
+	}{
+		{
+			name:     "declared favicon exists",
+			page:     `<html><head><link rel="icon" href="/icon.png"></head></html>`,
+			icons:    map[string]string{"/icon.png": "image/png"},
+			expected: "/icon.png",
+		},
+		{
+			name:     "declared favicon missing",
+			page:     `<html><head><link rel="icon" href="/icon.png"></head></html>`,
+			icons:    map[string]string{"/favicon.ico": "image/x-icon"},
+			expected: "",
+		},
+		{
+			name:     "declared favicon is not an image",
+			page:     `<html><head><link rel="icon" href="/icon.png"></head></html>`,
+			icons:    map[string]string{"/icon.png": "text/html; charset=utf-8"},
+			expected: "",
+		},
+		{
+			name:     "root favicon.ico fallback",
+			page:     `<html><head></head></html>`,
+			icons:    map[string]string{"/favicon.ico": "image/vnd.microsoft.icon"},
+			expected: "/favicon.ico",
+		},
+		{
+			name:     "root favicon.svg fallback",
+			page:     `<html><head></head></html>`,
+			icons:    map[string]string{"/favicon.svg": "image/svg+xml"},
+			expected: "/favicon.svg",
+		},
+		{
+			name:     "HEAD not allowed falls back to GET",
+			page:     `<html><head><link rel="icon" href="/icon.png?nohead=1"></head></html>`,
+			icons:    map[string]string{"/icon.png": "image/png"},
+			expected: "/icon.png?nohead=1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFaviconServer(tt.page, tt.icons)
+			defer server.Close()
+
+			client := NewClient(WithVerifyFavicon(true))
+			metadata, err := client.Extract(server.URL + "/")
+			if err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+
+			expected := ""
+			if tt.expected != "" {
+				expected = server.URL + tt.expected
+			}
+			if metadata.Favicon != expected {
+				t.Errorf("Expected favicon '%s', got '%s'", expected, metadata.Favicon)
+			}
+		})
+	}
+}
+
+func TestIsImageContentType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		expected    bool
+	}{
+		{"image/png", true},
+		{"IMAGE/X-ICON", true},
+		{"image/svg+xml; charset=utf-8", true},
+		{"application/octet-stream", true},
+		{"text/html", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if result := isImageContentType(tt.contentType); result != tt.expected {
+			t.Errorf("isImageContentType(%q) = %v, expected %v", tt.contentType, result, tt.expected)
+		}
+	}
+}
+
+func TestFetchFaviconDataURI(t *testing.T) {
+	icoData := []byte("\x00\x00\x01\x00\x01\x00\x10\x10")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/icon-32.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("png-bytes"))
+		case "/favicon.ico":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write(icoData)
+		case "/large.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(make([]byte, 2048))
+		case "/page.html":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient()
+
+	tests := []struct {
+		name     string
+		metadata *Metadata
+		expected string
+		err      error
+	}{
+		{
+			name: "best icon",
+			metadata: &Metadata{
+				Favicon: server.URL + "/favicon.ico",
+				Icons: []Icon{
+					{URL: server.URL + "/icon-16.png", Sizes: "16x16", Rel: "icon"},
+					{URL: server.URL + "/icon-32.png", Sizes: "32x32", Rel: "icon"},
+				},
+			},
+			expected: "data:image/png;base64,cG5nLWJ5dGVz",
+		},
+		{
+			name:     "favicon with sniffed type",
+			metadata: &Metadata{Favicon: server.URL + "/favicon.ico"},
+			expected: "data:image/x-icon;base64,AAABAAEAEBA=",
+		},
+		{
+			name:     "too large",
+			metadata: &Metadata{Favicon: server.URL + "/large.png"},
+			err:      ErrBodyTooLarge,
+		},
+		{
+			name:     "not an image",
+			metadata: &Metadata{Favicon: server.URL + "/page.html"},
+			err:      ErrUnsupportedContentType,
+		},
+		{
+			name:     "missing",
+			metadata: &Metadata{Favicon: server.URL + "/missing.ico"},
+			err:      &ErrHTTPStatus{Code: http.StatusNotFound},
+		},
+		{
+			name:     "no favicon",
+			metadata: &Metadata{},
+			err:      ErrNoFavicon,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataURI, err := client.FetchFaviconDataURI(tt.metadata, 1024)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Expected error %v, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchFaviconDataURI failed: %v", err)
+			}
+			if dataURI != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, dataURI)
+			}
+		})
+	}
+}
+
+func TestFetchFaviconDataURIDefaultLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(make([]byte, 2048))
+	}))
+	defer server.Close()
+
+	metadata := &Metadata{Favicon: server.URL + "/favicon.png"}
+
+	// maxBytes <= 0 falls back to the client's body size limit
+	if _, err := NewClient().FetchFaviconDataURI(metadata, 0); err != nil {
+		t.Errorf("Expected the icon within the default limit, got %v", err)
+	}
+	client := NewClient(WithMaxBodySize(1024))
+	if _, err := client.FetchFaviconDataURI(metadata, 0); !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("Expected ErrBodyTooLarge from WithMaxBodySize, got %v", err)
+	}
+}
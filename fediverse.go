@@ -0,0 +1,132 @@
+package urlmeta
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// fediverseCacheTTL is how long a host's detected server software is kept
+const fediverseCacheTTL = 6 * time.Hour
+
+// fediversePostPath matches status URLs of Mastodon (/@user/id,
+// /users/user/statuses/id) and Pleroma/Akkoma (/notice/id, /objects/uuid)
+var fediversePostPath = regexp.MustCompile(`^/(?:@[^/]+/\d+|users/[^/]+/statuses/\d+|notice/[A-Za-z0-9]+|objects/[0-9a-fA-F-]+)/?$`)
+
+// mastodonSoftware are nodeinfo software names serving Mastodon's
+// /api/oembed endpoint
+var mastodonSoftware = map[string]bool{
+	"mastodon":  true,
+	"hometown":  true,
+	"glitchsoc": true,
+}
+
+// fediverseSoftware are the other nodeinfo software names whose status
+// pages advertise an oEmbed endpoint through discovery
+var fediverseSoftware = map[string]bool{
+	"pleroma": true,
+	"akkoma":  true,
+}
+
+// WithFediverseDetection treats status URLs on Mastodon, Pleroma and
+// Akkoma instances as oEmbed URLs, since instances are too numerous to
+// list as providers. A URL shaped like a status (e.g. /@user/123) has its
+// host checked once through nodeinfo (/.well-known/nodeinfo, remembered
+// for a few hours); Mastodon instances are then queried at their own
+// /api/oembed endpoint, others through oEmbed discovery. With
+// StrategyAuto such URLs use the oEmbed-first strategy. Disabled by
+// default.
+func WithFediverseDetection(detect bool) Option {
+	return func(c *Client) {
+		if detect {
+			c.fediverse = newEndpointCache(fediverseCacheTTL)
+		} else {
+			c.fediverse = nil
+		}
+	}
+}
+
+// isFediversePost reports whether u is a status on a fediverse instance
+func (c *Client) isFediversePost(ctx context.Context, u *url.URL) bool {
+	software := c.fediverseSoftware(ctx, u)
+	return mastodonSoftware[software] || fediverseSoftware[software]
+}
+
+// fediverseEndpoint returns the oEmbed endpoint of the Mastodon instance
+// u is a status on, or ""
+func (c *Client) fediverseEndpoint(ctx context.Context, u *url.URL) string {
+	if !mastodonSoftware[c.fediverseSoftware(ctx, u)] {
+		return ""
+	}
+	return fmt.Sprintf("%s://%s/api/oembed", u.Scheme, u.Host)
+}
+
+// fediverseSoftware returns the nodeinfo software name of u's host when u
+// looks like a status URL and detection is enabled
+func (c *Client) fediverseSoftware(ctx context.Context, u *url.URL) string {
+	if c.fediverse == nil || !fediversePostPath.MatchString(u.Path) {
+		return ""
+	}
+
+	if software, ok := c.fediverse.get(u.String()); ok {
+		return software
+	}
+	software, err := c.detectFediverseSoftware(ctx, u)
+	if err != nil {
+		c.logDebug(ctx, "urlmeta: nodeinfo lookup failed", "host", u.Host, "error", err)
+		if ctx.Err() != nil {
+			return ""
+		}
+	}
+	// Hosts without nodeinfo are remembered too, so they aren't asked again
+	c.fediverse.set(u.String(), software)
+	return software
+}
+
+// nodeInfoLinks is the /.well-known/nodeinfo discovery document
+type nodeInfoLinks struct {
+	Links []struct {
+		Rel  string `json:"rel"`
+		Href string `json:"href"`
+	} `json:"links"`
+}
+
+// nodeInfo is the part of a nodeinfo document used here
+type nodeInfo struct {
+	Software struct {
+		Name string `json:"name"`
+	} `json:"software"`
+}
+
+// detectFediverseSoftware reads the server software of u's host from its
+// nodeinfo document
+func (c *Client) detectFediverseSoftware(ctx context.Context, u *url.URL) (string, error) {
+	client := c.pipelineClient()
+
+	var links nodeInfoLinks
+	wellKnown := fmt.Sprintf("%s://%s/.well-known/nodeinfo", u.Scheme, u.Host)
+	if err := getJSON(ctx, client, wellKnown, nil, &links); err != nil {
+		return "", err
+	}
+
+	for _, link := range links.Links {
+		if !strings.HasPrefix(link.Rel, "http://nodeinfo.diaspora.software/ns/schema/") {
+			continue
+		}
+		// The document must live on the same host
+		href, err := u.Parse(link.Href)
+		if err != nil || !strings.EqualFold(href.Host, u.Host) {
+			continue
+		}
+
+		var info nodeInfo
+		if err := getJSON(ctx, client, href.String(), nil, &info); err != nil {
+			return "", err
+		}
+		return strings.ToLower(info.Software.Name), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,111 @@
+package urlmeta
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func newFediverseServer(t *testing.T, software string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var nodeInfoFetches atomic.Int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/nodeinfo":
+			nodeInfoFetches.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"links":[{"rel":"http://nodeinfo.diaspora.software/ns/schema/2.0","href":"` + server.URL + `/nodeinfo/2.0"}]}`))
+		case "/nodeinfo/2.0":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"version":"2.0","software":{"name":"` + software + `","version":"4.2.0"}}`))
+		case "/api/oembed":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"type":"rich","version":"1.0","author_name":"Alice","provider_name":"Example Social","html":"<iframe src=\"` + r.URL.Query().Get("url") + `/embed\"></iframe>"}`))
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><head><title>Status</title></head></html>`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &nodeInfoFetches
+}
+
+func TestFediverseDetection(t *testing.T) {
+	server, nodeInfoFetches := newFediverseServer(t, "mastodon")
+	client := NewClient(WithFediverseDetection(true))
+
+	for _, path := range []string{"/@alice/109876543210", "/users/alice/statuses/109876543211"} {
+		metadata, err := client.Extract(server.URL + path)
+		if err != nil {
+			t.Fatalf("Extract(%s) failed: %v", path, err)
+		}
+		if metadata.OEmbed == nil || metadata.OEmbed.AuthorName != "Alice" {
+			t.Fatalf("Expected oEmbed from the instance for %s, got %+v", path, metadata.OEmbed)
+		}
+		if !strings.Contains(metadata.OEmbed.HTML, path+"/embed") {
+			t.Errorf("Expected the status URL sent to /api/oembed, got %q", metadata.OEmbed.HTML)
+		}
+	}
+
+	if n := nodeInfoFetches.Load(); n != 1 {
+		t.Errorf("Expected one nodeinfo lookup for the host, got %d", n)
+	}
+}
+
+func TestFediverseDetectionOtherSoftware(t *testing.T) {
+	server, _ := newFediverseServer(t, "wordpress")
+	client := NewClient(WithFediverseDetection(true))
+
+	metadata, err := client.Extract(server.URL + "/@alice/1")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if metadata.OEmbed != nil {
+		t.Errorf("Expected no oEmbed for a non-fediverse host, got %+v", metadata.OEmbed)
+	}
+}
+
+func TestFediverseDetectionDisabled(t *testing.T) {
+	server, nodeInfoFetches := newFediverseServer(t, "mastodon")
+	client := NewClient()
+
+	if _, err := client.Extract(server.URL + "/@alice/1"); err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if n := nodeInfoFetches.Load(); n != 0 {
+		t.Errorf("Expected no nodeinfo lookups by default, got %d", n)
+	}
+}
+
+func TestFediversePostPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/@alice/109876543210", true},
+		{"/users/alice/statuses/109876543210", true},
+		{"/notice/AbC123", true},
+		{"/objects/0b1c2d3e-4f50-6172-8394-a5b6c7d8e9f0", true},
+		{"/@alice", false},
+		{"/@alice/with_replies", false},
+		{"/about", false},
+	}
+
+	for _, tt := range tests {
+		if got := fediversePostPath.MatchString(tt.path); got != tt.want {
+			t.Errorf("fediversePostPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	// Paths that don't look like statuses never trigger a lookup
+	client := NewClient(WithFediverseDetection(true))
+	u, _ := url.Parse("http://127.0.0.1:1/about")
+	if client.isFediversePost(context.Background(), u) {
+		t.Error("Expected a non-status path not to be a fediverse post")
+	}
+}
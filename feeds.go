@@ -0,0 +1,66 @@
+package urlmeta
+
+import (
+	"net/url"
+	"strings"
+)
+
+// FeedLink is a syndication feed advertised with <link rel="alternate">
+type FeedLink struct {
+	URL   string `json:"url"`
+	Type  string `json:"type"`
+	Title string `json:"title,omitempty"`
+}
+
+// Feed MIME types recognized by feed discovery
+const (
+	FeedTypeRSS  = "application/rss+xml"
+	FeedTypeAtom = "application/atom+xml"
+	FeedTypeJSON = "application/feed+json"
+)
+
+// feedTypes maps accepted type attributes to their canonical feed type
+var feedTypes = map[string]string{
+	FeedTypeRSS:             FeedTypeRSS,
+	FeedTypeAtom:            FeedTypeAtom,
+	FeedTypeJSON:            FeedTypeJSON,
+	"application/json+feed": FeedTypeJSON, // common misspelling
+	"application/rdf+xml":   FeedTypeRSS,  // RSS 1.0
+}
+
+// processFeedLink records rel="alternate" links that point at a feed
+func processFeedLink(rel, linkType, title, href string, metadata *Metadata, baseURL *url.URL) {
+	if !hasRelToken(rel, "alternate") {
+		return
+	}
+
+	// Strip parameters such as "; charset=utf-8"
+	mediaType := strings.ToLower(strings.TrimSpace(strings.SplitN(linkType, ";", 2)[0]))
+	feedType, ok := feedTypes[mediaType]
+	if !ok {
+		return
+	}
+
+	feedURL := resolveURL(href, baseURL)
+	for _, existing := range metadata.Feeds {
+		if existing.URL == feedURL {
+			return
+		}
+	}
+
+	metadata.Feeds = append(metadata.Feeds, FeedLink{
+		URL:   feedURL,
+		Type:  feedType,
+		Title: strings.TrimSpace(title),
+	})
+}
+
+// hasRelToken reports whether the space-separated rel attribute contains token
+func hasRelToken(rel, token string) bool {
+	for _, t := range strings.Fields(rel) {
+		if strings.EqualFold(t, token) {
+			return true
+		}
+	}
+	return false
+}
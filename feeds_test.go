@@ -0,0 +1,72 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const mockHTMLFeeds = `
+<!DOCTYPE html>
+<html>
+<head>
+	<title>Feeds Test</title>
+	<link rel="alternate" type="application/rss+xml" title=" Main RSS " href="/feed.xml">
+	<link rel="alternate" type="application/atom+xml; charset=utf-8" href="https://example.com/atom.xml">
+	<link rel="Alternate" type="application/feed+json" title="JSON Feed" href="/feed.json">
+	<link rel="alternate" type="application/rss+xml" href="/feed.xml">
+	<link rel="alternate" hreflang="fr" href="/fr/">
+	<link rel="alternate" type="application/json+oembed" href="/oembed">
+	<link rel="stylesheet" type="application/rss+xml" href="/not-a-feed">
+</head>
+<body></body>
+</html>
+`
+
+func TestExtractFeeds(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLFeeds))
+	}))
+	defer server.Close()
+
+	metadata, err := Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	expected := []FeedLink{
+		{URL: server.URL + "/feed.xml", Type: FeedTypeRSS, Title: "Main RSS"},
+		{URL: "https://example.com/atom.xml", Type: FeedTypeAtom},
+		{URL: server.URL + "/feed.json", Type: FeedTypeJSON, Title: "JSON Feed"},
+	}
+
+	if len(metadata.Feeds) != len(expected) {
+		t.Fatalf("Expected %d feeds, got %d: %+v", len(expected), len(metadata.Feeds), metadata.Feeds)
+	}
+
+	for i, feed := range expected {
+		if metadata.Feeds[i] != feed {
+			t.Errorf("Feed %d: expected %+v, got %+v", i, feed, metadata.Feeds[i])
+		}
+	}
+}
+
+func TestHasRelToken(t *testing.T) {
+	tests := []struct {
+		rel      string
+		token    string
+		expected bool
+	}{
+		{"alternate", "alternate", true},
+		{"ALTERNATE home", "alternate", true},
+		{"alternative", "alternate", false},
+		{"", "alternate", false},
+	}
+
+	for _, tt := range tests {
+		if result := hasRelToken(tt.rel, tt.token); result != tt.expected {
+			t.Errorf("hasRelToken(%q, %q) = %v, expected %v", tt.rel, tt.token, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package urlmeta
+
+import (
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// generatorHeaders maps response headers that only a given platform sends
+var generatorHeaders = []struct {
+	header   string
+	platform string
+}{
+	{"X-Shopify-Stage", "Shopify"},
+	{"X-ShopId", "Shopify"},
+	{"X-Drupal-Cache", "Drupal"},
+	{"X-Drupal-Dynamic-Cache", "Drupal"},
+	{"X-Wix-Request-Id", "Wix"},
+	{"X-Ghost-Cache-Status", "Ghost"},
+	{"X-Squarespace-Cache", "Squarespace"},
+	{"X-Pingback", "WordPress"},
+}
+
+// poweredByPlatforms maps X-Powered-By substrings (lowercased) to platforms;
+// generic server stacks such as PHP or Express are deliberately absent
+var poweredByPlatforms = []struct {
+	token    string
+	platform string
+}{
+	{"next.js", "Next.js"},
+	{"nuxt", "Nuxt"},
+	{"wp engine", "WordPress"},
+	{"wordpress", "WordPress"},
+	{"craft cms", "Craft CMS"},
+	{"hubspot", "HubSpot"},
+}
+
+// assetFingerprints maps substrings of script/link/img URLs to platforms
+var assetFingerprints = []struct {
+	token    string
+	platform string
+}{
+	{"/wp-content/", "WordPress"},
+	{"/wp-includes/", "WordPress"},
+	{"cdn.shopify.com", "Shopify"},
+	{"static.squarespace.com", "Squarespace"},
+	{"squarespace-cdn.com", "Squarespace"},
+	{"static.wixstatic.com", "Wix"},
+	{"static.parastorage.com", "Wix"},
+	{"website-files.com", "Webflow"},
+	{"cdn.prod.website-files.com", "Webflow"},
+	{"substackcdn.com", "Substack"},
+	{"/sites/default/files/", "Drupal"},
+	{"/misc/drupal.js", "Drupal"},
+	{"/media/jui/", "Joomla"},
+	{"/public/ghost-sdk", "Ghost"},
+	{"/_next/static/", "Next.js"},
+	{"/_nuxt/", "Nuxt"},
+	{"/_gatsby/", "Gatsby"},
+	{"static.tumblr.com", "Tumblr"},
+	{"hs-scripts.com", "HubSpot"},
+}
+
+// detectGenerator sets Metadata.Generator when the page has no generator
+// meta tag, first from platform-specific response headers and then from
+// well-known asset URLs
+func detectGenerator(doc *html.Node, header http.Header, metadata *Metadata) {
+	if metadata.Generator != "" {
+		return
+	}
+
+	if generator := strings.TrimSpace(header.Get("X-Generator")); generator != "" {
+		metadata.Generator = generator
+		return
+	}
+
+	for _, h := range generatorHeaders {
+		if header.Get(h.header) != "" {
+			metadata.Generator = h.platform
+			return
+		}
+	}
+
+	poweredBy := strings.ToLower(header.Get("X-Powered-By"))
+	for _, p := range poweredByPlatforms {
+		if strings.Contains(poweredBy, p.token) {
+			metadata.Generator = p.platform
+			return
+		}
+	}
+
+	metadata.Generator = assetPlatform(doc)
+}
+
+// assetPlatform returns the platform whose asset URLs appear first in doc
+func assetPlatform(doc *html.Node) string {
+	var platform string
+
+	findNode(doc, func(n *html.Node) bool {
+		var ref string
+		switch n.DataAtom {
+		case atom.Script, atom.Img:
+			ref = getAttr(n, "src")
+		case atom.Link:
+			ref = getAttr(n, "href")
+		default:
+			return false
+		}
+
+		ref = strings.ToLower(ref)
+		for _, f := range assetFingerprints {
+			if strings.Contains(ref, f.token) {
+				platform = f.platform
+				return true
+			}
+		}
+		return false
+	})
+
+	return platform
+}
@@ -0,0 +1,79 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDetectGenerator(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     string
+		headers  map[string]string
+		expected string
+	}{
+		{
+			name:     "meta generator",
+			page:     `<html><head><meta name="generator" content="WordPress 6.4.2"><meta name="generator" content="Elementor 3.18"></head></html>`,
+			headers:  map[string]string{"X-Shopify-Stage": "production"},
+			expected: "WordPress 6.4.2",
+		},
+		{
+			name:     "x-generator header",
+			page:     `<html><head></head></html>`,
+			headers:  map[string]string{"X-Generator": "Drupal 10 (https://www.drupal.org)"},
+			expected: "Drupal 10 (https://www.drupal.org)",
+		},
+		{
+			name:     "platform header",
+			page:     `<html><head></head></html>`,
+			headers:  map[string]string{"X-Shopify-Stage": "production"},
+			expected: "Shopify",
+		},
+		{
+			name:     "x-powered-by",
+			page:     `<html><head></head></html>`,
+			headers:  map[string]string{"X-Powered-By": "Next.js"},
+			expected: "Next.js",
+		},
+		{
+			name:     "generic x-powered-by ignored",
+			page:     `<html><head></head></html>`,
+			headers:  map[string]string{"X-Powered-By": "PHP/8.2"},
+			expected: "",
+		},
+		{
+			name:     "asset fingerprint",
+			page:     `<html><head><link rel="stylesheet" href="https://example.com/wp-content/themes/x/style.css"></head></html>`,
+			expected: "WordPress",
+		},
+		{
+			name:     "script fingerprint",
+			page:     `<html><head></head><body><script src="//cdn.shopify.com/s/files/theme.js"></script></body></html>`,
+			expected: "Shopify",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.headers {
+					w.Header().Set(k, v)
+				}
+				w.Header().Set("Content-Type", "text/html")
+				w.Write([]byte(tt.page))
+			}))
+			defer server.Close()
+
+			metadata, err := Extract(server.URL)
+			if err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+
+			if metadata.Generator != tt.expected {
+				t.Errorf("Expected generator %q, got %q", tt.expected, metadata.Generator)
+			}
+		})
+	}
+}
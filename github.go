@@ -0,0 +1,216 @@
+package urlmeta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GitHubInfo holds the GitHub-specific details of a repository, issue or
+// pull request (see GitHubExtractor)
+type GitHubInfo struct {
+	Owner      string   `json:"owner"`
+	Repo       string   `json:"repo"`
+	Stars      int      `json:"stars"`
+	Forks      int      `json:"forks"`
+	OpenIssues int      `json:"open_issues"`
+	Language   string   `json:"language,omitempty"`
+	Topics     []string `json:"topics,omitempty"`
+	Archived   bool     `json:"archived,omitempty"`
+
+	// Set for issue and pull request URLs
+	Number      int    `json:"number,omitempty"`
+	State       string `json:"state,omitempty"` // "open" or "closed"
+	PullRequest bool   `json:"pull_request,omitempty"`
+	Comments    int    `json:"comments,omitempty"`
+}
+
+// githubReservedOwners are top-level github.com paths that aren't users or
+// organizations
+var githubReservedOwners = map[string]bool{
+	"about": true, "apps": true, "collections": true, "customer-stories": true,
+	"enterprise": true, "explore": true, "features": true, "issues": true,
+	"login": true, "marketplace": true, "new": true, "notifications": true,
+	"orgs": true, "organizations": true, "pricing": true, "pulls": true,
+	"search": true, "security": true, "settings": true, "site": true,
+	"sponsors": true, "topics": true, "trending": true, "users": true,
+}
+
+// githubExtractor is the SiteExtractor for github.com
+type githubExtractor struct {
+	apiBase string
+	token   string
+}
+
+// GitHubExtractor returns a SiteExtractor for github.com repositories,
+// issues and pull requests, using the GitHub REST API. Repositories get
+// their description, stars, forks, language, topics (as Keywords) and the
+// owner's avatar; issues and pull requests their title, state, author and
+// comment count. Details are in Metadata.GitHub. The token is optional;
+// without one the API allows 60 requests an hour per IP address.
+func GitHubExtractor(token string) SiteExtractor {
+	return &githubExtractor{apiBase: "https://api.github.com", token: token}
+}
+
+// Name implements SiteExtractor
+func (g *githubExtractor) Name() string {
+	return "github"
+}
+
+// Match implements SiteExtractor
+func (g *githubExtractor) Match(u *url.URL) bool {
+	owner, _, _ := githubTarget(u)
+	return owner != ""
+}
+
+// githubTarget returns the repository u points into and, for issue and
+// pull request URLs, their number
+func githubTarget(u *url.URL) (owner, repo string, number int) {
+	host := strings.ToLower(u.Hostname())
+	if host != "github.com" && host != "www.github.com" {
+		return "", "", 0
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" || githubReservedOwners[strings.ToLower(parts[0])] {
+		return "", "", 0
+	}
+	owner, repo = parts[0], strings.TrimSuffix(parts[1], ".git")
+
+	if len(parts) >= 4 && (parts[2] == "issues" || parts[2] == "pull") {
+		if n, err := strconv.Atoi(parts[3]); err == nil && n > 0 {
+			number = n
+		}
+	}
+	return owner, repo, number
+}
+
+// githubUser is a user as returned by the REST API
+type githubUser struct {
+	Login     string `json:"login"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+// githubRepo is a repository as returned by the REST API
+type githubRepo struct {
+	FullName        string     `json:"full_name"`
+	Description     string     `json:"description"`
+	HTMLURL         string     `json:"html_url"`
+	Homepage        string     `json:"homepage"`
+	Language        string     `json:"language"`
+	StargazersCount int        `json:"stargazers_count"`
+	ForksCount      int        `json:"forks_count"`
+	OpenIssuesCount int        `json:"open_issues_count"`
+	Topics          []string   `json:"topics"`
+	Archived        bool       `json:"archived"`
+	CreatedAt       string     `json:"created_at"`
+	PushedAt        string     `json:"pushed_at"`
+	Owner           githubUser `json:"owner"`
+}
+
+// githubIssue is an issue or pull request as returned by the REST API
+type githubIssue struct {
+	Number      int        `json:"number"`
+	Title       string     `json:"title"`
+	Body        string     `json:"body"`
+	State       string     `json:"state"`
+	HTMLURL     string     `json:"html_url"`
+	Comments    int        `json:"comments"`
+	CreatedAt   string     `json:"created_at"`
+	UpdatedAt   string     `json:"updated_at"`
+	User        githubUser `json:"user"`
+	PullRequest *struct{}  `json:"pull_request"`
+}
+
+// Extract implements SiteExtractor
+func (g *githubExtractor) Extract(ctx context.Context, client *http.Client, u *url.URL) (*Metadata, error) {
+	owner, repo, number := githubTarget(u)
+
+	header := http.Header{}
+	header.Set("Accept", "application/vnd.github+json")
+	header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if g.token != "" {
+		header.Set("Authorization", "Bearer "+g.token)
+	}
+
+	repoPath := fmt.Sprintf("%s/repos/%s/%s", g.apiBase, url.PathEscape(owner), url.PathEscape(repo))
+	var r githubRepo
+	if err := getJSON(ctx, client, repoPath, header, &r); err != nil {
+		return nil, err
+	}
+
+	metadata := &Metadata{
+		Title:           r.FullName,
+		Description:     r.Description,
+		CanonicalURL:    r.HTMLURL,
+		ProviderName:    "GitHub",
+		ProviderURL:     "https://github.com",
+		ProviderDisplay: "github.com",
+		SiteName:        "GitHub",
+		Type:            "object",
+		Author:          r.Owner.Login,
+		Keywords:        r.Topics,
+		GitHub: &GitHubInfo{
+			Owner:      r.Owner.Login,
+			Repo:       strings.TrimPrefix(r.FullName, r.Owner.Login+"/"),
+			Stars:      r.StargazersCount,
+			Forks:      r.ForksCount,
+			OpenIssues: r.OpenIssuesCount,
+			Language:   r.Language,
+			Topics:     r.Topics,
+			Archived:   r.Archived,
+		},
+	}
+	setAPIDates(metadata, r.CreatedAt, r.PushedAt)
+	addAvatar(metadata, r.Owner)
+
+	if number == 0 {
+		return metadata, nil
+	}
+
+	var issue githubIssue
+	if err := getJSON(ctx, client, fmt.Sprintf("%s/issues/%d", repoPath, number), header, &issue); err != nil {
+		return nil, err
+	}
+
+	metadata.Title = issue.Title
+	metadata.Description = issue.Body
+	metadata.CanonicalURL = issue.HTMLURL
+	metadata.Type = "article"
+	metadata.Author = issue.User.Login
+	metadata.Images = nil
+	setAPIDates(metadata, issue.CreatedAt, issue.UpdatedAt)
+	addAvatar(metadata, issue.User)
+
+	metadata.GitHub.Number = issue.Number
+	metadata.GitHub.State = issue.State
+	metadata.GitHub.PullRequest = issue.PullRequest != nil
+	metadata.GitHub.Comments = issue.Comments
+
+	return metadata, nil
+}
+
+// addAvatar adds user's avatar to the images
+func addAvatar(metadata *Metadata, user githubUser) {
+	if user.AvatarURL != "" {
+		metadata.Images = append(metadata.Images, Image{URL: user.AvatarURL, Alt: user.Login, Source: ImageSourceAPI})
+	}
+}
+
+// setAPIDates sets the published and modified times from RFC 3339 dates
+// reported by an API
+func setAPIDates(metadata *Metadata, published, modified string) {
+	metadata.PublishedTime, metadata.PublishedAt = published, nil
+	if t, err := time.Parse(time.RFC3339, published); err == nil {
+		metadata.PublishedAt = &t
+		metadata.PublishedTimeSource = DateSourceAPI
+	}
+	metadata.ModifiedTime, metadata.ModifiedAt = modified, nil
+	if t, err := time.Parse(time.RFC3339, modified); err == nil {
+		metadata.ModifiedAt = &t
+	}
+}
@@ -0,0 +1,134 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+const (
+	mockGitHubRepo = `{
+		"full_name": "golang/go",
+		"description": "The Go programming language",
+		"html_url": "https://github.com/golang/go",
+		"language": "Go",
+		"stargazers_count": 120000,
+		"forks_count": 17000,
+		"open_issues_count": 9000,
+		"topics": ["go", "language"],
+		"created_at": "2014-08-19T04:33:40Z",
+		"pushed_at": "2024-08-14T10:00:00Z",
+		"owner": {"login": "golang", "avatar_url": "https://avatars.githubusercontent.com/u/4314092"}
+	}`
+
+	mockGitHubPull = `{
+		"number": 42,
+		"title": "cmd/go: fix the build",
+		"body": "Fixes #41.",
+		"state": "open",
+		"html_url": "https://github.com/golang/go/pull/42",
+		"comments": 3,
+		"created_at": "2024-08-01T12:00:00Z",
+		"updated_at": "2024-08-02T12:00:00Z",
+		"user": {"login": "gopher", "avatar_url": "https://avatars.githubusercontent.com/u/1"},
+		"pull_request": {"url": "https://api.github.com/repos/golang/go/pulls/42"}
+	}`
+)
+
+func TestGitHubTarget(t *testing.T) {
+	tests := []struct {
+		url    string
+		owner  string
+		repo   string
+		number int
+	}{
+		{"https://github.com/golang/go", "golang", "go", 0},
+		{"https://github.com/golang/go.git", "golang", "go", 0},
+		{"https://github.com/golang/go/tree/master/src", "golang", "go", 0},
+		{"https://github.com/golang/go/issues/123", "golang", "go", 123},
+		{"https://www.github.com/golang/go/pull/42/files", "golang", "go", 42},
+		{"https://github.com/golang", "", "", 0},
+		{"https://github.com/features/actions", "", "", 0},
+		{"https://gist.github.com/golang/abc", "", "", 0},
+	}
+
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		owner, repo, number := githubTarget(u)
+		if owner != tt.owner || repo != tt.repo || number != tt.number {
+			t.Errorf("githubTarget(%s) = %q, %q, %d", tt.url, owner, repo, number)
+		}
+	}
+}
+
+func newGitHubServer(t *testing.T, authorization *string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*authorization = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/golang/go":
+			w.Write([]byte(mockGitHubRepo))
+		case "/repos/golang/go/issues/42":
+			w.Write([]byte(mockGitHubPull))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGitHubExtractorRepo(t *testing.T) {
+	var authorization string
+	server := newGitHubServer(t, &authorization)
+
+	client := NewClient(WithSiteExtractors(&githubExtractor{apiBase: server.URL}))
+	metadata, err := client.Extract("https://github.com/golang/go")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.Title != "golang/go" || metadata.Description != "The Go programming language" {
+		t.Errorf("Unexpected repository fields: %q, %q", metadata.Title, metadata.Description)
+	}
+	info := metadata.GitHub
+	if info == nil || info.Stars != 120000 || info.Language != "Go" || info.Repo != "go" {
+		t.Fatalf("Unexpected GitHub details: %+v", info)
+	}
+	if len(metadata.Images) != 1 || metadata.Images[0].URL != "https://avatars.githubusercontent.com/u/4314092" {
+		t.Errorf("Expected the owner avatar, got %+v", metadata.Images)
+	}
+	if len(metadata.Keywords) != 2 || metadata.PublishedAt == nil {
+		t.Errorf("Expected topics and dates, got %v, %v", metadata.Keywords, metadata.PublishedAt)
+	}
+	if authorization != "" {
+		t.Errorf("Expected no Authorization without a token, got %q", authorization)
+	}
+}
+
+func TestGitHubExtractorPullRequest(t *testing.T) {
+	var authorization string
+	server := newGitHubServer(t, &authorization)
+
+	client := NewClient(WithSiteExtractors(&githubExtractor{apiBase: server.URL, token: "ghp_test"}))
+	metadata, err := client.Extract("https://github.com/golang/go/pull/42")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.Title != "cmd/go: fix the build" || metadata.Author != "gopher" {
+		t.Errorf("Unexpected pull request fields: %q by %q", metadata.Title, metadata.Author)
+	}
+	info := metadata.GitHub
+	if info.Number != 42 || !info.PullRequest || info.State != "open" || info.Comments != 3 || info.Stars != 120000 {
+		t.Errorf("Unexpected GitHub details: %+v", info)
+	}
+	if len(metadata.Images) != 1 || metadata.Images[0].Alt != "gopher" {
+		t.Errorf("Expected the author's avatar, got %+v", metadata.Images)
+	}
+	if authorization != "Bearer ghp_test" {
+		t.Errorf("Expected the token to be sent, got %q", authorization)
+	}
+}
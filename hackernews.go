@@ -0,0 +1,157 @@
+package urlmeta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HackerNewsItem holds the Hacker News details of an item (see
+// HackerNewsExtractor)
+type HackerNewsItem struct {
+	ID       int    `json:"id"`
+	Type     string `json:"type"` // "story", "job", "poll" or "comment"
+	Points   int    `json:"points"`
+	Comments int    `json:"comments"`
+
+	// LinkURL is the page a story points to (empty for Ask HN posts)
+	LinkURL string `json:"link_url,omitempty"`
+
+	// Article is the metadata of LinkURL, when following links is enabled
+	Article *Metadata `json:"article,omitempty"`
+}
+
+// hnChainKey marks a context extracting a story's linked article, so
+// chaining stops after one level
+type hnChainKey struct{}
+
+// hackerNewsExtractor is the SiteExtractor for Hacker News items
+type hackerNewsExtractor struct {
+	apiBase     string
+	followLinks bool
+}
+
+// HackerNewsExtractor returns a SiteExtractor for Hacker News items
+// (news.ycombinator.com/item?id=...), read from the official Firebase API.
+// It sets Title, Author, the publication date, Description (for Ask HN and
+// other text posts) and Metadata.HackerNews (points, comment count, linked
+// URL). With followLinks, the linked article is extracted too, with the
+// same client, into HackerNewsItem.Article, and its description and
+// images fill those the item lacks; a failure there only adds a
+// WarningLinkedArticleFailed. Following links needs the Client, so it
+// only happens when the extractor runs through WithSiteExtractors; called
+// directly with another *http.Client, Extract leaves Article nil.
+func HackerNewsExtractor(followLinks bool) SiteExtractor {
+	return &hackerNewsExtractor{apiBase: "https://hacker-news.firebaseio.com/v0", followLinks: followLinks}
+}
+
+// Name implements SiteExtractor
+func (h *hackerNewsExtractor) Name() string {
+	return "hackernews"
+}
+
+// Match implements SiteExtractor
+func (h *hackerNewsExtractor) Match(u *url.URL) bool {
+	return hackerNewsItemID(u) > 0
+}
+
+// hackerNewsItemID returns the ID of the item u links to, or 0
+func hackerNewsItemID(u *url.URL) int {
+	if !strings.EqualFold(u.Hostname(), "news.ycombinator.com") || u.Path != "/item" {
+		return 0
+	}
+	id, err := strconv.Atoi(u.Query().Get("id"))
+	if err != nil || id <= 0 {
+		return 0
+	}
+	return id
+}
+
+// hackerNewsAPIItem is an item as returned by the Firebase API
+type hackerNewsAPIItem struct {
+	ID          int    `json:"id"`
+	Type        string `json:"type"`
+	By          string `json:"by"`
+	Time        int64  `json:"time"`
+	Title       string `json:"title"`
+	Text        string `json:"text"`
+	URL         string `json:"url"`
+	Score       int    `json:"score"`
+	Descendants int    `json:"descendants"`
+	Deleted     bool   `json:"deleted"`
+	Dead        bool   `json:"dead"`
+}
+
+// Extract implements SiteExtractor
+func (h *hackerNewsExtractor) Extract(ctx context.Context, client *http.Client, u *url.URL) (*Metadata, error) {
+	id := hackerNewsItemID(u)
+
+	// The API answers null for unknown items
+	var item *hackerNewsAPIItem
+	if err := getJSON(ctx, client, fmt.Sprintf("%s/item/%d.json", h.apiBase, id), nil, &item); err != nil {
+		return nil, err
+	}
+	if item == nil || item.Deleted || item.Dead {
+		return nil, errors.New("hacker news item not found")
+	}
+
+	metadata := &Metadata{
+		Title:           item.Title,
+		Description:     cleanText(stripTags(item.Text)),
+		CanonicalURL:    fmt.Sprintf("https://news.ycombinator.com/item?id=%d", item.ID),
+		ProviderName:    "Hacker News",
+		ProviderURL:     "https://news.ycombinator.com",
+		ProviderDisplay: "news.ycombinator.com",
+		SiteName:        "Hacker News",
+		Type:            "article",
+		Author:          item.By,
+		HackerNews: &HackerNewsItem{
+			ID:       item.ID,
+			Type:     item.Type,
+			Points:   item.Score,
+			Comments: item.Descendants,
+			LinkURL:  item.URL,
+		},
+	}
+	if item.Time > 0 {
+		published := time.Unix(item.Time, 0).UTC()
+		metadata.PublishedTime = published.Format(time.RFC3339)
+		metadata.PublishedAt = &published
+		metadata.PublishedTimeSource = DateSourceAPI
+	}
+
+	if h.followLinks && item.URL != "" && ctx.Value(hnChainKey{}) == nil {
+		h.extractArticle(ctx, client, metadata)
+	}
+
+	return metadata, nil
+}
+
+// extractArticle extracts the story's linked article with the client
+// behind httpClient, doing nothing for clients outside the pipeline
+func (h *hackerNewsExtractor) extractArticle(ctx context.Context, httpClient *http.Client, metadata *Metadata) {
+	transport, ok := httpClient.Transport.(*pipelineTransport)
+	if !ok {
+		return
+	}
+
+	article, err := transport.client.ExtractContext(context.WithValue(ctx, hnChainKey{}, true), metadata.HackerNews.LinkURL)
+	if err != nil {
+		metadata.addWarning(WarningLinkedArticleFailed, "%s: %v", metadata.HackerNews.LinkURL, err)
+		return
+	}
+
+	metadata.HackerNews.Article = article
+	if metadata.Description == "" {
+		metadata.Description = article.BestDescription()
+	}
+	if len(metadata.Images) == 0 {
+		// A copy, as the pipeline filters the item's images in place
+		metadata.Images = append([]Image(nil), article.Images...)
+	}
+}
@@ -0,0 +1,117 @@
+package urlmeta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func newHackerNewsServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/item/8863.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"by":"dhouston","descendants":71,"id":8863,"score":111,"time":1175714200,
+			"title":"My YC app: Dropbox - Throw away your USB drive","type":"story","url":%q}`, server.URL+"/article")
+	})
+	mux.HandleFunc("/item/121003.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"by":"tel","descendants":16,"id":121003,"score":25,"time":1203647620,
+			"text":"<i>or</i> HN: the Next Iteration<p>I'll get things started.","title":"Ask HN: The Arc Effect","type":"story"}`))
+	})
+	mux.HandleFunc("/item/1.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`null`))
+	})
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Dropbox</title>
+			<meta name="description" content="Sync your files">
+			<meta property="og:image" content="https://example.com/dropbox.png"></head></html>`))
+	})
+	return server
+}
+
+func TestHackerNewsItemID(t *testing.T) {
+	tests := []struct {
+		url  string
+		want int
+	}{
+		{"https://news.ycombinator.com/item?id=8863", 8863},
+		{"https://News.YCombinator.com/item?id=8863&p=2", 8863},
+		{"https://news.ycombinator.com/item?id=abc", 0},
+		{"https://news.ycombinator.com/user?id=pg", 0},
+		{"https://example.com/item?id=8863", 0},
+	}
+
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		if got := hackerNewsItemID(u); got != tt.want {
+			t.Errorf("hackerNewsItemID(%s) = %d, want %d", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestHackerNewsExtractor(t *testing.T) {
+	server := newHackerNewsServer(t)
+
+	client := NewClient(WithSiteExtractors(&hackerNewsExtractor{apiBase: server.URL}))
+	metadata, err := client.Extract("https://news.ycombinator.com/item?id=8863")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.Title != "My YC app: Dropbox - Throw away your USB drive" || metadata.Author != "dhouston" {
+		t.Errorf("Unexpected item fields: %q by %q", metadata.Title, metadata.Author)
+	}
+	item := metadata.HackerNews
+	if item == nil || item.Points != 111 || item.Comments != 71 || item.LinkURL != server.URL+"/article" {
+		t.Fatalf("Unexpected Hacker News details: %+v", item)
+	}
+	if item.Article != nil || metadata.Description != "" {
+		t.Error("Expected the linked article not to be extracted by default")
+	}
+
+	metadata, err = client.Extract("https://news.ycombinator.com/item?id=121003")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if metadata.Description != "or HN: the Next Iteration I'll get things started." {
+		t.Errorf("Expected the post text as description, got %q", metadata.Description)
+	}
+
+	missing, _ := url.Parse("https://news.ycombinator.com/item?id=1")
+	extractor := &hackerNewsExtractor{apiBase: server.URL}
+	if _, err := extractor.Extract(context.Background(), client.pipelineClient(), missing); err == nil {
+		t.Error("Expected a missing item to fail the extractor")
+	}
+}
+
+func TestHackerNewsExtractorFollowLinks(t *testing.T) {
+	server := newHackerNewsServer(t)
+
+	client := NewClient(WithSiteExtractors(&hackerNewsExtractor{apiBase: server.URL, followLinks: true}))
+	metadata, err := client.Extract("https://news.ycombinator.com/item?id=8863")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	article := metadata.HackerNews.Article
+	if article == nil || article.Title != "Dropbox" {
+		t.Fatalf("Expected the linked article, got %+v", article)
+	}
+	if metadata.Description != "Sync your files" || len(metadata.Images) != 1 {
+		t.Errorf("Expected the article to fill description and images, got %q, %+v", metadata.Description, metadata.Images)
+	}
+	if metadata.Title != "My YC app: Dropbox - Throw away your USB drive" {
+		t.Errorf("Expected the item title to be kept, got %q", metadata.Title)
+	}
+	// The item's images don't share the article's backing array
+	metadata.Images[0].URL = "https://example.com/changed.png"
+	if article.Images[0].URL == metadata.Images[0].URL {
+		t.Error("Expected the article images to be unaffected by changes to the item's")
+	}
+}
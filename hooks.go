@@ -0,0 +1,56 @@
+package urlmeta
+
+import (
+	"net/http"
+	"time"
+)
+
+// WithRequestHook calls hook with every outbound request just before it is
+// sent, including retries, oEmbed lookups and image requests (redirects
+// are followed within a single request). The hook may modify the request,
+// e.g. to add headers. Hooks run in the order added.
+func WithRequestHook(hook func(*http.Request)) Option {
+	return func(c *Client) {
+		c.requestHooks = append(c.requestHooks, hook)
+	}
+}
+
+// WithResponseHook calls hook with every response received and the time
+// taken to get its headers. The body has not been read yet and must not be
+// consumed by the hook. Requests that fail without a response don't reach
+// response hooks.
+func WithResponseHook(hook func(*http.Response, time.Duration)) Option {
+	return func(c *Client) {
+		c.responseHooks = append(c.responseHooks, hook)
+	}
+}
+
+// WithErrorHook calls hook whenever extraction of a URL fails, with the
+// requested URL and the error returned to the caller. Recoverable problems
+// recorded as warnings don't reach error hooks.
+func WithErrorHook(hook func(url string, err error)) Option {
+	return func(c *Client) {
+		c.errorHooks = append(c.errorHooks, hook)
+	}
+}
+
+// runRequestHooks calls the request hooks with req
+func (c *Client) runRequestHooks(req *http.Request) {
+	for _, hook := range c.requestHooks {
+		hook(req)
+	}
+}
+
+// runResponseHooks calls the response hooks with resp
+func (c *Client) runResponseHooks(resp *http.Response, elapsed time.Duration) {
+	for _, hook := range c.responseHooks {
+		hook(resp, elapsed)
+	}
+}
+
+// runErrorHooks calls the error hooks for a failed extraction
+func (c *Client) runErrorHooks(targetURL string, err error) {
+	for _, hook := range c.errorHooks {
+		hook(targetURL, err)
+	}
+}
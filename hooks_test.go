@@ -0,0 +1,88 @@
+package urlmeta
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRequestAndResponseHooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "k" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var order []string
+	var statuses []int
+
+	client := NewClient(
+		WithRequestHook(func(r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, "first")
+			r.Header.Set("X-Api-Key", "k")
+		}),
+		WithRequestHook(func(r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, "second")
+		}),
+		WithResponseHook(func(resp *http.Response, elapsed time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			statuses = append(statuses, resp.StatusCode)
+			if elapsed <= 0 {
+				t.Errorf("Expected positive elapsed time, got %v", elapsed)
+			}
+		}),
+	)
+
+	if _, err := client.Extract(server.URL); err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if len(order) != 2 || order[0] != "first" || order[1] != "second" {
+		t.Errorf("Expected hooks in order added, got %v", order)
+	}
+	if len(statuses) != 1 || statuses[0] != http.StatusOK {
+		t.Errorf("Expected one 200 response, got %v", statuses)
+	}
+}
+
+func TestErrorHook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	var gotURL string
+	var gotErr error
+	client := NewClient(WithErrorHook(func(url string, err error) {
+		gotURL, gotErr = url, err
+	}))
+
+	_, err := client.Extract(server.URL)
+	if err == nil {
+		t.Fatal("Expected error for 404 response")
+	}
+	if gotURL != server.URL || !errors.Is(gotErr, &ErrHTTPStatus{Code: http.StatusNotFound}) {
+		t.Errorf("Expected hook with %s and the 404 error, got %q, %v", server.URL, gotURL, gotErr)
+	}
+
+	gotURL, gotErr = "", nil
+	if _, err := client.Extract("ftp://example.com"); !errors.Is(err, ErrUnsupportedScheme) {
+		t.Fatalf("Expected ErrUnsupportedScheme, got %v", err)
+	}
+	if gotURL != "ftp://example.com" || !errors.Is(gotErr, ErrUnsupportedScheme) {
+		t.Errorf("Expected hook for validation errors, got %q, %v", gotURL, gotErr)
+	}
+}
@@ -0,0 +1,177 @@
+// Package httpapi serves urlmeta extraction over HTTP.
+//
+//	client := urlmeta.NewClient(urlmeta.WithSSRFProtection(true), urlmeta.WithCache(1000, time.Hour))
+//	http.Handle("/", httpapi.NewHandler(client, httpapi.Options{Timeout: 10 * time.Second}))
+//
+// GET /extract?url=https://example.com answers with the Metadata as JSON.
+// Errors answer with {"error": "..."} and a status chosen by
+// Options.ErrorStatus (DefaultErrorStatus by default). Results are cached
+// by the client (see urlmeta.WithCache and urlmeta.WithCacheStore); the
+// handler only adds Cache-Control headers.
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alfarisi/urlmeta"
+)
+
+// Options configures the handler. The zero value is usable.
+type Options struct {
+	// Timeout bounds each extraction (0 uses the client's timeouts only)
+	Timeout time.Duration
+
+	// MaxAge sets Cache-Control: public, max-age on successful responses
+	// (0 sends no Cache-Control header)
+	MaxAge time.Duration
+
+	// AllowRefresh lets callers skip the client cache with refresh=1
+	AllowRefresh bool
+
+	// AllowedHosts restricts the url parameter to these hosts, using the
+	// pattern syntax of urlmeta.WithAllowedHosts. Only the requested URL
+	// is checked; configure the client with WithAllowedHosts to cover
+	// redirects too.
+	AllowedHosts []string
+
+	// ErrorStatus maps extraction errors to HTTP status codes
+	// (DefaultErrorStatus when nil)
+	ErrorStatus func(error) int
+}
+
+// Handler serves the extraction API
+type Handler struct {
+	client *urlmeta.Client
+	opts   Options
+	mux    *http.ServeMux
+}
+
+// NewHandler returns a handler serving GET /extract with client. Mount it
+// under a prefix with http.StripPrefix.
+func NewHandler(client *urlmeta.Client, opts Options) *Handler {
+	if opts.ErrorStatus == nil {
+		opts.ErrorStatus = DefaultErrorStatus
+	}
+	hosts := make([]string, 0, len(opts.AllowedHosts))
+	for _, host := range opts.AllowedHosts {
+		if host = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), "."); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	opts.AllowedHosts = hosts
+
+	h := &Handler{client: client, opts: opts, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET /extract", h.extract)
+	return h
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// extract handles GET /extract?url=...
+func (h *Handler) extract(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	target := query.Get("url")
+	if target == "" {
+		writeError(w, http.StatusBadRequest, errors.New("missing url parameter"))
+		return
+	}
+	if err := h.checkHost(target); err != nil {
+		writeError(w, h.opts.ErrorStatus(err), err)
+		return
+	}
+
+	var reqOpts []urlmeta.RequestOption
+	if h.opts.Timeout > 0 {
+		reqOpts = append(reqOpts, urlmeta.WithRequestTimeout(h.opts.Timeout))
+	}
+	if h.opts.AllowRefresh {
+		if refresh, _ := strconv.ParseBool(query.Get("refresh")); refresh {
+			reqOpts = append(reqOpts, urlmeta.WithCacheBypass())
+		}
+	}
+
+	metadata, err := h.client.ExtractWithOptions(r.Context(), target, reqOpts...)
+	if err != nil {
+		writeError(w, h.opts.ErrorStatus(err), err)
+		return
+	}
+
+	if h.opts.MaxAge > 0 {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.opts.MaxAge.Seconds())))
+	}
+	writeJSON(w, http.StatusOK, metadata)
+}
+
+// checkHost applies Options.AllowedHosts to target
+func (h *Handler) checkHost(target string) error {
+	if len(h.opts.AllowedHosts) == 0 {
+		return nil
+	}
+
+	// Match the host the client will fetch: like ExtractContext, a URL
+	// without a scheme is taken as https
+	if !strings.Contains(target, "://") {
+		target = "https://" + target
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return fmt.Errorf("%w: %w", urlmeta.ErrInvalidURL, err)
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	for _, pattern := range h.opts.AllowedHosts {
+		if host == pattern || (strings.HasPrefix(pattern, "*.") && strings.HasSuffix(host, pattern[1:])) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s is not in the allowlist", urlmeta.ErrHostNotAllowed, host)
+}
+
+// DefaultErrorStatus maps urlmeta errors to HTTP status codes: 400 for bad
+// URLs, 403 for hosts and addresses the policy refuses, 422 for non-HTML
+// pages, 503 while a circuit breaker is open, 504 for timeouts and 502 for
+// any other upstream failure.
+func DefaultErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, urlmeta.ErrInvalidURL), errors.Is(err, urlmeta.ErrUnsupportedScheme):
+		return http.StatusBadRequest
+	case errors.Is(err, urlmeta.ErrHostNotAllowed), errors.Is(err, urlmeta.ErrBlockedAddress):
+		return http.StatusForbidden
+	case errors.Is(err, urlmeta.ErrUnsupportedContentType):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, urlmeta.ErrCircuitOpen):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, urlmeta.ErrTimeout):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+// errorResponse is the body of error responses
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// writeError writes err as a JSON error response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// writeJSON writes v as the JSON response body
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
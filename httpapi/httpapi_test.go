@@ -0,0 +1,166 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alfarisi/urlmeta"
+)
+
+const testPage = `<html><head><title>Test Page</title>
+<meta property="og:description" content="A test page"></head><body></body></html>`
+
+func newUpstream(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+			fallthrough
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(testPage))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func get(h http.Handler, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func extractPath(target string) string {
+	return "/extract?url=" + url.QueryEscape(target)
+}
+
+func TestExtract(t *testing.T) {
+	upstream := newUpstream(t)
+	h := NewHandler(urlmeta.NewClient(), Options{MaxAge: time.Hour})
+
+	rec := get(h, extractPath(upstream.URL))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("Expected JSON, got %s", ct)
+	}
+	if cc := rec.Header().Get("Cache-Control"); cc != "public, max-age=3600" {
+		t.Errorf("Expected Cache-Control, got %q", cc)
+	}
+
+	var metadata urlmeta.Metadata
+	if err := json.Unmarshal(rec.Body.Bytes(), &metadata); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if metadata.Title != "Test Page" || metadata.Description != "A test page" {
+		t.Errorf("Unexpected metadata: %+v", metadata)
+	}
+}
+
+func TestExtractErrors(t *testing.T) {
+	upstream := newUpstream(t)
+	h := NewHandler(urlmeta.NewClient(), Options{Timeout: 50 * time.Millisecond})
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"missing url", "/extract", http.StatusBadRequest},
+		{"bad scheme", extractPath("ftp://example.com/"), http.StatusBadRequest},
+		{"upstream 404", extractPath(upstream.URL + "/missing"), http.StatusBadGateway},
+		{"timeout", extractPath(upstream.URL + "/slow"), http.StatusGatewayTimeout},
+		{"unknown path", "/other", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get(h, tt.path)
+			if rec.Code != tt.status {
+				t.Errorf("Expected %d, got %d: %s", tt.status, rec.Code, rec.Body)
+			}
+		})
+	}
+
+	rec := get(h, "/extract")
+	var body errorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Error == "" {
+		t.Errorf("Expected a JSON error body, got %s", rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, extractPath(upstream.URL), nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for POST, got %d", rec.Code)
+	}
+}
+
+func TestAllowedHosts(t *testing.T) {
+	upstream := newUpstream(t)
+	h := NewHandler(urlmeta.NewClient(), Options{AllowedHosts: []string{"*.example.com", "127.0.0.1"}})
+
+	if rec := get(h, extractPath(upstream.URL)); rec.Code != http.StatusOK {
+		t.Errorf("Expected allowed host to pass, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec := get(h, extractPath("https://example.org/")); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403, got %d", rec.Code)
+	}
+
+	// URLs without a scheme are matched as https URLs
+	if rec := get(h, extractPath("example.org/page")); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a schemeless disallowed host, got %d", rec.Code)
+	}
+	if err := h.checkHost("www.example.com/page"); err != nil {
+		t.Errorf("Expected a schemeless allowed host to pass, got %v", err)
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	h := NewHandler(urlmeta.NewClient(), Options{
+		ErrorStatus: func(err error) int {
+			var httpErr *urlmeta.ErrHTTPStatus
+			if errors.As(err, &httpErr) {
+				return httpErr.Code
+			}
+			return DefaultErrorStatus(err)
+		},
+	})
+
+	upstream := newUpstream(t)
+	if rec := get(h, extractPath(upstream.URL+"/missing")); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected the custom mapping, got %d", rec.Code)
+	}
+}
+
+func TestDefaultErrorStatus(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{urlmeta.ErrInvalidURL, http.StatusBadRequest},
+		{fmt.Errorf("dial: %w", urlmeta.ErrBlockedAddress), http.StatusForbidden},
+		{urlmeta.ErrUnsupportedContentType, http.StatusUnprocessableEntity},
+		{urlmeta.ErrCircuitOpen, http.StatusServiceUnavailable},
+		{urlmeta.ErrTimeout, http.StatusGatewayTimeout},
+		{&urlmeta.ErrHTTPStatus{Code: 500}, http.StatusBadGateway},
+		{errors.New("connection refused"), http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		if got := DefaultErrorStatus(tt.err); got != tt.status {
+			t.Errorf("DefaultErrorStatus(%v) = %d, want %d", tt.err, got, tt.status)
+		}
+	}
+}
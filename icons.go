@@ -0,0 +1,119 @@
+package urlmeta
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Icon is a site icon declared with a <link> tag
+type Icon struct {
+	URL   string `json:"url"`
+	Sizes string `json:"sizes,omitempty"` // raw sizes attribute, e.g. "32x32 64x64" or "any"
+	Type  string `json:"type,omitempty"`
+	Rel   string `json:"rel"`
+}
+
+// iconRels lists the rel values recognized as icons
+var iconRels = map[string]bool{
+	"icon":                         true,
+	"shortcut icon":                true,
+	"apple-touch-icon":             true,
+	"apple-touch-icon-precomposed": true,
+	"mask-icon":                    true,
+}
+
+// appleTouchIconSize is the size Safari assumes for apple-touch-icon
+// links without a sizes attribute
+const appleTouchIconSize = 180
+
+// processIconLink records icon links in metadata.Icons
+func processIconLink(rel, linkType, sizes, href string, metadata *Metadata, baseURL *url.URL) {
+	rel = strings.ToLower(strings.Join(strings.Fields(rel), " "))
+	if !iconRels[rel] {
+		return
+	}
+
+	metadata.Icons = append(metadata.Icons, Icon{
+		URL:   resolveURL(href, baseURL),
+		Sizes: strings.TrimSpace(sizes),
+		Type:  strings.TrimSpace(linkType),
+		Rel:   rel,
+	})
+}
+
+// MaxSize returns the largest declared dimension of the icon in pixels,
+// 0 when unknown, and -1 when the icon is scalable (sizes="any")
+func (i Icon) MaxSize() int {
+	best := 0
+	for _, size := range strings.Fields(strings.ToLower(i.Sizes)) {
+		if size == "any" {
+			return -1
+		}
+		w, h, ok := strings.Cut(size, "x")
+		if !ok {
+			continue
+		}
+		width, _ := strconv.Atoi(w)
+		height, _ := strconv.Atoi(h)
+		if width > best {
+			best = width
+		}
+		if height > best {
+			best = height
+		}
+	}
+
+	if best == 0 && strings.HasPrefix(i.Rel, "apple-touch-icon") {
+		return appleTouchIconSize
+	}
+	return best
+}
+
+// BestIcon picks the most suitable icon for display at minSize pixels.
+// It prefers the smallest fixed-size icon at least minSize wide, then a
+// scalable icon, then the largest smaller one. Monochrome mask icons are
+// only used when nothing else is available. Returns nil when the page
+// declared no icons.
+func (m *Metadata) BestIcon(minSize int) *Icon {
+	var (
+		fit, scalable, largest, unknown, mask *Icon
+	)
+
+	for idx := range m.Icons {
+		icon := &m.Icons[idx]
+		if icon.Rel == "mask-icon" {
+			if mask == nil {
+				mask = icon
+			}
+			continue
+		}
+
+		size := icon.MaxSize()
+		switch {
+		case size < 0:
+			if scalable == nil {
+				scalable = icon
+			}
+		case size == 0:
+			if unknown == nil {
+				unknown = icon
+			}
+		case size >= minSize:
+			if fit == nil || size < fit.MaxSize() {
+				fit = icon
+			}
+		default:
+			if largest == nil || size > largest.MaxSize() {
+				largest = icon
+			}
+		}
+	}
+
+	for _, icon := range []*Icon{fit, scalable, largest, unknown, mask} {
+		if icon != nil {
+			return icon
+		}
+	}
+	return nil
+}
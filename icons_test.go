@@ -0,0 +1,122 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const mockHTMLIcons = `
+<!DOCTYPE html>
+<html>
+<head>
+	<title>Icons Test</title>
+	<link rel="icon" href="/favicon-32.png" sizes="32x32" type="image/png">
+	<link rel="icon" href="/favicon-192.png" sizes="192x192" type="image/png">
+	<link rel="Shortcut  Icon" href="/favicon.ico">
+	<link rel="apple-touch-icon" href="/apple-touch-icon.png">
+	<link rel="mask-icon" href="/mask.svg" color="#5bbad5">
+	<link rel="icon" href="/icon.svg" sizes="any" type="image/svg+xml">
+</head>
+<body></body>
+</html>
+`
+
+func TestExtractIcons(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLIcons))
+	}))
+	defer server.Close()
+
+	metadata, err := Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if len(metadata.Icons) != 6 {
+		t.Fatalf("Expected 6 icons, got %d: %+v", len(metadata.Icons), metadata.Icons)
+	}
+
+	first := metadata.Icons[0]
+	if first.URL != server.URL+"/favicon-32.png" || first.Sizes != "32x32" || first.Type != "image/png" || first.Rel != "icon" {
+		t.Errorf("Unexpected first icon: %+v", first)
+	}
+
+	if metadata.Icons[2].Rel != "shortcut icon" {
+		t.Errorf("Expected normalized rel 'shortcut icon', got '%s'", metadata.Icons[2].Rel)
+	}
+
+	// Favicon keeps its previous behavior: first icon/shortcut icon link
+	if metadata.Favicon != server.URL+"/favicon-32.png" {
+		t.Errorf("Expected favicon to be first icon link, got '%s'", metadata.Favicon)
+	}
+}
+
+func TestIconMaxSize(t *testing.T) {
+	tests := []struct {
+		icon     Icon
+		expected int
+	}{
+		{Icon{Sizes: "16x16"}, 16},
+		{Icon{Sizes: "16x16 48x48 32x32"}, 48},
+		{Icon{Sizes: "64X32"}, 64},
+		{Icon{Sizes: "any"}, -1},
+		{Icon{Sizes: ""}, 0},
+		{Icon{Sizes: "bogus"}, 0},
+		{Icon{Rel: "apple-touch-icon"}, 180},
+		{Icon{Rel: "apple-touch-icon", Sizes: "152x152"}, 152},
+	}
+
+	for _, tt := range tests {
+		if size := tt.icon.MaxSize(); size != tt.expected {
+			t.Errorf("MaxSize(%+v) = %d, expected %d", tt.icon, size, tt.expected)
+		}
+	}
+}
+
+func TestBestIcon(t *testing.T) {
+	metadata := &Metadata{Icons: []Icon{
+		{URL: "32.png", Sizes: "32x32", Rel: "icon"},
+		{URL: "192.png", Sizes: "192x192", Rel: "icon"},
+		{URL: "favicon.ico", Rel: "shortcut icon"},
+		{URL: "apple.png", Rel: "apple-touch-icon"},
+		{URL: "mask.svg", Rel: "mask-icon"},
+		{URL: "icon.svg", Sizes: "any", Rel: "icon"},
+	}}
+
+	tests := []struct {
+		minSize  int
+		expected string
+	}{
+		{16, "32.png"},
+		{64, "apple.png"},
+		{181, "192.png"},
+		{512, "icon.svg"},
+	}
+
+	for _, tt := range tests {
+		best := metadata.BestIcon(tt.minSize)
+		if best == nil || best.URL != tt.expected {
+			t.Errorf("BestIcon(%d) = %+v, expected %s", tt.minSize, best, tt.expected)
+		}
+	}
+
+	noScalable := &Metadata{Icons: []Icon{
+		{URL: "16.png", Sizes: "16x16", Rel: "icon"},
+		{URL: "32.png", Sizes: "32x32", Rel: "icon"},
+		{URL: "mask.svg", Rel: "mask-icon"},
+	}}
+	if best := noScalable.BestIcon(64); best == nil || best.URL != "32.png" {
+		t.Errorf("Expected largest smaller icon, got %+v", best)
+	}
+
+	onlyMask := &Metadata{Icons: []Icon{{URL: "mask.svg", Rel: "mask-icon"}}}
+	if best := onlyMask.BestIcon(16); best == nil || best.URL != "mask.svg" {
+		t.Errorf("Expected mask icon as last resort, got %+v", best)
+	}
+
+	if (&Metadata{}).BestIcon(16) != nil {
+		t.Error("Expected nil when no icons declared")
+	}
+}
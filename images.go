@@ -0,0 +1,284 @@
+package urlmeta
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ImageSource identifies where an image was declared
+type ImageSource string
+
+// Image sources, from most to least authoritative
+const (
+	ImageSourceOpenGraph ImageSource = "og"
+	ImageSourceOEmbed    ImageSource = "oembed"
+	ImageSourceAPI       ImageSource = "api"
+	ImageSourceTwitter   ImageSource = "twitter"
+	ImageSourceMicrodata ImageSource = "microdata"
+	ImageSourceBody      ImageSource = "body"
+)
+
+// imageSourceScores weights each source when ranking images
+var imageSourceScores = map[ImageSource]float64{
+	ImageSourceOpenGraph: 30,
+	ImageSourceOEmbed:    30,
+	ImageSourceAPI:       30,
+	ImageSourceTwitter:   20,
+	ImageSourceMicrodata: 10,
+}
+
+// minPreviewImageSize is the declared size below which BestImage treats an
+// image as an icon and never picks it
+const minPreviewImageSize = 50
+
+// ImageCriteria constrains the images considered by BestImage and
+// WithImageFilter. Zero fields are not checked. Images that don't declare
+// their dimensions always pass, since they can't be judged.
+type ImageCriteria struct {
+	MinWidth  int
+	MinHeight int
+	MinAspect float64 // minimum width/height ratio
+	MaxAspect float64 // maximum width/height ratio
+}
+
+// Matches reports whether img satisfies the criteria
+func (c ImageCriteria) Matches(img Image) bool {
+	if img.Width > 0 && img.Width < c.MinWidth {
+		return false
+	}
+	if img.Height > 0 && img.Height < c.MinHeight {
+		return false
+	}
+	if img.Width > 0 && img.Height > 0 {
+		aspect := float64(img.Width) / float64(img.Height)
+		if c.MinAspect > 0 && aspect < c.MinAspect {
+			return false
+		}
+		if c.MaxAspect > 0 && aspect > c.MaxAspect {
+			return false
+		}
+	}
+	return true
+}
+
+// WithImageFilter drops images declared smaller than minWidth x minHeight
+// from Metadata.Images. An optional aspectRange of [min] or [min, max]
+// width/height ratios also drops images outside that range, e.g.
+// WithImageFilter(200, 100, 1.0, 2.5) keeps only landscape images.
+func WithImageFilter(minWidth, minHeight int, aspectRange ...float64) Option {
+	return func(c *Client) {
+		criteria := &ImageCriteria{MinWidth: minWidth, MinHeight: minHeight}
+		if len(aspectRange) > 0 {
+			criteria.MinAspect = aspectRange[0]
+		}
+		if len(aspectRange) > 1 {
+			criteria.MaxAspect = aspectRange[1]
+		}
+		c.imageFilter = criteria
+	}
+}
+
+// processImages applies the client's image post-processing to metadata,
+// whichever strategy produced it
+func (c *Client) processImages(ctx context.Context, metadata *Metadata) {
+	if c.validateImages {
+		c.validateImageURLs(ctx, metadata)
+	}
+
+	if c.probeImages {
+		c.probeImageSizes(ctx, metadata)
+	}
+
+	if c.imageFilter != nil {
+		kept := metadata.Images[:0]
+		for _, img := range metadata.Images {
+			if c.imageFilter.Matches(img) {
+				kept = append(kept, img)
+			}
+		}
+		metadata.Images = kept
+	}
+
+	if c.paletteSize > 0 {
+		c.extractPalette(ctx, metadata)
+	}
+}
+
+// imageRequestConcurrency bounds parallel image requests per extraction
+const imageRequestConcurrency = 4
+
+// eachImage calls fn for every image, running up to imageRequestConcurrency
+// calls at once, and returns when all have finished
+func eachImage(images []Image, fn func(img *Image)) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, imageRequestConcurrency)
+
+	for idx := range images {
+		img := &images[idx]
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(img)
+		}()
+	}
+
+	wg.Wait()
+}
+
+// BestImage picks the most suitable preview image matching criteria. Images
+// are ranked by source (og/oEmbed over twitter over microdata over body),
+// declared size and aspect ratio, preferring landscape images between 1:1
+// and 2:1. Images declared smaller than 50px are never picked. Returns nil
+// when no image qualifies.
+func (m *Metadata) BestImage(criteria ImageCriteria) *Image {
+	var (
+		best      *Image
+		bestScore float64
+	)
+
+	for idx := range m.Images {
+		img := &m.Images[idx]
+		if (img.Width > 0 && img.Width < minPreviewImageSize) || (img.Height > 0 && img.Height < minPreviewImageSize) {
+			continue
+		}
+		if !criteria.Matches(*img) {
+			continue
+		}
+
+		if score := scoreImage(*img); best == nil || score > bestScore {
+			best, bestScore = img, score
+		}
+	}
+	return best
+}
+
+// scoreImage ranks an image for BestImage
+func scoreImage(img Image) float64 {
+	score := imageSourceScores[img.Source]
+
+	if img.Width <= 0 || img.Height <= 0 {
+		// Unknown size: assume a reasonable image, below a large known one
+		return score + 10
+	}
+
+	// Size contributes up to 40 points, saturating at 1200x630
+	score += math.Min(float64(img.Width*img.Height)/(1200*630), 1) * 40
+
+	aspect := float64(img.Width) / float64(img.Height)
+	switch {
+	case aspect >= 1 && aspect <= 2:
+		score += 10
+	case aspect < 0.5 || aspect > 3:
+		score -= 20
+	}
+	return score
+}
+
+// minBodyImageSize is the smallest width/height a body image may declare
+// to be considered by the body image fallback
+const minBodyImageSize = 100
+
+// decorativeImage matches src/class/id values of tracking pixels, spacers,
+// sprites and similar non-content images
+var decorativeImage = regexp.MustCompile(`(?i)pixel|tracking|tracker|spacer|blank\.gif|sprite|beacon|badge|avatar|emoji|logo`)
+
+// WithBodyImageFallback scans the page body for large <img> elements when
+// no og:image or twitter:image is declared, adding up to n of them to
+// Images, largest first. Images whose width/height attributes are below
+// 100px, tracking pixels, spacers, sprites and images inside navigation,
+// headers and footers are skipped. Images without declared dimensions
+// are ranked after sized ones. Zero disables the fallback (default).
+func WithBodyImageFallback(n int) Option {
+	return func(c *Client) {
+		c.bodyImageFallback = n
+	}
+}
+
+// bodyImageCandidate is an <img> found while scanning the body
+type bodyImageCandidate struct {
+	image Image
+	area  int
+}
+
+// scanBodyImages returns up to limit content images from doc, largest first
+func scanBodyImages(doc *html.Node, baseURL *url.URL, limit int) []Image {
+	var candidates []bodyImageCandidate
+	seen := make(map[string]bool)
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if strippedElements[n.DataAtom] || isUnlikelyCandidate(n) {
+				return
+			}
+			if n.DataAtom == atom.Img {
+				if candidate, ok := bodyImage(n, baseURL); ok && !seen[candidate.image.URL] {
+					seen[candidate.image.URL] = true
+					candidates = append(candidates, candidate)
+				}
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].area > candidates[j].area
+	})
+
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	images := make([]Image, 0, len(candidates))
+	for _, candidate := range candidates {
+		images = append(images, candidate.image)
+	}
+	return images
+}
+
+// bodyImage inspects an <img> and reports whether it looks like content
+func bodyImage(n *html.Node, baseURL *url.URL) (bodyImageCandidate, bool) {
+	src := strings.TrimSpace(getAttr(n, "src"))
+	if src == "" || strings.HasPrefix(src, "data:") {
+		// Lazy-loading scripts commonly keep the real source here
+		src = strings.TrimSpace(getAttr(n, "data-src"))
+	}
+	if src == "" || strings.HasPrefix(src, "data:") {
+		return bodyImageCandidate{}, false
+	}
+
+	if decorativeImage.MatchString(src + " " + getAttr(n, "class") + " " + getAttr(n, "id")) {
+		return bodyImageCandidate{}, false
+	}
+
+	width := parseInt(strings.TrimSuffix(strings.TrimSpace(getAttr(n, "width")), "px"))
+	height := parseInt(strings.TrimSuffix(strings.TrimSpace(getAttr(n, "height")), "px"))
+	if (width > 0 && width < minBodyImageSize) || (height > 0 && height < minBodyImageSize) {
+		return bodyImageCandidate{}, false
+	}
+
+	return bodyImageCandidate{
+		image: Image{
+			URL:    resolveURL(src, baseURL),
+			Width:  width,
+			Height: height,
+			Alt:    strings.TrimSpace(getAttr(n, "alt")),
+			Source: ImageSourceBody,
+		},
+		area: width * height,
+	}, true
+}
@@ -0,0 +1,164 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const mockHTMLBodyImages = `
+<!DOCTYPE html>
+<html>
+<head><title>Gallery</title></head>
+<body>
+	<header><img src="/header-banner.jpg" width="1200" height="300"></header>
+	<img src="/pixel.gif" width="1" height="1">
+	<img src="/sprites.png" width="400" height="400">
+	<img src="/thumb.jpg" width="50" height="50">
+	<img src="/unsized.jpg" alt="Unsized">
+	<img src="/medium.jpg" width="400" height="300" alt="Medium">
+	<img src="data:image/gif;base64,R0lGOD" data-src="/lazy.jpg" width="800" height="600">
+	<img src="/medium.jpg" width="400" height="300">
+</body>
+</html>
+`
+
+func TestBodyImageFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBodyImages))
+	}))
+	defer server.Close()
+
+	metadata, err := NewClient(WithBodyImageFallback(5)).Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	expected := []Image{
+		{URL: server.URL + "/lazy.jpg", Width: 800, Height: 600, Source: ImageSourceBody},
+		{URL: server.URL + "/medium.jpg", Width: 400, Height: 300, Alt: "Medium", Source: ImageSourceBody},
+		{URL: server.URL + "/unsized.jpg", Alt: "Unsized", Source: ImageSourceBody},
+	}
+
+	if len(metadata.Images) != len(expected) {
+		t.Fatalf("Expected %d images, got %d: %+v", len(expected), len(metadata.Images), metadata.Images)
+	}
+
+	for i, img := range metadata.Images {
+		if !reflect.DeepEqual(img, expected[i]) {
+			t.Errorf("Image %d: expected %+v, got %+v", i, expected[i], img)
+		}
+	}
+
+	limited, err := NewClient(WithBodyImageFallback(1)).Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if len(limited.Images) != 1 || limited.Images[0].URL != server.URL+"/lazy.jpg" {
+		t.Errorf("Expected only the largest image, got %+v", limited.Images)
+	}
+
+	plain, err := Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if len(plain.Images) != 0 {
+		t.Errorf("Expected no body images by default, got %+v", plain.Images)
+	}
+}
+
+func TestBodyImageFallbackSkippedWithMetaImage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><meta property="og:image" content="/og.jpg"></head>
+			<body><img src="/big.jpg" width="1000" height="1000"></body></html>`))
+	}))
+	defer server.Close()
+
+	metadata, err := NewClient(WithBodyImageFallback(3)).Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if len(metadata.Images) != 1 || metadata.Images[0].URL != server.URL+"/og.jpg" {
+		t.Errorf("Expected only og:image, got %+v", metadata.Images)
+	}
+}
+
+func TestBestImage(t *testing.T) {
+	metadata := &Metadata{
+		Images: []Image{
+			{URL: "logo.png", Width: 32, Height: 32, Source: ImageSourceOpenGraph},
+			{URL: "body.jpg", Width: 1200, Height: 630, Source: ImageSourceBody},
+			{URL: "twitter.jpg", Width: 1200, Height: 630, Source: ImageSourceTwitter},
+			{URL: "og-tall.jpg", Width: 300, Height: 1200, Source: ImageSourceOpenGraph},
+			{URL: "og.jpg", Width: 1200, Height: 630, Source: ImageSourceOpenGraph},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		criteria ImageCriteria
+		expected string
+	}{
+		{"og wins at equal size", ImageCriteria{}, "og.jpg"},
+		{"aspect range excludes landscape", ImageCriteria{MaxAspect: 0.5}, "og-tall.jpg"},
+		{"nothing large enough", ImageCriteria{MinWidth: 2000}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			best := metadata.BestImage(tt.criteria)
+			got := ""
+			if best != nil {
+				got = best.URL
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	icons := &Metadata{Images: []Image{{URL: "pixel.gif", Width: 1, Height: 1}}}
+	if best := icons.BestImage(ImageCriteria{}); best != nil {
+		t.Errorf("Expected tiny images to be ignored, got %+v", best)
+	}
+}
+
+func TestWithImageFilter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head>
+			<meta property="og:image" content="/icon.png">
+			<meta property="og:image:width" content="64">
+			<meta property="og:image:height" content="64">
+			<meta property="og:image" content="/banner.jpg">
+			<meta property="og:image:width" content="1500">
+			<meta property="og:image:height" content="300">
+			<meta property="og:image" content="/card.jpg">
+			<meta property="og:image:width" content="1200">
+			<meta property="og:image:height" content="630">
+			<meta property="og:image" content="/unsized.jpg">
+		</head></html>`))
+	}))
+	defer server.Close()
+
+	metadata, err := NewClient(WithImageFilter(200, 200, 1.0, 2.5)).Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var urls []string
+	for _, img := range metadata.Images {
+		urls = append(urls, img.URL)
+	}
+
+	expected := []string{server.URL + "/card.jpg", server.URL + "/unsized.jpg"}
+	if len(urls) != len(expected) || urls[0] != expected[0] || urls[1] != expected[1] {
+		t.Errorf("Expected %v, got %v", expected, urls)
+	}
+}
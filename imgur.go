@@ -0,0 +1,37 @@
+package urlmeta
+
+import (
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// imgurImageEmbed returns a "photo" oEmbed for a direct i.imgur.com image,
+// which is an image rather than a page, or nil for any other URL
+func imgurImageEmbed(u *url.URL) *OEmbed {
+	if !strings.EqualFold(u.Hostname(), "i.imgur.com") || imageTypeFromURL(u.String()) == "" {
+		return nil
+	}
+	return &OEmbed{
+		Type:         "photo",
+		Version:      "1.0",
+		URL:          u.String(),
+		ProviderName: "Imgur",
+		ProviderURL:  "https://imgur.com",
+	}
+}
+
+// imageTypeFromURL returns the image MIME type for the file extension of
+// rawURL (e.g. "image/png" for .png), or ""
+func imageTypeFromURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	mediaType, _, _ := strings.Cut(mime.TypeByExtension(strings.ToLower(path.Ext(u.Path))), ";")
+	if !strings.HasPrefix(mediaType, "image/") {
+		return ""
+	}
+	return mediaType
+}
@@ -0,0 +1,62 @@
+package urlmeta
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestImgurImageEmbed(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://i.imgur.com/abc123.jpg", true},
+		{"https://i.imgur.com/abc123.PNG", true},
+		{"https://i.imgur.com/abc123.gifv", false},
+		{"https://i.imgur.com/abc123.mp4", false},
+		{"https://imgur.com/abc123.jpg", false},
+		{"https://imgur.com/gallery/abc123", false},
+	}
+
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		oembed := imgurImageEmbed(u)
+		if (oembed != nil) != tt.want {
+			t.Errorf("imgurImageEmbed(%s) = %+v, want embed: %v", tt.url, oembed, tt.want)
+			continue
+		}
+		if oembed != nil && (oembed.Type != "photo" || oembed.URL != tt.url) {
+			t.Errorf("imgurImageEmbed(%s): unexpected oEmbed %+v", tt.url, oembed)
+		}
+	}
+}
+
+func TestExtractImgurImage(t *testing.T) {
+	// The image itself is never fetched as a page
+	metadata, err := NewClient().Extract("https://i.imgur.com/abc123.png")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if metadata.Type != "photo" || metadata.ProviderName != "Imgur" {
+		t.Errorf("Expected an Imgur photo, got type %q from %q", metadata.Type, metadata.ProviderName)
+	}
+	if len(metadata.Images) != 1 || metadata.Images[0].Type != "image/png" {
+		t.Errorf("Expected the image with its content type, got %+v", metadata.Images)
+	}
+}
+
+func TestImageTypeFromURL(t *testing.T) {
+	tests := map[string]string{
+		"https://example.com/a.jpg":        "image/jpeg",
+		"https://example.com/a.webp?w=100": "image/webp",
+		"https://example.com/a.gif":        "image/gif",
+		"https://example.com/a.mp4":        "",
+		"https://example.com/a":            "",
+	}
+
+	for rawURL, want := range tests {
+		if got := imageTypeFromURL(rawURL); got != want {
+			t.Errorf("imageTypeFromURL(%s) = %q, want %q", rawURL, got, want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package urlmeta
+
+import (
+	"net/http"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// languageSampleWords caps how much body text the detector looks at
+const languageSampleWords = 2000
+
+// minLanguageMatches is the fewest stopword hits needed to trust a guess
+const minLanguageMatches = 5
+
+// languageStopwords lists very frequent short words of Latin-script
+// languages; the language with the most hits in the text wins
+var languageStopwords = map[string][]string{
+	"en": {"the", "and", "of", "to", "is", "in", "that", "it", "was", "for", "with", "you", "this", "are", "on", "have", "be", "not"},
+	"es": {"el", "la", "de", "que", "y", "en", "los", "se", "del", "las", "por", "un", "una", "para", "con", "es", "no", "lo"},
+	"fr": {"le", "la", "les", "de", "des", "et", "est", "un", "une", "du", "que", "qui", "dans", "pour", "pas", "sur", "au", "avec"},
+	"de": {"der", "die", "und", "das", "ist", "nicht", "ein", "eine", "zu", "den", "mit", "von", "sich", "des", "auf", "für", "im", "dem"},
+	"it": {"il", "di", "che", "e", "la", "per", "un", "non", "sono", "una", "gli", "con", "del", "della", "le", "si", "da", "questo"},
+	"pt": {"o", "de", "que", "e", "do", "da", "em", "um", "para", "com", "não", "uma", "os", "no", "se", "na", "por", "mais"},
+	"nl": {"de", "het", "een", "en", "van", "is", "dat", "niet", "op", "te", "zijn", "met", "voor", "ook", "maar", "er", "die", "wat"},
+	"id": {"yang", "dan", "di", "ini", "itu", "dengan", "untuk", "tidak", "dari", "dalam", "akan", "pada", "juga", "saya", "ke", "karena", "ada", "bisa"},
+	"sv": {"och", "att", "det", "som", "en", "på", "är", "av", "för", "med", "till", "den", "har", "inte", "om", "ett", "var", "jag"},
+	"pl": {"i", "w", "nie", "na", "się", "z", "jest", "że", "do", "to", "jak", "ale", "po", "co", "tak", "za", "od", "czy"},
+	"tr": {"ve", "bir", "bu", "da", "de", "için", "ile", "çok", "olarak", "daha", "gibi", "ne", "ama", "olan", "kadar", "sonra", "her", "en"},
+}
+
+// languageScripts maps writing systems to the language most commonly
+// written in them on the web
+var languageScripts = []struct {
+	table    *unicode.RangeTable
+	language string
+}{
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Hangul, "ko"},
+	{unicode.Han, "zh"},
+	{unicode.Arabic, "ar"},
+	{unicode.Hebrew, "he"},
+	{unicode.Greek, "el"},
+	{unicode.Thai, "th"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Cyrillic, "ru"},
+}
+
+// stopwordLanguages indexes languageStopwords by word
+var stopwordLanguages = func() map[string][]string {
+	index := make(map[string][]string)
+	for lang, words := range languageStopwords {
+		for _, w := range words {
+			index[w] = append(index[w], lang)
+		}
+	}
+	return index
+}()
+
+// WithLanguageDetection guesses Metadata.Language from the body text when
+// the page declares no language (no <html lang>, Content-Language header or
+// og:locale). The detector is deliberately lightweight: it recognizes
+// common non-Latin scripts and a dozen Latin-script languages by their
+// most frequent words, and leaves Language empty when unsure.
+func WithLanguageDetection(enabled bool) Option {
+	return func(c *Client) {
+		c.detectLanguage = enabled
+	}
+}
+
+// pageLanguage returns the declared page language, trying <html lang>, the
+// Content-Language header and og:locale in that order
+func pageLanguage(doc *html.Node, header http.Header, locale string) string {
+	if root := findElement(doc, atom.Html); root != nil {
+		if lang := normalizeLanguageTag(getAttr(root, "lang")); lang != "" {
+			return lang
+		}
+	}
+
+	// Content-Language may list several languages; the first is primary
+	if lang := normalizeLanguageTag(strings.SplitN(header.Get("Content-Language"), ",", 2)[0]); lang != "" {
+		return lang
+	}
+
+	return normalizeLanguageTag(locale)
+}
+
+// normalizeLanguageTag turns "en_US" style locales into BCP 47 "en-US"
+func normalizeLanguageTag(tag string) string {
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	if tag == "" {
+		return ""
+	}
+
+	parts := strings.Split(tag, "-")
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) == 2 {
+			parts[i] = strings.ToUpper(parts[i])
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// detectTextLanguage guesses the language of the body text of doc
+func detectTextLanguage(doc *html.Node) string {
+	body := findElement(doc, atom.Body)
+	if body == nil {
+		return ""
+	}
+
+	var words []string
+	collectWords(body, &words)
+	if len(words) == 0 {
+		return ""
+	}
+
+	if lang := scriptLanguage(words); lang != "" {
+		return lang
+	}
+
+	hits := make(map[string]int)
+	for _, w := range words {
+		for _, lang := range stopwordLanguages[strings.ToLower(w)] {
+			hits[lang]++
+		}
+	}
+
+	best, bestHits, runnerUp := "", 0, 0
+	for lang, n := range hits {
+		switch {
+		case n > bestHits || (n == bestHits && lang < best):
+			best, bestHits, runnerUp = lang, n, bestHits
+		case n > runnerUp:
+			runnerUp = n
+		}
+	}
+
+	// Require a clear winner, since close languages share many stopwords
+	if bestHits < minLanguageMatches || bestHits == runnerUp {
+		return ""
+	}
+	return best
+}
+
+// scriptLanguage returns the language of the dominant non-Latin script
+// among words, or "" when most letters are Latin
+func scriptLanguage(words []string) string {
+	counts := make(map[string]int)
+	letters := 0
+
+	for _, w := range words {
+		for _, r := range w {
+			if !unicode.IsLetter(r) {
+				continue
+			}
+			letters++
+			for _, script := range languageScripts {
+				if unicode.Is(script.table, r) {
+					counts[script.language]++
+					break
+				}
+			}
+		}
+	}
+
+	// Japanese text mixes kana with Han, so any kana tips it to Japanese
+	if counts["ja"] > 0 && counts["ja"]+counts["zh"] > letters/2 {
+		return "ja"
+	}
+
+	for _, script := range languageScripts {
+		if counts[script.language] > letters/2 {
+			return script.language
+		}
+	}
+	return ""
+}
+
+// collectWords appends up to languageSampleWords words of visible text
+func collectWords(n *html.Node, words *[]string) {
+	if len(*words) >= languageSampleWords {
+		return
+	}
+
+	switch n.Type {
+	case html.TextNode:
+		for _, w := range strings.FieldsFunc(n.Data, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsMark(r)
+		}) {
+			if len(*words) >= languageSampleWords {
+				return
+			}
+			*words = append(*words, w)
+		}
+		return
+	case html.ElementNode:
+		if nonContentElements[n.DataAtom] {
+			return
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		collectWords(c, words)
+	}
+}
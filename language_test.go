@@ -0,0 +1,127 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestPageLanguage(t *testing.T) {
+	tests := []struct {
+		name            string
+		page            string
+		contentLanguage string
+		expected        string
+	}{
+		{
+			name:            "html lang wins",
+			page:            `<html lang="en_gb"><head><meta property="og:locale" content="fr_FR"></head></html>`,
+			contentLanguage: "de",
+			expected:        "en-GB",
+		},
+		{
+			name:            "content-language header",
+			page:            `<html><head><meta property="og:locale" content="fr_FR"></head></html>`,
+			contentLanguage: "de-DE, en",
+			expected:        "de-DE",
+		},
+		{
+			name:     "og:locale",
+			page:     `<html><head><meta property="og:locale" content="fr_FR"></head></html>`,
+			expected: "fr-FR",
+		},
+		{
+			name:     "undeclared",
+			page:     `<html><head></head><body>` + strings.Repeat("the cat and the dog is in the house ", 5) + `</body></html>`,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+				if tt.contentLanguage != "" {
+					w.Header().Set("Content-Language", tt.contentLanguage)
+				}
+				w.Write([]byte(tt.page))
+			}))
+			defer server.Close()
+
+			metadata, err := Extract(server.URL)
+			if err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+
+			if metadata.Language != tt.expected {
+				t.Errorf("Expected language %q, got %q", tt.expected, metadata.Language)
+			}
+		})
+	}
+}
+
+func TestLanguageDetection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head></head><body><p>` +
+			strings.Repeat("Ini adalah artikel yang ditulis dengan bahasa dan untuk pembaca di sini. ", 3) +
+			`</p></body></html>`))
+	}))
+	defer server.Close()
+
+	metadata, err := NewClient(WithLanguageDetection(true)).Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.Language != "id" {
+		t.Errorf("Expected detected language 'id', got %q", metadata.Language)
+	}
+}
+
+func TestDetectTextLanguage(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"The quick brown fox jumps over the lazy dog, and it is not the first time that this is happening to the dog.", "en"},
+		{"El perro de la casa es el mejor amigo que se puede tener, y los niños lo quieren para siempre con el corazón.", "es"},
+		{"Le chat est dans la maison et les enfants jouent avec le chien dans le jardin pour la journée.", "fr"},
+		{"Der Hund und die Katze sind nicht im Haus, das ist ein Problem für den Mann mit dem Garten.", "de"},
+		{"東京は日本の首都です。たくさんの人が住んでいます。", "ja"},
+		{"北京是中华人民共和国的首都。", "zh"},
+		{"서울은 대한민국의 수도입니다.", "ko"},
+		{"Москва является столицей России.", "ru"},
+		{"Lorem ipsum", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		doc, err := html.Parse(strings.NewReader("<body><p>" + tt.text + "</p><script>var the = 'and the of to';</script></body>"))
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		if got := detectTextLanguage(doc); got != tt.expected {
+			t.Errorf("detectTextLanguage(%q) = %q, expected %q", tt.text, got, tt.expected)
+		}
+	}
+}
+
+func TestNormalizeLanguageTag(t *testing.T) {
+	tests := map[string]string{
+		"en_US":   "en-US",
+		"EN":      "en",
+		" pt-br ": "pt-BR",
+		"zh-Hant": "zh-Hant",
+		"":        "",
+	}
+
+	for input, expected := range tests {
+		if got := normalizeLanguageTag(input); got != expected {
+			t.Errorf("normalizeLanguageTag(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
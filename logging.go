@@ -0,0 +1,38 @@
+package urlmeta
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WithLogger makes the client log what it does: strategy selection,
+// redirects, oEmbed endpoint resolution, cache hits, retries and circuit
+// breaker rejections at debug level, and retries, extraction warnings and
+// failures at info level. Useful to find out why a URL produced an empty
+// preview. Nothing is logged by default.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// logDebug logs a debug event when a logger is configured
+func (c *Client) logDebug(ctx context.Context, msg string, args ...interface{}) {
+	if c.logger != nil {
+		c.logger.DebugContext(ctx, msg, args...)
+	}
+}
+
+// logInfo logs an info event when a logger is configured
+func (c *Client) logInfo(ctx context.Context, msg string, args ...interface{}) {
+	if c.logger != nil {
+		c.logger.InfoContext(ctx, msg, args...)
+	}
+}
+
+// logWarnings logs the warnings recorded while extracting targetURL
+func (c *Client) logWarnings(ctx context.Context, targetURL string, metadata *Metadata) {
+	for _, w := range metadata.Warnings {
+		c.logInfo(ctx, "urlmeta: extraction warning", "url", targetURL, "code", string(w.Code), "message", w.Message)
+	}
+}
@@ -0,0 +1,78 @@
+package urlmeta
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithLogger(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/flaky", http.StatusMovedPermanently)
+		case "/flaky":
+			if requests.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><head><title>Logged</title><meta property="og:image" content="http://[::1"></head></html>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	client := NewClient(
+		WithLogger(logger),
+		WithRetry(2, func(int) time.Duration { return 0 }),
+		WithCache(10, time.Minute),
+		WithStrictMode(false),
+	)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Extract(server.URL + "/old"); err != nil {
+			t.Fatalf("Extract failed: %v", err)
+		}
+	}
+	client.Extract(server.URL + "/missing")
+
+	out := buf.String()
+	for _, event := range []string{
+		"extraction strategy selected",
+		"strategy=html_only",
+		"following redirect",
+		"retrying request",
+		"extraction warning",
+		"cache hit",
+		"extraction failed",
+	} {
+		if !strings.Contains(out, event) {
+			t.Errorf("Expected log to contain %q, got:\n%s", event, out)
+		}
+	}
+}
+
+func TestExtractionStrategyString(t *testing.T) {
+	tests := map[ExtractionStrategy]string{
+		StrategyAuto:           "auto",
+		StrategyOEmbedFirst:    "oembed_first",
+		StrategyHTMLOnly:       "html_only",
+		ExtractionStrategy(42): "ExtractionStrategy(42)",
+	}
+	for strategy, expected := range tests {
+		if got := strategy.String(); got != expected {
+			t.Errorf("Expected %q, got %q", expected, got)
+		}
+	}
+}
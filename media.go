@@ -0,0 +1,66 @@
+package urlmeta
+
+import "net/url"
+
+// VideoInfo holds video:* Open Graph properties describing a video page
+// (og:type video.movie, video.episode, ...)
+type VideoInfo struct {
+	Duration    int      `json:"duration,omitempty"` // seconds
+	ReleaseDate string   `json:"release_date,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// MusicInfo holds music:* Open Graph properties describing a song or album page
+type MusicInfo struct {
+	Duration  int      `json:"duration,omitempty"` // seconds
+	Album     string   `json:"album,omitempty"`    // album URL
+	Musicians []string `json:"musicians,omitempty"`
+}
+
+// videoInfo returns metadata.VideoInfo, allocating it on first use
+func (m *Metadata) videoInfo() *VideoInfo {
+	if m.VideoInfo == nil {
+		m.VideoInfo = &VideoInfo{}
+	}
+	return m.VideoInfo
+}
+
+// musicInfo returns metadata.Music, allocating it on first use
+func (m *Metadata) musicInfo() *MusicInfo {
+	if m.Music == nil {
+		m.Music = &MusicInfo{}
+	}
+	return m.Music
+}
+
+// processMediaInfo handles the video: and music: Open Graph namespaces,
+// reporting whether property was one of them
+func processMediaInfo(property, content string, metadata *Metadata, baseURL *url.URL) bool {
+	switch property {
+	case "video:duration":
+		if seconds := parseInt(content); seconds > 0 {
+			metadata.videoInfo().Duration = seconds
+		}
+	case "video:release_date":
+		if v := metadata.videoInfo(); v.ReleaseDate == "" {
+			v.ReleaseDate = content
+		}
+	case "video:tag":
+		v := metadata.videoInfo()
+		v.Tags = append(v.Tags, content)
+	case "music:duration":
+		if seconds := parseInt(content); seconds > 0 {
+			metadata.musicInfo().Duration = seconds
+		}
+	case "music:album", "music:album:url":
+		if m := metadata.musicInfo(); m.Album == "" {
+			m.Album = resolveURL(content, baseURL)
+		}
+	case "music:musician":
+		m := metadata.musicInfo()
+		m.Musicians = append(m.Musicians, content)
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestExtractVideoInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head>
+			<meta property="og:type" content="video.movie">
+			<meta property="og:video" content="https://example.com/movie.mp4">
+			<meta property="video:duration" content="5400">
+			<meta property="video:release_date" content="2024-05-01">
+			<meta property="video:tag" content="drama">
+			<meta property="video:tag" content="thriller">
+		</head></html>`))
+	}))
+	defer server.Close()
+
+	metadata, err := Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	expected := &VideoInfo{Duration: 5400, ReleaseDate: "2024-05-01", Tags: []string{"drama", "thriller"}}
+	if !reflect.DeepEqual(metadata.VideoInfo, expected) {
+		t.Errorf("Expected video info %+v, got %+v", expected, metadata.VideoInfo)
+	}
+
+	if len(metadata.Videos) != 1 {
+		t.Errorf("Expected og:video to still be collected, got %d videos", len(metadata.Videos))
+	}
+
+	if metadata.Music != nil {
+		t.Errorf("Expected nil music info, got %+v", metadata.Music)
+	}
+}
+
+func TestExtractMusicInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head>
+			<meta property="og:type" content="music.song">
+			<meta property="music:duration" content="215">
+			<meta property="music:album" content="/album/42">
+			<meta property="music:musician" content="https://example.com/artist/1">
+			<meta property="music:musician" content="https://example.com/artist/2">
+		</head></html>`))
+	}))
+	defer server.Close()
+
+	metadata, err := Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	expected := &MusicInfo{
+		Duration:  215,
+		Album:     server.URL + "/album/42",
+		Musicians: []string{"https://example.com/artist/1", "https://example.com/artist/2"},
+	}
+	if !reflect.DeepEqual(metadata.Music, expected) {
+		t.Errorf("Expected music info %+v, got %+v", expected, metadata.Music)
+	}
+}
@@ -0,0 +1,136 @@
+package urlmeta
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Precedence decides which of two results wins a field both of them set
+type Precedence int
+
+const (
+	// PreferReceiver keeps the receiver's value; the other result only
+	// fills fields the receiver left empty
+	PreferReceiver Precedence = iota
+
+	// PreferOther replaces the receiver's value with the other result's
+	// whenever the latter is set
+	PreferOther
+)
+
+// MergePolicy controls Metadata.Merge. The zero value keeps the
+// receiver's values and fills its gaps from the other result.
+type MergePolicy struct {
+	// Default applies to fields not listed in Fields
+	Default Precedence
+
+	// Fields overrides Default per field, keyed by JSON name (e.g.
+	// "title", "description", "images")
+	Fields map[string]Precedence
+
+	// AppendLists combines list fields (images, videos, keywords, icons,
+	// feeds, ...) instead of picking one side: the preferred side's items
+	// come first, followed by the other side's items not already present.
+	// Items are matched by URL when they have one.
+	AppendLists bool
+}
+
+// precedence returns the precedence for the field with JSON name field
+func (p MergePolicy) precedence(field string) Precedence {
+	if prec, ok := p.Fields[field]; ok {
+		return prec
+	}
+	return p.Default
+}
+
+// Merge combines m with other, e.g. oEmbed data with the page's own meta
+// tags or the output of a custom extractor, and returns the result. Empty
+// fields never override set ones; when both are set, policy decides.
+// Warnings from both sides are kept. Neither m nor other is modified,
+// though nested values (OEmbed, Product, ...) are shared rather than
+// copied.
+func (m *Metadata) Merge(other *Metadata, policy MergePolicy) *Metadata {
+	merged := &Metadata{}
+	if m != nil {
+		*merged = *m
+	}
+	if other == nil {
+		return merged
+	}
+
+	dst := reflect.ValueOf(merged).Elem()
+	src := reflect.ValueOf(other).Elem()
+	fields := dst.Type()
+
+	for i := 0; i < fields.NumField(); i++ {
+		name := jsonFieldName(fields.Field(i))
+		d, s := dst.Field(i), src.Field(i)
+
+		switch {
+		case isEmptyValue(s):
+			continue
+		case name == "warnings":
+			d.Set(appendMissing(appendMissing(emptySliceOf(d), d), s))
+		case isEmptyValue(d):
+			d.Set(s)
+		case policy.AppendLists && d.Kind() == reflect.Slice:
+			first, second := d, s
+			if policy.precedence(name) == PreferOther {
+				first, second = s, d
+			}
+			d.Set(appendMissing(appendMissing(emptySliceOf(d), first), second))
+		case policy.precedence(name) == PreferOther:
+			d.Set(s)
+		}
+	}
+
+	return merged
+}
+
+// jsonFieldName returns the JSON name of a struct field
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// isEmptyValue reports whether v is unset: zero, or an empty list
+func isEmptyValue(v reflect.Value) bool {
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Map {
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// emptySliceOf returns a new empty slice of v's type
+func emptySliceOf(v reflect.Value) reflect.Value {
+	return reflect.MakeSlice(v.Type(), 0, v.Len())
+}
+
+// appendMissing appends the items of src not already in dst
+func appendMissing(dst, src reflect.Value) reflect.Value {
+	for i := 0; i < src.Len(); i++ {
+		item := src.Index(i)
+		found := false
+		for j := 0; j < dst.Len() && !found; j++ {
+			found = sameItem(dst.Index(j), item)
+		}
+		if !found {
+			dst = reflect.Append(dst, item)
+		}
+	}
+	return dst
+}
+
+// sameItem reports whether two list items are the same: equal URL fields
+// for structs that have one, equal values otherwise
+func sameItem(a, b reflect.Value) bool {
+	if a.Kind() == reflect.Struct {
+		if au := a.FieldByName("URL"); au.IsValid() && au.Kind() == reflect.String {
+			return au.String() == b.FieldByName("URL").String()
+		}
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
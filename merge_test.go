@@ -0,0 +1,116 @@
+package urlmeta
+
+import (
+	"reflect"
+	"testing"
+)
+
+func mergeFixtures() (*Metadata, *Metadata) {
+	fromOEmbed := &Metadata{
+		Title:        "oEmbed Title",
+		ProviderName: "VideoSite",
+		Images:       []Image{{URL: "https://cdn.example.com/thumb.jpg", Source: ImageSourceOEmbed}},
+		Keywords:     []string{},
+		OEmbed:       &OEmbed{Type: "video", HTML: "<iframe></iframe>"},
+		Warnings:     []Warning{{Code: WarningInvalidImageURL, Message: "a"}},
+	}
+	fromHTML := &Metadata{
+		Title:       "HTML Title",
+		Description: "From the page",
+		Images: []Image{
+			{URL: "https://cdn.example.com/thumb.jpg", Source: ImageSourceOpenGraph},
+			{URL: "https://cdn.example.com/og.jpg", Source: ImageSourceOpenGraph},
+		},
+		Keywords: []string{"go", "video"},
+		Warnings: []Warning{{Code: WarningBodyTruncated, Message: "b"}},
+	}
+	return fromOEmbed, fromHTML
+}
+
+func TestMergeFillsGaps(t *testing.T) {
+	fromOEmbed, fromHTML := mergeFixtures()
+
+	merged := fromOEmbed.Merge(fromHTML, MergePolicy{})
+
+	if merged.Title != "oEmbed Title" {
+		t.Errorf("Expected receiver title to win, got %q", merged.Title)
+	}
+	if merged.Description != "From the page" {
+		t.Errorf("Expected description filled from other, got %q", merged.Description)
+	}
+	if merged.ProviderName != "VideoSite" || merged.OEmbed == nil {
+		t.Error("Expected receiver-only fields to be kept")
+	}
+	if len(merged.Images) != 1 || merged.Images[0].Source != ImageSourceOEmbed {
+		t.Errorf("Expected receiver images to win, got %+v", merged.Images)
+	}
+	if !reflect.DeepEqual(merged.Keywords, []string{"go", "video"}) {
+		t.Errorf("Expected empty keywords filled from other, got %v", merged.Keywords)
+	}
+	if len(merged.Warnings) != 2 {
+		t.Errorf("Expected warnings from both sides, got %+v", merged.Warnings)
+	}
+
+	// Inputs are left alone
+	if fromOEmbed.Description != "" || len(fromOEmbed.Warnings) != 1 || len(fromHTML.Warnings) != 1 {
+		t.Error("Expected Merge not to modify its inputs")
+	}
+}
+
+func TestMergePrecedence(t *testing.T) {
+	fromOEmbed, fromHTML := mergeFixtures()
+
+	merged := fromOEmbed.Merge(fromHTML, MergePolicy{
+		Default: PreferOther,
+		Fields:  map[string]Precedence{"images": PreferReceiver},
+	})
+
+	if merged.Title != "HTML Title" {
+		t.Errorf("Expected other title to win, got %q", merged.Title)
+	}
+	if merged.ProviderName != "VideoSite" {
+		t.Errorf("Expected an empty field not to override, got %q", merged.ProviderName)
+	}
+	if len(merged.Images) != 1 || merged.Images[0].Source != ImageSourceOEmbed {
+		t.Errorf("Expected per-field precedence for images, got %+v", merged.Images)
+	}
+}
+
+func TestMergeAppendLists(t *testing.T) {
+	fromOEmbed, fromHTML := mergeFixtures()
+
+	merged := fromOEmbed.Merge(fromHTML, MergePolicy{AppendLists: true})
+	want := []string{"https://cdn.example.com/thumb.jpg", "https://cdn.example.com/og.jpg"}
+	if len(merged.Images) != len(want) {
+		t.Fatalf("Expected %d images, got %+v", len(want), merged.Images)
+	}
+	for i, url := range want {
+		if merged.Images[i].URL != url {
+			t.Errorf("Image %d: expected %s, got %s", i, url, merged.Images[i].URL)
+		}
+	}
+	if merged.Images[0].Source != ImageSourceOEmbed {
+		t.Errorf("Expected the receiver's copy of a shared image, got %s", merged.Images[0].Source)
+	}
+
+	merged = fromOEmbed.Merge(fromHTML, MergePolicy{AppendLists: true, Default: PreferOther})
+	if len(merged.Images) != 2 || merged.Images[0].Source != ImageSourceOpenGraph {
+		t.Errorf("Expected the other side's images first, got %+v", merged.Images)
+	}
+	if len(fromOEmbed.Images) != 1 {
+		t.Error("Expected Merge not to modify the receiver's images")
+	}
+}
+
+func TestMergeNil(t *testing.T) {
+	fromOEmbed, _ := mergeFixtures()
+
+	if merged := fromOEmbed.Merge(nil, MergePolicy{}); merged == fromOEmbed || merged.Title != fromOEmbed.Title {
+		t.Errorf("Expected a copy of the receiver, got %+v", merged)
+	}
+
+	var empty *Metadata
+	if merged := empty.Merge(fromOEmbed, MergePolicy{}); merged.Title != fromOEmbed.Title {
+		t.Errorf("Expected other's values on a nil receiver, got %+v", merged)
+	}
+}
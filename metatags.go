@@ -0,0 +1,102 @@
+package urlmeta
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ToMetaTags renders m as Open Graph and Twitter Card <meta> tags, one per
+// line, for a page that wants to share the same preview. Titles and
+// descriptions come from BestTitle and BestDescription, the image from
+// BestImage, and og:url from the canonical URL when known. Empty fields
+// are left out; og:type defaults to "website" and twitter:card to
+// "summary_large_image" when there is an image, "summary" otherwise.
+func (m *Metadata) ToMetaTags() string {
+	var b strings.Builder
+	property := func(name, content string) {
+		writeMetaTag(&b, "property", name, content)
+	}
+	name := func(name, content string) {
+		writeMetaTag(&b, "name", name, content)
+	}
+
+	title := m.BestTitle()
+	description := m.BestDescription()
+	image := m.BestImage(ImageCriteria{})
+
+	pageURL := m.CanonicalURL
+	if pageURL == "" {
+		pageURL = m.URL
+	}
+	ogType := m.Type
+	if ogType == "" {
+		ogType = "website"
+	}
+
+	property("og:title", title)
+	property("og:description", description)
+	property("og:type", ogType)
+	property("og:url", pageURL)
+	property("og:site_name", m.SiteName)
+	property("og:locale", m.Locale)
+	if image != nil {
+		property("og:image", image.URL)
+		property("og:image:type", image.Type)
+		property("og:image:width", formatDimension(image.Width))
+		property("og:image:height", formatDimension(image.Height))
+		property("og:image:alt", image.Alt)
+	}
+	for _, video := range m.Videos {
+		property("og:video", video.URL)
+		property("og:video:type", video.Type)
+		property("og:video:width", formatDimension(video.Width))
+		property("og:video:height", formatDimension(video.Height))
+	}
+	property("article:published_time", m.PublishedTime)
+	property("article:modified_time", m.ModifiedTime)
+	property("fb:app_id", m.FacebookAppID)
+
+	card := m.TwitterCard
+	if card == "" {
+		card = "summary"
+		if image != nil {
+			card = "summary_large_image"
+		}
+	}
+	name("twitter:card", card)
+	name("twitter:site", m.TwitterSite)
+	name("twitter:creator", m.TwitterCreator)
+	name("twitter:title", title)
+	name("twitter:description", description)
+	if image != nil {
+		name("twitter:image", image.URL)
+		name("twitter:image:alt", image.Alt)
+	}
+
+	return b.String()
+}
+
+// writeMetaTag writes a <meta> tag naming itself with attr (property or
+// name), skipping empty content
+func writeMetaTag(b *strings.Builder, attr, name, content string) {
+	if content == "" {
+		return
+	}
+	b.WriteString(`<meta `)
+	b.WriteString(attr)
+	b.WriteString(`="`)
+	b.WriteString(html.EscapeString(name))
+	b.WriteString(`" content="`)
+	b.WriteString(html.EscapeString(content))
+	b.WriteString("\">\n")
+}
+
+// formatDimension formats a width or height, empty when unknown
+func formatDimension(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
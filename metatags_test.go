@@ -0,0 +1,64 @@
+package urlmeta
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToMetaTags(t *testing.T) {
+	m := &Metadata{
+		Title:        "Release notes | Example",
+		OGTitle:      "Release notes",
+		Description:  `Fixes "quoted" bugs & more`,
+		URL:          "https://example.com/notes?utm_source=x",
+		CanonicalURL: "https://example.com/notes",
+		SiteName:     "Example",
+		Images: []Image{
+			{URL: "https://example.com/icon.png", Width: 16, Height: 16},
+			{URL: "https://example.com/og.jpg", Width: 1200, Height: 630, Alt: "Cover", Source: ImageSourceOpenGraph},
+		},
+		TwitterSite:   "@example",
+		PublishedTime: "2024-01-15T10:00:00Z",
+	}
+
+	got := m.ToMetaTags()
+	for _, want := range []string{
+		`<meta property="og:title" content="Release notes">`,
+		`<meta property="og:description" content="Fixes &#34;quoted&#34; bugs &amp; more">`,
+		`<meta property="og:type" content="website">`,
+		`<meta property="og:url" content="https://example.com/notes">`,
+		`<meta property="og:site_name" content="Example">`,
+		`<meta property="og:image" content="https://example.com/og.jpg">`,
+		`<meta property="og:image:width" content="1200">`,
+		`<meta property="og:image:alt" content="Cover">`,
+		`<meta property="article:published_time" content="2024-01-15T10:00:00Z">`,
+		`<meta name="twitter:card" content="summary_large_image">`,
+		`<meta name="twitter:site" content="@example">`,
+		`<meta name="twitter:title" content="Release notes">`,
+		`<meta name="twitter:image" content="https://example.com/og.jpg">`,
+	} {
+		if !strings.Contains(got, want+"\n") {
+			t.Errorf("Expected %s in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "icon.png") {
+		t.Error("Expected only the best image")
+	}
+	if strings.Contains(got, "og:locale") || strings.Contains(got, "twitter:creator") {
+		t.Errorf("Expected empty fields to be left out, got:\n%s", got)
+	}
+}
+
+func TestToMetaTagsMinimal(t *testing.T) {
+	m := &Metadata{Title: "Hello", URL: "https://example.com/"}
+
+	want := `<meta property="og:title" content="Hello">
+<meta property="og:type" content="website">
+<meta property="og:url" content="https://example.com/">
+<meta name="twitter:card" content="summary">
+<meta name="twitter:title" content="Hello">
+`
+	if got := m.ToMetaTags(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,90 @@
+package urlmeta
+
+import (
+	"io"
+	"net/http"
+	"time"
+)
+
+// Extraction outcomes reported to Metrics
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// Metrics receives measurements from a Client, e.g. to export them to
+// Prometheus (see the prommetrics package). Implementations must be safe
+// for concurrent use.
+type Metrics interface {
+	// ObserveExtraction is called once per extraction that ran a strategy,
+	// i.e. not for cache hits or URLs rejected before fetching. strategy is
+	// the strategy actually used (never StrategyAuto) and outcome is
+	// OutcomeSuccess or OutcomeError.
+	ObserveExtraction(strategy ExtractionStrategy, outcome string, duration time.Duration)
+
+	// ObserveFetch is called for every HTTP request sent, with the time
+	// until the response headers arrived. status is 0 when no response was
+	// received.
+	ObserveFetch(status int, duration time.Duration)
+
+	// AddBytesDownloaded is called as response bodies are read, with the
+	// number of (decompressed) bytes read
+	AddBytesDownloaded(n int64)
+
+	// ObserveCacheLookup is called for every cache lookup
+	ObserveCacheLookup(hit bool)
+}
+
+// WithMetrics reports extraction outcomes, fetch latency, bytes downloaded
+// and cache lookups to m. Metrics are disabled by default.
+func WithMetrics(m Metrics) Option {
+	return func(c *Client) {
+		c.metrics = m
+	}
+}
+
+// observeExtraction reports an extraction that started at start
+func (c *Client) observeExtraction(strategy ExtractionStrategy, start time.Time, err error) {
+	if c.metrics == nil {
+		return
+	}
+	outcome := OutcomeSuccess
+	if err != nil {
+		outcome = OutcomeError
+	}
+	c.metrics.ObserveExtraction(strategy, outcome, time.Since(start))
+}
+
+// observeFetch reports a request sent at start
+func (c *Client) observeFetch(resp *http.Response, start time.Time) {
+	if c.metrics == nil {
+		return
+	}
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	c.metrics.ObserveFetch(status, time.Since(start))
+}
+
+// observeCacheLookup reports a cache lookup
+func (c *Client) observeCacheLookup(hit bool) {
+	if c.metrics != nil {
+		c.metrics.ObserveCacheLookup(hit)
+	}
+}
+
+// countingBody reports the bytes read from a response body
+type countingBody struct {
+	io.ReadCloser
+	metrics Metrics
+}
+
+// Read implements io.Reader
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.metrics.AddBytesDownloaded(int64(n))
+	}
+	return n, err
+}
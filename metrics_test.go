@@ -0,0 +1,99 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testMetrics records everything reported to it
+type testMetrics struct {
+	mu          sync.Mutex
+	extractions []string
+	statuses    []int
+	bytes       int64
+	hits        int
+	misses      int
+}
+
+func (m *testMetrics) ObserveExtraction(strategy ExtractionStrategy, outcome string, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.extractions = append(m.extractions, strategy.String()+"/"+outcome)
+}
+
+func (m *testMetrics) ObserveFetch(status int, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.statuses = append(m.statuses, status)
+}
+
+func (m *testMetrics) AddBytesDownloaded(n int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bytes += n
+}
+
+func (m *testMetrics) ObserveCacheLookup(hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if hit {
+		m.hits++
+	} else {
+		m.misses++
+	}
+}
+
+func TestWithMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	m := &testMetrics{}
+	client := NewClient(WithMetrics(m), WithCache(10, time.Minute), WithAutoOEmbed(false))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Extract(server.URL); err != nil {
+			t.Fatalf("Extract failed: %v", err)
+		}
+	}
+	if _, err := client.Extract(server.URL + "/missing"); err == nil {
+		t.Fatal("Expected an error for a missing page")
+	}
+
+	if len(m.extractions) != 2 || m.extractions[0] != "html_only/success" || m.extractions[1] != "html_only/error" {
+		t.Errorf("Expected one successful and one failed extraction, got %v", m.extractions)
+	}
+	if len(m.statuses) != 2 || m.statuses[0] != http.StatusOK || m.statuses[1] != http.StatusNotFound {
+		t.Errorf("Expected fetches with status 200 and 404, got %v", m.statuses)
+	}
+	if m.bytes < int64(len(mockHTMLBasic)) {
+		t.Errorf("Expected at least %d bytes downloaded, got %d", len(mockHTMLBasic), m.bytes)
+	}
+	if m.hits != 1 || m.misses != 2 {
+		t.Errorf("Expected 1 cache hit and 2 misses, got %d/%d", m.hits, m.misses)
+	}
+}
+
+func TestWithMetricsFailedFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	m := &testMetrics{}
+	client := NewClient(WithMetrics(m), WithAutoOEmbed(false))
+	if _, err := client.Extract(server.URL); err == nil {
+		t.Fatal("Expected an error for a closed server")
+	}
+
+	if len(m.statuses) != 1 || m.statuses[0] != 0 {
+		t.Errorf("Expected one fetch with status 0, got %v", m.statuses)
+	}
+}
@@ -0,0 +1,192 @@
+package urlmeta
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// MicrodataItem is a schema.org (or other vocabulary) microdata item, as
+// declared with itemscope/itemtype/itemprop attributes.
+//
+// Property values are either a string or a nested *MicrodataItem, mirroring
+// the WHATWG microdata JSON format.
+type MicrodataItem struct {
+	Type       []string                 `json:"type,omitempty"`
+	ID         string                   `json:"id,omitempty"`
+	Properties map[string][]interface{} `json:"properties"`
+}
+
+// HasType reports whether the item declares itemType (e.g.
+// "https://schema.org/Article"). The scheme and trailing slash are ignored.
+func (item *MicrodataItem) HasType(itemType string) bool {
+	want := trimSchemaType(itemType)
+	for _, t := range item.Type {
+		if trimSchemaType(t) == want {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the first string value of property name, or ""
+func (item *MicrodataItem) String(name string) string {
+	for _, v := range item.Properties[name] {
+		if s, ok := v.(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// Item returns the first nested item value of property name, or nil
+func (item *MicrodataItem) Item(name string) *MicrodataItem {
+	for _, v := range item.Properties[name] {
+		if nested, ok := v.(*MicrodataItem); ok {
+			return nested
+		}
+	}
+	return nil
+}
+
+// MicrodataByType returns top-level microdata items declaring itemType
+func (m *Metadata) MicrodataByType(itemType string) []*MicrodataItem {
+	var items []*MicrodataItem
+	for i := range m.Microdata {
+		if m.Microdata[i].HasType(itemType) {
+			items = append(items, &m.Microdata[i])
+		}
+	}
+	return items
+}
+
+// extractMicrodata returns all top-level items in the document, i.e.
+// elements with itemscope that are not themselves a property of another item
+func extractMicrodata(n *html.Node, baseURL *url.URL) []MicrodataItem {
+	var items []MicrodataItem
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && hasAttr(n, "itemscope") && !hasAttr(n, "itemprop") {
+			items = append(items, *parseMicrodataItem(n, baseURL))
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+
+	return items
+}
+
+// parseMicrodataItem builds the item rooted at an itemscope element
+func parseMicrodataItem(n *html.Node, baseURL *url.URL) *MicrodataItem {
+	item := &MicrodataItem{
+		Type:       strings.Fields(getAttr(n, "itemtype")),
+		ID:         getAttr(n, "itemid"),
+		Properties: make(map[string][]interface{}),
+	}
+
+	var collect func(*html.Node)
+	collect = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+
+			names := strings.Fields(getAttr(c, "itemprop"))
+			scoped := hasAttr(c, "itemscope")
+
+			if len(names) > 0 {
+				var value interface{}
+				if scoped {
+					value = parseMicrodataItem(c, baseURL)
+				} else {
+					value = microdataValue(c, baseURL)
+				}
+				for _, name := range names {
+					item.Properties[name] = append(item.Properties[name], value)
+				}
+			}
+
+			// Descendants of a nested scope belong to that item, not this one
+			if !scoped {
+				collect(c)
+			}
+		}
+	}
+	collect(n)
+
+	return item
+}
+
+// microdataValue returns the property value of a non-itemscope element
+// following the WHATWG microdata rules
+func microdataValue(n *html.Node, baseURL *url.URL) string {
+	switch n.Data {
+	case "meta":
+		return strings.TrimSpace(getAttr(n, "content"))
+	case "audio", "embed", "iframe", "img", "source", "track", "video":
+		return resolveMicrodataURL(getAttr(n, "src"), baseURL)
+	case "a", "area", "link":
+		return resolveMicrodataURL(getAttr(n, "href"), baseURL)
+	case "object":
+		return resolveMicrodataURL(getAttr(n, "data"), baseURL)
+	case "data", "meter":
+		return strings.TrimSpace(getAttr(n, "value"))
+	case "time":
+		if hasAttr(n, "datetime") {
+			return strings.TrimSpace(getAttr(n, "datetime"))
+		}
+	}
+	return strings.Join(strings.Fields(textContent(n)), " ")
+}
+
+// resolveMicrodataURL resolves a URL-valued property, tolerating a nil base
+func resolveMicrodataURL(href string, baseURL *url.URL) string {
+	href = strings.TrimSpace(href)
+	if baseURL == nil {
+		return href
+	}
+	return resolveURL(href, baseURL)
+}
+
+// trimSchemaType normalizes an itemtype for comparison
+func trimSchemaType(t string) string {
+	t = strings.TrimPrefix(t, "https://")
+	t = strings.TrimPrefix(t, "http://")
+	return strings.TrimSuffix(t, "/")
+}
+
+// getAttr returns the value of attribute key on n, or ""
+func getAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// hasAttr reports whether n carries attribute key
+func hasAttr(n *html.Node, key string) bool {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// textContent concatenates all text beneath n
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sb.WriteString(textContent(c))
+	}
+	return sb.String()
+}
@@ -0,0 +1,131 @@
+package urlmeta
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+const mockHTMLMicrodata = `
+<!DOCTYPE html>
+<html>
+<head><title>Microdata Test</title></head>
+<body>
+	<article itemscope itemtype="https://schema.org/Article" itemid="urn:article:1">
+		<h1 itemprop="headline">  Microdata   Headline </h1>
+		<img itemprop="image" src="/img/cover.jpg">
+		<a itemprop="url" href="/articles/1">permalink</a>
+		<time itemprop="datePublished" datetime="2025-03-01T10:00:00Z">March 1</time>
+		<meta itemprop="wordCount" content="1200">
+		<div itemprop="author" itemscope itemtype="https://schema.org/Person">
+			<span itemprop="name">Jane Doe</span>
+			<a itemprop="sameAs" href="https://twitter.com/jane">Twitter</a>
+		</div>
+		<span itemprop="keywords genre">Go</span>
+	</article>
+	<div itemscope itemtype="http://schema.org/Product">
+		<span itemprop="name">Widget</span>
+		<data itemprop="sku" value="W-1">Widget One</data>
+	</div>
+</body>
+</html>
+`
+
+func parseMicrodataFixture(t *testing.T) []MicrodataItem {
+	t.Helper()
+
+	doc, err := html.Parse(strings.NewReader(mockHTMLMicrodata))
+	if err != nil {
+		t.Fatalf("Failed to parse fixture: %v", err)
+	}
+
+	base, _ := url.Parse("https://example.com/blog/")
+	return extractMicrodata(doc, base)
+}
+
+func TestExtractMicrodata(t *testing.T) {
+	items := parseMicrodataFixture(t)
+
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 top-level items, got %d", len(items))
+	}
+
+	article := items[0]
+	if !article.HasType("schema.org/Article") {
+		t.Errorf("Expected Article type, got %v", article.Type)
+	}
+
+	if article.ID != "urn:article:1" {
+		t.Errorf("Expected itemid 'urn:article:1', got '%s'", article.ID)
+	}
+
+	tests := []struct {
+		prop     string
+		expected string
+	}{
+		{"headline", "Microdata Headline"},
+		{"image", "https://example.com/img/cover.jpg"},
+		{"url", "https://example.com/articles/1"},
+		{"datePublished", "2025-03-01T10:00:00Z"},
+		{"wordCount", "1200"},
+		{"keywords", "Go"},
+		{"genre", "Go"},
+	}
+
+	for _, tt := range tests {
+		if got := article.String(tt.prop); got != tt.expected {
+			t.Errorf("Property %s: expected '%s', got '%s'", tt.prop, tt.expected, got)
+		}
+	}
+
+	author := article.Item("author")
+	if author == nil {
+		t.Fatal("Expected nested author item")
+	}
+
+	if !author.HasType("https://schema.org/Person") || author.String("name") != "Jane Doe" {
+		t.Errorf("Unexpected author item: %+v", author)
+	}
+
+	// Nested item properties must not leak into the parent
+	if _, ok := article.Properties["name"]; ok {
+		t.Error("Nested 'name' property leaked into Article")
+	}
+
+	product := items[1]
+	if product.String("sku") != "W-1" {
+		t.Errorf("Expected sku 'W-1' from data value, got '%s'", product.String("sku"))
+	}
+}
+
+func TestMicrodataInExtract(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLMicrodata))
+	}))
+	defer server.Close()
+
+	metadata, err := Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	products := metadata.MicrodataByType("https://schema.org/Product")
+	if len(products) != 1 || products[0].String("name") != "Widget" {
+		t.Errorf("Expected one Product named 'Widget', got %+v", products)
+	}
+
+	data, err := json.Marshal(metadata.Microdata[0])
+	if err != nil {
+		t.Fatalf("Failed to marshal microdata: %v", err)
+	}
+
+	if !strings.Contains(string(data), `"author":[{"type":["https://schema.org/Person"]`) {
+		t.Errorf("Expected nested item in JSON, got %s", data)
+	}
+}
@@ -0,0 +1,136 @@
+package urlmeta
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// trackingParams are the query parameters NormalizeURL removes, besides
+// any starting with "utm_"
+var trackingParams = map[string]bool{
+	"fbclid":  true,
+	"gclid":   true,
+	"dclid":   true,
+	"gbraid":  true,
+	"wbraid":  true,
+	"msclkid": true,
+	"yclid":   true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"igshid":  true,
+}
+
+// defaultPorts maps schemes to the port implied when none is given
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// normalizeConfig holds the settings of a NormalizeURL call
+type normalizeConfig struct {
+	keepFragment bool
+	stripParams  map[string]bool
+}
+
+// NormalizeOption is a function that configures a NormalizeURL call
+type NormalizeOption func(*normalizeConfig)
+
+// WithKeepFragment keeps the #fragment, for sites that route on it
+func WithKeepFragment() NormalizeOption {
+	return func(n *normalizeConfig) {
+		n.keepFragment = true
+	}
+}
+
+// WithStripParams removes the given query parameters too (matched
+// case-insensitively), e.g. a site's own "ref" or "source"
+func WithStripParams(names ...string) NormalizeOption {
+	return func(n *normalizeConfig) {
+		if n.stripParams == nil {
+			n.stripParams = make(map[string]bool)
+		}
+		for _, name := range names {
+			n.stripParams[strings.ToLower(name)] = true
+		}
+	}
+}
+
+// NormalizeURL returns a canonical form of rawURL, so that links differing
+// only in presentation compare equal: the scheme and host are lowercased,
+// the default port and the fragment dropped, tracking parameters (utm_*,
+// fbclid, gclid, msclkid, ...) removed, the remaining query parameters
+// sorted by name, and an empty path replaced by "/". The path itself is
+// left alone, as servers may treat case and trailing slashes differently.
+func NormalizeURL(rawURL string, opts ...NormalizeOption) (string, error) {
+	cfg := &normalizeConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidURL, err)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("%w: %s has no host", ErrInvalidURL, rawURL)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if port := u.Port(); port != "" && port != defaultPorts[u.Scheme] {
+		u.Host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		u.Host = "[" + host + "]"
+	} else {
+		u.Host = host
+	}
+
+	if u.Path == "" && u.Opaque == "" {
+		u.Path = "/"
+	}
+	if !cfg.keepFragment {
+		u.Fragment = ""
+		u.RawFragment = ""
+	}
+
+	if u.RawQuery != "" {
+		query, err := url.ParseQuery(u.RawQuery)
+		if err == nil {
+			for name := range query {
+				lower := strings.ToLower(name)
+				if strings.HasPrefix(lower, "utm_") || trackingParams[lower] || cfg.stripParams[lower] {
+					delete(query, name)
+				}
+			}
+			u.RawQuery = query.Encode()
+		}
+	}
+	u.ForceQuery = false
+
+	return u.String(), nil
+}
+
+// WithURLNormalization keys the cache and batch deduplication (see
+// WithDeduplication) on NormalizeURL forms, so the same page reached
+// through links with different tracking parameters or fragments is cached
+// and fetched once. The URL requested is left unchanged. Disabled by
+// default.
+func WithURLNormalization(normalize bool) Option {
+	return func(c *Client) {
+		c.normalizeURLs = normalize
+	}
+}
+
+// urlKey returns the key identifying targetURL in the cache and batch
+// deduplication
+func (c *Client) urlKey(targetURL string) string {
+	if !c.normalizeURLs {
+		return targetURL
+	}
+	if normalized, err := NormalizeURL(targetURL); err == nil {
+		return normalized
+	}
+	return targetURL
+}
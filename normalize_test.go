@@ -0,0 +1,81 @@
+package urlmeta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNormalizeURLForms(t *testing.T) {
+	tests := []struct {
+		in   string
+		opts []NormalizeOption
+		want string
+	}{
+		{"HTTPS://Example.COM", nil, "https://example.com/"},
+		{"https://example.com:443/a", nil, "https://example.com/a"},
+		{"http://example.com:80/a", nil, "http://example.com/a"},
+		{"http://example.com:8080/a", nil, "http://example.com:8080/a"},
+		{"https://example.com./Path/", nil, "https://example.com/Path/"},
+		{"https://example.com/a#section", nil, "https://example.com/a"},
+		{"https://example.com/a#section", []NormalizeOption{WithKeepFragment()}, "https://example.com/a#section"},
+		{"https://example.com/a?utm_source=x&b=2&UTM_Medium=y&a=1&fbclid=z&gclid=q", nil, "https://example.com/a?a=1&b=2"},
+		{"https://example.com/a?utm_source=x&fbclid=z", nil, "https://example.com/a"},
+		{"https://example.com/a?ref=feed&id=7", []NormalizeOption{WithStripParams("REF")}, "https://example.com/a?id=7"},
+		{"https://[2001:DB8::1]:443/", nil, "https://[2001:db8::1]/"},
+		{"https://user@example.com/", nil, "https://user@example.com/"},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeURL(tt.in, tt.opts...)
+		if err != nil {
+			t.Errorf("NormalizeURL(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeURL(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"/relative/path", "https://exa mple.com/"} {
+		if _, err := NormalizeURL(in); !errors.Is(err, ErrInvalidURL) {
+			t.Errorf("NormalizeURL(%q): expected ErrInvalidURL, got %v", in, err)
+		}
+	}
+}
+
+func TestWithURLNormalization(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<html><head><title>Page</title></head></html>")
+	}))
+	defer server.Close()
+
+	client := NewClient(WithCache(10, time.Minute), WithURLNormalization(true))
+	for _, u := range []string{server.URL + "/a?utm_source=x", server.URL + "/a#top", server.URL + "/a"} {
+		if _, err := client.Extract(u); err != nil {
+			t.Fatalf("Extract(%s) failed: %v", u, err)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("Expected one request through the cache, got %d", got)
+	}
+
+	requests.Store(0)
+	client = NewClient(WithURLNormalization(true))
+	urls := []string{server.URL + "/b?fbclid=1", server.URL + "/b?fbclid=2"}
+	results, err := client.ExtractAll(context.Background(), urls, WithDeduplication(true))
+	if err != nil {
+		t.Fatalf("ExtractAll failed: %v", err)
+	}
+	if results[1].DuplicateOf != urls[0] || requests.Load() != 1 {
+		t.Errorf("Expected the second URL deduplicated without a fetch, got %+v (%d requests)", results[1], requests.Load())
+	}
+}
@@ -1,39 +1,46 @@
 package urlmeta
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
 
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/net/html"
 )
 
 // OEmbed represents oEmbed response data
 // Specification: https://oembed.com/
 type OEmbed struct {
-	Type            string `json:"type"`                       // photo, video, link, rich
-	Version         string `json:"version"`                    // oEmbed version (usually "1.0")
-	Title           string `json:"title,omitempty"`            // Resource title
-	AuthorName      string `json:"author_name,omitempty"`      // Author/owner name
-	AuthorURL       string `json:"author_url,omitempty"`       // Author/owner URL
-	ProviderName    string `json:"provider_name,omitempty"`    // Provider name
-	ProviderURL     string `json:"provider_url,omitempty"`     // Provider URL
-	CacheAge        int    `json:"cache_age,omitempty"`        // Suggested cache lifetime in seconds
-	ThumbnailURL    string `json:"thumbnail_url,omitempty"`    // Thumbnail URL
-	ThumbnailWidth  int    `json:"thumbnail_width,omitempty"`  // Thumbnail width
-	ThumbnailHeight int    `json:"thumbnail_height,omitempty"` // Thumbnail height
+	Type            string `json:"type" xml:"type"`                                   // photo, video, link, rich
+	Version         string `json:"version" xml:"version"`                             // oEmbed version (usually "1.0")
+	Title           string `json:"title,omitempty" xml:"title"`                       // Resource title
+	AuthorName      string `json:"author_name,omitempty" xml:"author_name"`           // Author/owner name
+	AuthorURL       string `json:"author_url,omitempty" xml:"author_url"`             // Author/owner URL
+	ProviderName    string `json:"provider_name,omitempty" xml:"provider_name"`       // Provider name
+	ProviderURL     string `json:"provider_url,omitempty" xml:"provider_url"`         // Provider URL
+	CacheAge        int    `json:"cache_age,omitempty" xml:"cache_age"`               // Suggested cache lifetime in seconds
+	ThumbnailURL    string `json:"thumbnail_url,omitempty" xml:"thumbnail_url"`       // Thumbnail URL
+	ThumbnailWidth  int    `json:"thumbnail_width,omitempty" xml:"thumbnail_width"`   // Thumbnail width
+	ThumbnailHeight int    `json:"thumbnail_height,omitempty" xml:"thumbnail_height"` // Thumbnail height
 
 	// Photo type specific
-	URL    string `json:"url,omitempty"`    // Photo URL
-	Width  int    `json:"width,omitempty"`  // Photo width
-	Height int    `json:"height,omitempty"` // Photo height
+	URL    string `json:"url,omitempty" xml:"url"`       // Photo URL
+	Width  int    `json:"width,omitempty" xml:"width"`   // Photo width
+	Height int    `json:"height,omitempty" xml:"height"` // Photo height
 
 	// Video/Rich type specific
-	HTML string `json:"html,omitempty"` // HTML embed code
+	HTML string `json:"html,omitempty" xml:"html"` // HTML embed code
 }
 
 // OEmbedProvider represents an oEmbed provider configuration
@@ -54,27 +61,104 @@ type OEmbedEndpoint struct {
 
 // ExtractOEmbed attempts to extract oEmbed data from a URL
 func (c *Client) ExtractOEmbed(targetURL string) (*OEmbed, error) {
+	return c.ExtractOEmbedContext(context.Background(), targetURL)
+}
+
+// ExtractOEmbedContext is like ExtractOEmbed but honors ctx cancellation
+func (c *Client) ExtractOEmbedContext(ctx context.Context, targetURL string) (*OEmbed, error) {
+	return c.extractOEmbed(ctx, targetURL, true)
+}
+
+// extractOEmbed resolves and fetches the oEmbed of targetURL. Without
+// discover it skips discovery, which fetches the page itself, for callers
+// that fetch the page anyway (see extractParallel).
+func (c *Client) extractOEmbed(ctx context.Context, targetURL string, discover bool) (*OEmbed, error) {
 	// Normalize URL
 	targetURL = normalizeURL(targetURL)
 
+	// The last endpoint failure is returned if nothing works, so callers
+	// can tell e.g. a private resource from a missing endpoint
+	var fetchErr error
+
+	// Some embeds are built from the URL alone
+	if oembed := c.embedFromURL(targetURL); oembed != nil {
+		c.logDebug(ctx, "urlmeta: oEmbed built from URL", "url", targetURL, "provider", oembed.ProviderName)
+		return oembed, nil
+	}
+
 	// 1. Try to find oEmbed endpoint from known providers
-	endpoint := findOEmbedEndpoint(targetURL)
+	endpoint := c.findOEmbedEndpoint(targetURL)
 	if endpoint != "" {
-		oembed, err := c.fetchOEmbed(endpoint, targetURL)
+		c.logDebug(ctx, "urlmeta: oEmbed endpoint resolved", "url", targetURL, "endpoint", endpoint, "source", "provider")
+		oembed, err := c.fetchOEmbed(ctx, endpoint, targetURL)
 		if err == nil {
 			return oembed, nil
 		}
+		c.logDebug(ctx, "urlmeta: oEmbed fetch failed", "url", targetURL, "endpoint", endpoint, "error", err)
+		fetchErr = err
+	}
+
+	// Fediverse instances serve their own endpoint
+	if endpoint == "" && c.fediverse != nil {
+		if u, err := url.Parse(targetURL); err == nil {
+			endpoint = c.fediverseEndpoint(ctx, u)
+		}
+		if endpoint != "" {
+			c.logDebug(ctx, "urlmeta: oEmbed endpoint resolved", "url", targetURL, "endpoint", endpoint, "source", "fediverse")
+			oembed, err := c.fetchOEmbed(ctx, endpoint, targetURL)
+			if err == nil {
+				return oembed, nil
+			}
+			c.logDebug(ctx, "urlmeta: oEmbed fetch failed", "url", targetURL, "endpoint", endpoint, "error", err)
+			fetchErr = err
+		}
 	}
 
-	// 2. Try oEmbed discovery from HTML
-	discoveredEndpoint, err := c.discoverOEmbedEndpoint(targetURL)
+	// 2. Try an endpoint discovered earlier on the same host
+	var cachedEndpoint string
+	if endpoint == "" && c.endpointCache != nil {
+		if cached, ok := c.endpointCache.get(targetURL); ok {
+			c.logDebug(ctx, "urlmeta: oEmbed endpoint resolved", "url", targetURL, "endpoint", cached, "source", "cache")
+			oembed, err := c.fetchOEmbed(ctx, cached, targetURL)
+			if err == nil {
+				return oembed, nil
+			}
+			c.logDebug(ctx, "urlmeta: oEmbed fetch failed", "url", targetURL, "endpoint", cached, "error", err)
+			cachedEndpoint, fetchErr = cached, err
+		}
+	}
+
+	if !discover {
+		if fetchErr != nil {
+			return nil, fmt.Errorf("oEmbed failed for URL %s: %w", targetURL, fetchErr)
+		}
+		return nil, fmt.Errorf("oEmbed endpoint not found for URL: %s", targetURL)
+	}
+
+	// 3. Try oEmbed discovery from HTML
+	discoveredEndpoint, err := c.discoverOEmbedEndpoint(ctx, targetURL)
+	if err == nil && discoveredEndpoint != "" && discoveredEndpoint == cachedEndpoint {
+		// Already tried above
+		return nil, fmt.Errorf("oEmbed failed for URL %s: %w", targetURL, fetchErr)
+	}
 	if err == nil && discoveredEndpoint != "" {
-		oembed, err := c.fetchOEmbed(discoveredEndpoint, targetURL)
+		c.logDebug(ctx, "urlmeta: oEmbed endpoint resolved", "url", targetURL, "endpoint", discoveredEndpoint, "source", "discovery")
+		if c.endpointCache != nil {
+			c.endpointCache.set(targetURL, discoveredEndpoint)
+		}
+		oembed, err := c.fetchOEmbed(ctx, discoveredEndpoint, targetURL)
 		if err == nil {
 			return oembed, nil
 		}
+		c.logDebug(ctx, "urlmeta: oEmbed fetch failed", "url", targetURL, "endpoint", discoveredEndpoint, "error", err)
+		fetchErr = err
+	} else {
+		c.logDebug(ctx, "urlmeta: oEmbed discovery found no endpoint", "url", targetURL, "error", err)
 	}
 
+	if fetchErr != nil {
+		return nil, fmt.Errorf("oEmbed failed for URL %s: %w", targetURL, fetchErr)
+	}
 	return nil, fmt.Errorf("oEmbed endpoint not found for URL: %s", targetURL)
 }
 
@@ -84,9 +168,37 @@ func ExtractOEmbed(targetURL string) (*OEmbed, error) {
 	return client.ExtractOEmbed(targetURL)
 }
 
+// embedFromURL returns the oEmbed for URLs whose embed needs no request:
+// Twitch players (with WithTwitchParent) and direct Imgur images
+func (c *Client) embedFromURL(targetURL string) *OEmbed {
+	u, err := url.Parse(targetURL)
+	if err != nil {
+		return nil
+	}
+	if oembed := c.twitchEmbed(u); oembed != nil {
+		return oembed
+	}
+	return imgurImageEmbed(u)
+}
+
+// findOEmbedEndpoint finds the oEmbed endpoint from known providers, then
+// from the providers downloaded by WithProviderAutoRefresh
+func (c *Client) findOEmbedEndpoint(targetURL string) string {
+	if endpoint := findOEmbedEndpoint(targetURL); endpoint != "" {
+		return endpoint
+	}
+	return matchEndpoint(c.refreshedProviders(), targetURL)
+}
+
 // findOEmbedEndpoint finds oEmbed endpoint from known providers
 func findOEmbedEndpoint(targetURL string) string {
-	for _, provider := range knownProviders {
+	return matchEndpoint(providerSnapshot(), targetURL)
+}
+
+// matchEndpoint returns the endpoint of the first provider with a scheme
+// matching targetURL
+func matchEndpoint(providers []OEmbedProvider, targetURL string) string {
+	for _, provider := range providers {
 		for _, endpoint := range provider.Endpoints {
 			for _, scheme := range endpoint.Schemes {
 				if matchScheme(targetURL, scheme) {
@@ -191,15 +303,18 @@ func clearRegexCache() {
 }
 
 // discoverOEmbedEndpoint discovers oEmbed endpoint from HTML
-func (c *Client) discoverOEmbedEndpoint(targetURL string) (string, error) {
-	req, err := http.NewRequest("GET", targetURL, nil)
+func (c *Client) discoverOEmbedEndpoint(ctx context.Context, targetURL string) (endpoint string, err error) {
+	ctx, span := c.startSpan(ctx, spanDiscoverOEmbed, targetURL)
+	defer func() { endSpan(span, err) }()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", targetURL, nil)
 	if err != nil {
 		return "", err
 	}
 
 	req.Header.Set("User-Agent", c.userAgent)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return "", err
 	}
@@ -209,34 +324,72 @@ func (c *Client) discoverOEmbedEndpoint(targetURL string) (string, error) {
 			_ = closeErr
 		}
 	}()
+	setSpanStatusCode(span, resp.StatusCode)
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("HTTP error: %d", resp.StatusCode)
+		return "", &ErrHTTPStatus{Code: resp.StatusCode}
 	}
 
-	doc, err := html.Parse(resp.Body)
+	body, err := c.limitBody(resp)
 	if err != nil {
 		return "", err
 	}
 
+	_, parseSpan := c.startSpan(ctx, spanParseHTML, targetURL)
+	doc, err := c.parseHTML(body)
+	endSpan(parseSpan, err)
+	if err != nil {
+		return "", err
+	}
+
+	baseURL, parseErr := url.Parse(targetURL)
+	if parseErr != nil {
+		return findOEmbedLink(doc), nil
+	}
+	return pageOEmbedLink(doc, baseURL), nil
+}
+
+// pageOEmbedLink returns the oEmbed link of doc resolved against baseURL,
+// or "" if it has none
+func pageOEmbedLink(doc *html.Node, baseURL *url.URL) string {
 	endpoint := findOEmbedLink(doc)
-	if endpoint != "" {
-		// Resolve relative URLs
-		baseURL, parseErr := url.Parse(targetURL)
-		if parseErr != nil {
-			return endpoint, nil
-		}
-		endpointURL, parseErr := url.Parse(endpoint)
-		if parseErr == nil && !endpointURL.IsAbs() {
-			endpoint = baseURL.ResolveReference(endpointURL).String()
-		}
+	if endpoint == "" {
+		return ""
+	}
+
+	// Resolve relative URLs
+	endpointURL, err := url.Parse(endpoint)
+	if err == nil && !endpointURL.IsAbs() {
+		endpoint = baseURL.ResolveReference(endpointURL).String()
 	}
+	return endpoint
+}
 
-	return endpoint, nil
+// discoveredOEmbed fetches the oEmbed of targetURL from endpoint, taken
+// from the page's oEmbed link, as discovery would
+func (c *Client) discoveredOEmbed(ctx context.Context, targetURL, endpoint string) (*OEmbed, error) {
+	c.logDebug(ctx, "urlmeta: oEmbed endpoint resolved", "url", targetURL, "endpoint", endpoint, "source", "discovery")
+	if c.endpointCache != nil {
+		c.endpointCache.set(targetURL, endpoint)
+	}
+	oembed, err := c.fetchOEmbed(ctx, endpoint, targetURL)
+	if err != nil {
+		return nil, fmt.Errorf("oEmbed failed for URL %s: %w", targetURL, err)
+	}
+	return oembed, nil
 }
 
-// findOEmbedLink searches for oEmbed link in HTML
+// findOEmbedLink searches for oEmbed link in HTML, preferring the JSON
+// format over XML
 func findOEmbedLink(n *html.Node) string {
+	if href := findOEmbedLinkType(n, "application/json+oembed", "text/json+oembed"); href != "" {
+		return href
+	}
+	return findOEmbedLinkType(n, "text/xml+oembed", "application/xml+oembed")
+}
+
+// findOEmbedLinkType returns the first oEmbed link with one of types
+func findOEmbedLinkType(n *html.Node, types ...string) string {
 	if n.Type == html.ElementNode && n.Data == "link" {
 		var rel, href, typeAttr string
 		for _, attr := range n.Attr {
@@ -251,13 +404,13 @@ func findOEmbedLink(n *html.Node) string {
 		}
 
 		// Look for oEmbed link
-		if rel == "alternate" && (typeAttr == "application/json+oembed" || typeAttr == "text/json+oembed") {
+		if rel == "alternate" && slices.Contains(types, typeAttr) {
 			return href
 		}
 	}
 
 	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		if result := findOEmbedLink(c); result != "" {
+		if result := findOEmbedLinkType(c, types...); result != "" {
 			return result
 		}
 	}
@@ -265,27 +418,63 @@ func findOEmbedLink(n *html.Node) string {
 	return ""
 }
 
-// fetchOEmbed fetches oEmbed data from endpoint
-func (c *Client) fetchOEmbed(endpoint, targetURL string) (*OEmbed, error) {
+// fetchOEmbed fetches oEmbed data from endpoint. JSON is requested unless
+// the endpoint asks for XML (as discovered text/xml+oembed links do); when
+// a JSON request is refused or answered with something other than JSON,
+// the XML format is tried instead.
+func (c *Client) fetchOEmbed(ctx context.Context, endpoint, targetURL string) (_ *OEmbed, err error) {
+	ctx, span := c.startSpan(ctx, spanFetchOEmbed, endpoint)
+	defer func() { endSpan(span, err) }()
+
 	// Build oEmbed request URL
 	oembedURL, err := url.Parse(endpoint)
 	if err != nil {
 		return nil, err
 	}
 
+	format := "json"
+	if strings.EqualFold(oembedURL.Query().Get("format"), "xml") {
+		format = "xml"
+	}
+
+	oembed, err := c.requestOEmbed(ctx, oembedURL, targetURL, format)
+	if err != nil && format == "json" && retryOEmbedAsXML(err) {
+		c.logDebug(ctx, "urlmeta: retrying oEmbed as XML", "endpoint", endpoint, "error", err)
+		oembed, err = c.requestOEmbed(ctx, oembedURL, targetURL, "xml")
+	}
+	return oembed, err
+}
+
+// requestOEmbed makes one oEmbed request in the given format
+func (c *Client) requestOEmbed(ctx context.Context, endpoint *url.URL, targetURL, format string) (*OEmbed, error) {
+	oembedURL := *endpoint
 	query := oembedURL.Query()
 	query.Set("url", targetURL)
-	query.Set("format", "json")
+	query.Set("format", format)
+
+	// Debug output and errors show the URL without provider credentials
+	debugURL := oembedURL
+	debugURL.RawQuery = query.Encode()
+	secrets := c.applyProviderCredentials(endpoint, query)
 	oembedURL.RawQuery = query.Encode()
 
-	req, err := http.NewRequest("GET", oembedURL.String(), nil)
+	oembed, err := c.doOEmbedRequest(ctx, oembedURL.String(), debugURL.String())
+	return oembed, redactSecrets(err, secrets)
+}
+
+// doOEmbedRequest fetches and decodes an oEmbed response, recording it
+// under debugURL
+func (c *Client) doOEmbedRequest(ctx context.Context, oembedURL, debugURL string) (*OEmbed, error) {
+	recordOEmbedDebug(ctx, debugURL, nil)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", oembedURL, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("User-Agent", c.userAgent)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -295,19 +484,94 @@ func (c *Client) fetchOEmbed(endpoint, targetURL string) (*OEmbed, error) {
 			_ = closeErr
 		}
 	}()
+	setSpanStatusCode(trace.SpanFromContext(ctx), resp.StatusCode)
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("oEmbed endpoint returned HTTP %d", resp.StatusCode)
+		return nil, oembedStatusError(resp.StatusCode)
+	}
+
+	body, err := c.limitBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	recordOEmbedDebug(ctx, debugURL, data)
+
+	return decodeOEmbed(data, resp.Header.Get("Content-Type"))
+}
+
+// oembedStatusError maps an oEmbed endpoint's error status to the errors
+// the spec gives it a meaning for. The result also wraps an *ErrHTTPStatus.
+func oembedStatusError(code int) error {
+	status := &ErrHTTPStatus{Code: code}
+	switch code {
+	case http.StatusUnauthorized:
+		return fmt.Errorf("%w: %w", ErrEmbedPrivate, status)
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %w", ErrEmbedNotFound, status)
+	case http.StatusNotImplemented:
+		return fmt.Errorf("%w: %w", ErrEmbedFormatUnsupported, status)
+	}
+	return fmt.Errorf("oEmbed endpoint returned %w", status)
+}
+
+// errOEmbedFormat marks an oEmbed response in neither JSON nor XML
+var errOEmbedFormat = errors.New("unexpected oEmbed response format")
+
+// decodeOEmbed decodes a JSON or XML oEmbed response, going by the
+// content type and, for providers that mislabel it, the body itself
+func decodeOEmbed(data []byte, contentType string) (*OEmbed, error) {
+	trimmed := bytes.TrimSpace(data)
+	if strings.Contains(contentType, "xml") || bytes.HasPrefix(trimmed, []byte("<")) {
+		var doc struct {
+			XMLName xml.Name
+			OEmbed
+		}
+		if err := xml.Unmarshal(trimmed, &doc); err != nil {
+			return nil, fmt.Errorf("failed to decode oEmbed response: %w: %w", errOEmbedFormat, err)
+		}
+		if doc.XMLName.Local != "oembed" {
+			return nil, fmt.Errorf("failed to decode oEmbed response: %w: root element <%s>", errOEmbedFormat, doc.XMLName.Local)
+		}
+		return &doc.OEmbed, nil
 	}
 
 	var oembed OEmbed
-	if err := json.NewDecoder(resp.Body).Decode(&oembed); err != nil {
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&oembed); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			err = fmt.Errorf("%w: %w", errOEmbedFormat, err)
+		}
 		return nil, fmt.Errorf("failed to decode oEmbed response: %w", err)
 	}
-
 	return &oembed, nil
 }
 
+// retryOEmbedAsXML reports whether a failed JSON oEmbed request may work
+// in the XML format: the response wasn't JSON, or the endpoint refused the
+// request for a reason other than the resource itself or rate limiting
+// (401, 403, 404 and 429 apply to either format; server errors other than
+// 501 aren't worth repeating)
+func retryOEmbedAsXML(err error) bool {
+	if errors.Is(err, errOEmbedFormat) {
+		return true
+	}
+
+	var status *ErrHTTPStatus
+	if !errors.As(err, &status) {
+		return false
+	}
+	switch status.Code {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusTooManyRequests:
+		return false
+	}
+	return status.Code < 500 || status.Code == http.StatusNotImplemented
+}
+
 // IsOEmbedSupported checks if a URL is likely to support oEmbed
 func IsOEmbedSupported(targetURL string) bool {
 	return findOEmbedEndpoint(targetURL) != ""
@@ -1,7 +1,9 @@
 package urlmeta
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -120,6 +122,39 @@ func TestIsOEmbedSupported(t *testing.T) {
 		{"https://twitter.com/user/status/123456", true},
 		{"https://soundcloud.com/artist/track", true},
 		{"https://open.spotify.com/track/123", true},
+		{"https://bsky.app/profile/bsky.app/post/3kgbz6tz6ab2p", true},
+		{"https://bsky.app/profile/bsky.app", false},
+		{"https://clips.twitch.tv/AwkwardHelplessSalamanderSwiftRage", false},
+		{"https://www.twitch.tv/videos/123456789", false},
+		{"https://www.dailymotion.com/video/x8abc12", true},
+		{"https://dai.ly/x8abc12", true},
+		{"https://giphy.com/gifs/cat-funny-3o7TKSjRrfIPjeiVyM", true},
+		{"https://media.giphy.com/media/3o7TKSjRrfIPjeiVyM/giphy.gif", true},
+		{"https://tenor.com/view/cat-gif-12345", true},
+		{"https://tenor.com/de/view/cat-gif-12345", true},
+		{"https://imgur.com/gallery/abc123", true},
+		{"https://imgur.com/a/abc123", true},
+		{"https://codepen.io/team/codepen/pen/PNaGbb", true},
+		{"https://codepen.io/team/codepen", false},
+		{"https://jsfiddle.net/user/a1b2c3/", true},
+		{"https://codesandbox.io/s/new", true},
+		{"https://codesandbox.io/p/sandbox/react-new", true},
+		{"https://www.figma.com/file/LKQ4FJ4bTnCSjedbRpk931/Sample-File", true},
+		{"https://www.figma.com/design/LKQ4FJ4bTnCSjedbRpk931/Sample", true},
+		{"https://www.figma.com/pricing", false},
+		{"https://www.loom.com/share/0281766fa2d04bb788eaf19e65135184", true},
+		{"https://www.slideshare.net/slideshow/go-concurrency-patterns/12345", true},
+		{"https://fr.slideshare.net/user/deck-title", true},
+		{"https://speakerdeck.com/user/deck-title", true},
+		{"https://speakerdeck.com/user", false},
+		{"https://www.mixcloud.com/someradio/late-night-session/", true},
+		{"https://www.kickstarter.com/projects/someone/a-board-game", true},
+		{"https://www.kickstarter.com/discover", false},
+		{"https://form.typeform.com/to/aBcD1234", true},
+		{"https://acme.typeform.com/to/aBcD1234", true},
+		{"https://www.typeform.com/templates/", false},
+		{"https://www.canva.com/design/DAFabc123/view", true},
+		{"https://www.canva.com/templates/", false},
 		{"https://example.com/random", false},
 		{"https://github.com/user/repo", false},
 	}
@@ -149,6 +184,138 @@ func TestFindOEmbedEndpoint(t *testing.T) {
 			"https://twitter.com/user/status/123",
 			"https://publish.twitter.com/oembed",
 		},
+		{
+			"https://bsky.app/profile/bsky.app/post/3kgbz6tz6ab2p",
+			"https://embed.bsky.app/oembed",
+		},
+		{
+			"https://bsky.app/profile/bsky.app",
+			"",
+		},
+		{
+			"https://clips.twitch.tv/AwkwardHelplessSalamanderSwiftRage",
+			"",
+		},
+		{
+			"https://www.twitch.tv/videos/123456789",
+			"",
+		},
+		{
+			"https://www.dailymotion.com/video/x8abc12",
+			"https://www.dailymotion.com/services/oembed",
+		},
+		{
+			"https://dai.ly/x8abc12",
+			"https://www.dailymotion.com/services/oembed",
+		},
+		{
+			"https://giphy.com/gifs/cat-funny-3o7TKSjRrfIPjeiVyM",
+			"https://giphy.com/services/oembed",
+		},
+		{
+			"https://media.giphy.com/media/3o7TKSjRrfIPjeiVyM/giphy.gif",
+			"https://giphy.com/services/oembed",
+		},
+		{
+			"https://tenor.com/view/cat-gif-12345",
+			"https://tenor.com/oembed",
+		},
+		{
+			"https://tenor.com/de/view/cat-gif-12345",
+			"https://tenor.com/oembed",
+		},
+		{
+			"https://imgur.com/gallery/abc123",
+			"https://api.imgur.com/oembed",
+		},
+		{
+			"https://imgur.com/a/abc123",
+			"https://api.imgur.com/oembed",
+		},
+		{
+			"https://codepen.io/team/codepen/pen/PNaGbb",
+			"https://codepen.io/api/oembed",
+		},
+		{
+			"https://codepen.io/team/codepen",
+			"",
+		},
+		{
+			"https://jsfiddle.net/user/a1b2c3/",
+			"https://jsfiddle.net/services/oembed/",
+		},
+		{
+			"https://codesandbox.io/s/new",
+			"https://codesandbox.io/oembed",
+		},
+		{
+			"https://codesandbox.io/p/sandbox/react-new",
+			"https://codesandbox.io/oembed",
+		},
+		{
+			"https://www.figma.com/file/LKQ4FJ4bTnCSjedbRpk931/Sample-File",
+			"https://www.figma.com/api/oembed",
+		},
+		{
+			"https://www.figma.com/design/LKQ4FJ4bTnCSjedbRpk931/Sample",
+			"https://www.figma.com/api/oembed",
+		},
+		{
+			"https://www.figma.com/pricing",
+			"",
+		},
+		{
+			"https://www.loom.com/share/0281766fa2d04bb788eaf19e65135184",
+			"https://www.loom.com/v1/oembed",
+		},
+		{
+			"https://www.slideshare.net/slideshow/go-concurrency-patterns/12345",
+			"https://www.slideshare.net/api/oembed/2",
+		},
+		{
+			"https://fr.slideshare.net/user/deck-title",
+			"https://www.slideshare.net/api/oembed/2",
+		},
+		{
+			"https://speakerdeck.com/user/deck-title",
+			"https://speakerdeck.com/oembed.json",
+		},
+		{
+			"https://speakerdeck.com/user",
+			"",
+		},
+		{
+			"https://www.mixcloud.com/someradio/late-night-session/",
+			"https://www.mixcloud.com/oembed/",
+		},
+		{
+			"https://www.kickstarter.com/projects/someone/a-board-game",
+			"https://www.kickstarter.com/services/oembed",
+		},
+		{
+			"https://www.kickstarter.com/discover",
+			"",
+		},
+		{
+			"https://form.typeform.com/to/aBcD1234",
+			"https://form.typeform.com/oembed",
+		},
+		{
+			"https://acme.typeform.com/to/aBcD1234",
+			"https://form.typeform.com/oembed",
+		},
+		{
+			"https://www.typeform.com/templates/",
+			"",
+		},
+		{
+			"https://www.canva.com/design/DAFabc123/view",
+			"https://canva.com/_oembed",
+		},
+		{
+			"https://www.canva.com/templates/",
+			"",
+		},
 		{
 			"https://example.com/random",
 			"",
@@ -172,7 +339,7 @@ func TestDiscoverOEmbedEndpoint(t *testing.T) {
 	defer serverWithOEmbed.Close()
 
 	client := NewClient()
-	endpoint, err := client.discoverOEmbedEndpoint(serverWithOEmbed.URL)
+	endpoint, err := client.discoverOEmbedEndpoint(context.Background(), serverWithOEmbed.URL)
 	if err != nil {
 		t.Fatalf("discoverOEmbedEndpoint failed: %v", err)
 	}
@@ -188,7 +355,7 @@ func TestDiscoverOEmbedEndpoint(t *testing.T) {
 	}))
 	defer serverWithoutOEmbed.Close()
 
-	endpoint, err = client.discoverOEmbedEndpoint(serverWithoutOEmbed.URL)
+	endpoint, err = client.discoverOEmbedEndpoint(context.Background(), serverWithoutOEmbed.URL)
 	if err != nil {
 		t.Fatalf("discoverOEmbedEndpoint failed: %v", err)
 	}
@@ -553,3 +720,96 @@ func BenchmarkMatchSchemeMultiplePatterns(b *testing.B) {
 		matchScheme(tc.url, tc.scheme)
 	}
 }
+
+const mockOEmbedXML = `<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<oembed>
+	<version>1.0</version>
+	<type>video</type>
+	<title>XML Video</title>
+	<provider_name>XML Provider</provider_name>
+	<width>640</width>
+	<height>360</height>
+	<html>&lt;iframe src="https://xml.example.com/embed/1"&gt;&lt;/iframe&gt;</html>
+</oembed>`
+
+func TestFetchOEmbedXMLFallback(t *testing.T) {
+	tests := []struct {
+		name      string
+		jsonReply func(w http.ResponseWriter)
+	}{
+		{"not implemented", func(w http.ResponseWriter) { w.WriteHeader(http.StatusNotImplemented) }},
+		{"HTML error page", func(w http.ResponseWriter) { w.Write([]byte("<html>oops</html>")) }},
+		{"invalid JSON", func(w http.ResponseWriter) { w.Write([]byte("Bad format")) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var formats []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				format := r.URL.Query().Get("format")
+				formats = append(formats, format)
+				if format == "json" {
+					tt.jsonReply(w)
+					return
+				}
+				w.Header().Set("Content-Type", "text/xml")
+				w.Write([]byte(mockOEmbedXML))
+			}))
+			defer server.Close()
+
+			oembed, err := NewClient().fetchOEmbed(context.Background(), server.URL+"/oembed", "https://xml.example.com/v/1")
+			if err != nil {
+				t.Fatalf("fetchOEmbed failed: %v", err)
+			}
+			if oembed.Title != "XML Video" || oembed.Width != 640 || !strings.HasPrefix(oembed.HTML, "<iframe") {
+				t.Errorf("Expected the XML response decoded, got %+v", oembed)
+			}
+			if strings.Join(formats, ",") != "json,xml" {
+				t.Errorf("Expected JSON then XML requests, got %v", formats)
+			}
+		})
+	}
+}
+
+func TestFetchOEmbedNoXMLRetry(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := NewClient().fetchOEmbed(context.Background(), server.URL+"/oembed", "https://xml.example.com/v/1")
+	if !errors.Is(err, &ErrHTTPStatus{Code: http.StatusNotFound}) {
+		t.Errorf("Expected a 404 status error, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected no XML retry for a missing resource, got %d requests", requests)
+	}
+}
+
+func TestDiscoverOEmbedXML(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/video", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><link rel="alternate" type="text/xml+oembed" href="/oembed?format=xml"></head></html>`))
+	})
+	mux.HandleFunc("/oembed", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("format") != "xml" {
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		w.Header().Set("Content-Type", "text/xml+oembed")
+		w.Write([]byte(mockOEmbedXML))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	oembed, err := NewClient().ExtractOEmbed(server.URL + "/video")
+	if err != nil {
+		t.Fatalf("ExtractOEmbed failed: %v", err)
+	}
+	if oembed.ProviderName != "XML Provider" || oembed.Type != "video" {
+		t.Errorf("Expected the discovered XML endpoint to be used, got %+v", oembed)
+	}
+}
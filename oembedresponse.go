@@ -0,0 +1,104 @@
+package urlmeta
+
+// OEmbedOptions controls Metadata.ToOEmbed
+type OEmbedOptions struct {
+	// MaxWidth and MaxHeight are the consumer's maxwidth and maxheight
+	// parameters; thumbnails and embeds larger than these are left out.
+	// Zero means no limit.
+	MaxWidth  int
+	MaxHeight int
+
+	// ProviderName and ProviderURL override the provider fields taken
+	// from the metadata, e.g. to name the proxying application
+	ProviderName string
+	ProviderURL  string
+
+	// CacheAge is the suggested cache lifetime in seconds (0 leaves it out)
+	CacheAge int
+
+	// HTML, Width and Height make the response a "rich" embed of your own
+	// (e.g. a preview card), used instead of the page's own embed. HTML is
+	// ignored unless Width and Height are set; the reported size is scaled
+	// down to fit MaxWidth and MaxHeight, so the HTML should be responsive.
+	HTML   string
+	Width  int
+	Height int
+}
+
+// ToOEmbed describes m as an oEmbed 1.0 response, so an application can
+// serve oEmbed for arbitrary URLs it proxies. The response is of type
+// "rich" when opts.HTML is set or the page's own oEmbed has embed HTML of
+// known size within the limits, and of type "link" otherwise. The title
+// comes from BestTitle, the author from Author, and the thumbnail from
+// BestImage among images of known size that fit MaxWidth and MaxHeight, as
+// the spec requires thumbnail dimensions.
+func (m *Metadata) ToOEmbed(opts OEmbedOptions) *OEmbed {
+	oembed := &OEmbed{
+		Type:         "link",
+		Version:      "1.0",
+		Title:        m.BestTitle(),
+		AuthorName:   m.Author,
+		ProviderName: firstNonEmpty(opts.ProviderName, m.ProviderName, m.SiteName),
+		ProviderURL:  firstNonEmpty(opts.ProviderURL, m.ProviderURL),
+		CacheAge:     opts.CacheAge,
+	}
+
+	if m.OEmbed != nil {
+		oembed.AuthorName = firstNonEmpty(oembed.AuthorName, m.OEmbed.AuthorName)
+		oembed.AuthorURL = m.OEmbed.AuthorURL
+	}
+
+	switch {
+	case opts.HTML != "" && opts.Width > 0 && opts.Height > 0:
+		oembed.Type = "rich"
+		oembed.HTML = opts.HTML
+		oembed.Width, oembed.Height = fitSize(opts.Width, opts.Height, opts.MaxWidth, opts.MaxHeight)
+	case m.OEmbed != nil && m.OEmbed.HTML != "" && m.OEmbed.Width > 0 && m.OEmbed.Height > 0 &&
+		fitsWithin(m.OEmbed.Width, m.OEmbed.Height, opts.MaxWidth, opts.MaxHeight):
+		oembed.Type = "rich"
+		oembed.HTML = m.OEmbed.HTML
+		oembed.Width, oembed.Height = m.OEmbed.Width, m.OEmbed.Height
+	}
+
+	thumbnails := &Metadata{}
+	for _, img := range m.Images {
+		if img.Width > 0 && img.Height > 0 && fitsWithin(img.Width, img.Height, opts.MaxWidth, opts.MaxHeight) {
+			thumbnails.Images = append(thumbnails.Images, img)
+		}
+	}
+	if img := thumbnails.BestImage(ImageCriteria{}); img != nil {
+		oembed.ThumbnailURL = img.URL
+		oembed.ThumbnailWidth = img.Width
+		oembed.ThumbnailHeight = img.Height
+	}
+
+	return oembed
+}
+
+// fitsWithin reports whether a width x height box fits the limits, where
+// zero means no limit
+func fitsWithin(width, height, maxWidth, maxHeight int) bool {
+	return (maxWidth <= 0 || width <= maxWidth) && (maxHeight <= 0 || height <= maxHeight)
+}
+
+// fitSize scales width x height down to fit the limits, keeping the
+// aspect ratio
+func fitSize(width, height, maxWidth, maxHeight int) (int, int) {
+	if maxWidth > 0 && width > maxWidth {
+		width, height = maxWidth, height*maxWidth/width
+	}
+	if maxHeight > 0 && height > maxHeight {
+		width, height = width*maxHeight/height, maxHeight
+	}
+	return width, height
+}
+
+// firstNonEmpty returns the first of values that isn't empty
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package urlmeta
+
+import "testing"
+
+func oembedFixture() *Metadata {
+	return &Metadata{
+		Title:        "Release notes",
+		URL:          "https://example.com/notes",
+		ProviderName: "Example",
+		ProviderURL:  "https://example.com",
+		Author:       "Jane Doe",
+		Images: []Image{
+			{URL: "https://example.com/unsized.jpg", Source: ImageSourceOpenGraph},
+			{URL: "https://example.com/large.jpg", Width: 1200, Height: 630, Source: ImageSourceOpenGraph},
+			{URL: "https://example.com/small.jpg", Width: 400, Height: 210, Source: ImageSourceTwitter},
+		},
+	}
+}
+
+func TestToOEmbedLink(t *testing.T) {
+	got := oembedFixture().ToOEmbed(OEmbedOptions{CacheAge: 3600})
+
+	want := OEmbed{
+		Type:            "link",
+		Version:         "1.0",
+		Title:           "Release notes",
+		AuthorName:      "Jane Doe",
+		ProviderName:    "Example",
+		ProviderURL:     "https://example.com",
+		CacheAge:        3600,
+		ThumbnailURL:    "https://example.com/large.jpg",
+		ThumbnailWidth:  1200,
+		ThumbnailHeight: 630,
+	}
+	if *got != want {
+		t.Errorf("got %+v, want %+v", *got, want)
+	}
+}
+
+func TestToOEmbedMaxSize(t *testing.T) {
+	got := oembedFixture().ToOEmbed(OEmbedOptions{MaxWidth: 500})
+	if got.ThumbnailURL != "https://example.com/small.jpg" {
+		t.Errorf("Expected the thumbnail that fits, got %s", got.ThumbnailURL)
+	}
+
+	got = oembedFixture().ToOEmbed(OEmbedOptions{MaxWidth: 100})
+	if got.ThumbnailURL != "" || got.ThumbnailWidth != 0 {
+		t.Errorf("Expected no thumbnail, got %+v", got)
+	}
+}
+
+func TestToOEmbedRich(t *testing.T) {
+	m := oembedFixture()
+	m.OEmbed = &OEmbed{Type: "video", HTML: "<iframe></iframe>", Width: 640, Height: 360, AuthorURL: "https://example.com/jane"}
+
+	got := m.ToOEmbed(OEmbedOptions{ProviderName: "Proxy"})
+	if got.Type != "rich" || got.HTML != "<iframe></iframe>" || got.Width != 640 || got.Height != 360 {
+		t.Errorf("Expected the page's embed, got %+v", got)
+	}
+	if got.ProviderName != "Proxy" || got.AuthorURL != "https://example.com/jane" {
+		t.Errorf("Expected provider override and author URL, got %+v", got)
+	}
+
+	// Too large for the consumer: falls back to a link
+	if got := m.ToOEmbed(OEmbedOptions{MaxWidth: 320}); got.Type != "link" || got.HTML != "" {
+		t.Errorf("Expected a link, got %+v", got)
+	}
+
+	// Own HTML is scaled to fit
+	got = m.ToOEmbed(OEmbedOptions{HTML: "<div>card</div>", Width: 600, Height: 300, MaxWidth: 300})
+	if got.Type != "rich" || got.HTML != "<div>card</div>" || got.Width != 300 || got.Height != 150 {
+		t.Errorf("Expected own HTML scaled to fit, got %+v", got)
+	}
+}
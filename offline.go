@@ -0,0 +1,173 @@
+package urlmeta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ExtractFromHTML extracts metadata from a page the caller already
+// downloaded (a crawler's store, a queued message body, ...) without
+// fetching it again. baseURL is the page URL, used to resolve relative
+// links and fill the provider fields. The client's size limit, parsing
+// options, robots handling and image post-processing apply as for Extract;
+// the cache, oEmbed, rendering and AMP, which need the network or the
+// live page, are skipped.
+func (c *Client) ExtractFromHTML(r io.Reader, baseURL string) (*Metadata, error) {
+	return c.ExtractFromHTMLContext(context.Background(), r, baseURL)
+}
+
+// ExtractFromHTMLContext is like ExtractFromHTML but honors ctx
+// cancellation in post-processing that makes requests (image validation,
+// favicon verification)
+func (c *Client) ExtractFromHTMLContext(ctx context.Context, r io.Reader, baseURL string) (*Metadata, error) {
+	pageURL, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidURL, err)
+	}
+	if err := checkBaseURL(pageURL); err != nil {
+		return nil, err
+	}
+
+	page, err := c.readPage(r, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	return c.extractPage(ctx, page), nil
+}
+
+// ExtractFromHTML is a convenience function using default client
+func ExtractFromHTML(r io.Reader, baseURL string) (*Metadata, error) {
+	client := NewClient()
+	return client.ExtractFromHTML(r, baseURL)
+}
+
+// ExtractFromNode is like ExtractFromHTML for a document the caller
+// already parsed with golang.org/x/net/html, avoiding a second parse of
+// large pages. doc is only read, never modified.
+func (c *Client) ExtractFromNode(doc *html.Node, baseURL *url.URL) (*Metadata, error) {
+	return c.ExtractFromNodeContext(context.Background(), doc, baseURL)
+}
+
+// ExtractFromNodeContext is like ExtractFromNode but honors ctx
+// cancellation in post-processing that makes requests
+func (c *Client) ExtractFromNodeContext(ctx context.Context, doc *html.Node, baseURL *url.URL) (*Metadata, error) {
+	if doc == nil {
+		return nil, errors.New("document is nil")
+	}
+	if baseURL == nil {
+		return nil, fmt.Errorf("%w: nil base URL", ErrInvalidURL)
+	}
+	if err := checkBaseURL(baseURL); err != nil {
+		return nil, err
+	}
+
+	page := &htmlPage{
+		doc:      doc,
+		finalURL: baseURL,
+		header:   http.Header{},
+	}
+	return c.extractPage(ctx, page), nil
+}
+
+// ExtractFromNode is a convenience function using default client
+func ExtractFromNode(doc *html.Node, baseURL *url.URL) (*Metadata, error) {
+	client := NewClient()
+	return client.ExtractFromNode(doc, baseURL)
+}
+
+// ExtractFromResponse extracts metadata from a response the caller already
+// obtained, e.g. through their own crawler middleware. The same status,
+// content type, size limit and charset handling as Extract apply, and
+// response headers (X-Robots-Tag, Content-Language, ...) are used. The page
+// URL is taken from resp.Request. A body still carrying a Content-Encoding
+// that the package can decode is decompressed. The body is read but not
+// closed. The cache, oEmbed, rendering and AMP are skipped.
+func (c *Client) ExtractFromResponse(resp *http.Response) (*Metadata, error) {
+	if resp == nil || resp.Request == nil || resp.Request.URL == nil {
+		return nil, fmt.Errorf("%w: response has no request URL", ErrInvalidURL)
+	}
+	if err := checkBaseURL(resp.Request.URL); err != nil {
+		return nil, err
+	}
+
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding != "" && encoding != "identity" {
+		if body, ok := newDecodingBody(encoding, resp.Body); ok {
+			decoded := *resp
+			decoded.Body = body
+			decoded.ContentLength = -1
+			resp = &decoded
+		}
+	}
+
+	ctx := resp.Request.Context()
+	page, err := c.readResponse(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return c.extractPage(ctx, page), nil
+}
+
+// checkBaseURL validates the page URL given for offline extraction
+func checkBaseURL(pageURL *url.URL) error {
+	if pageURL.Scheme != "http" && pageURL.Scheme != "https" {
+		return fmt.Errorf("%w: %s (only http and https are supported)", ErrUnsupportedScheme, pageURL.Scheme)
+	}
+	if pageURL.Host == "" {
+		return fmt.Errorf("%w: %q has no host", ErrInvalidURL, pageURL.String())
+	}
+	return nil
+}
+
+// extractPage runs the extraction pipeline on a page obtained without the
+// client fetching it
+func (c *Client) extractPage(ctx context.Context, page *htmlPage) *Metadata {
+	var debug *DebugInfo
+	if c.debug {
+		debug = &DebugInfo{}
+		ctx = withDebugInfo(ctx, debug)
+	}
+
+	metadata := c.buildMetadata(page, page.finalURL)
+	recordPageDebug(ctx, page)
+	metadata.Debug = debug
+
+	if c.verifyFavicon {
+		c.verifyFaviconURL(ctx, metadata)
+	}
+	c.processImages(ctx, metadata)
+	c.logWarnings(ctx, page.finalURL.String(), metadata)
+	return metadata
+}
+
+// readPage parses a page body obtained without an HTTP response (rendered
+// or supplied by the caller), applying the client's size limit
+func (c *Client) readPage(r io.Reader, pageURL *url.URL) (*htmlPage, error) {
+	limited := &maxBytesReader{
+		r:         r,
+		limit:     c.maxBodySize,
+		remaining: c.maxBodySize,
+		truncate:  !c.strict,
+	}
+
+	body, capture := c.debugReader(limited)
+	doc, err := c.parseHTML(charsetReader(body, ""))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	return &htmlPage{
+		doc:       doc,
+		finalURL:  pageURL,
+		header:    http.Header{},
+		truncated: limited.truncated,
+		rawHead:   capture.head(),
+	}, nil
+}
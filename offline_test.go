@@ -0,0 +1,218 @@
+package urlmeta
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestExtractFromHTML(t *testing.T) {
+	page := `<html lang="en"><head>
+		<title>Stored Page</title>
+		<meta property="og:description" content="Stored description">
+		<meta property="og:image" content="/img/cover.jpg">
+		<link rel="icon" href="/favicon.png">
+	</head><body></body></html>`
+
+	metadata, err := ExtractFromHTML(strings.NewReader(page), "https://example.com/articles/1")
+	if err != nil {
+		t.Fatalf("ExtractFromHTML failed: %v", err)
+	}
+
+	if metadata.Title != "Stored Page" {
+		t.Errorf("Expected title 'Stored Page', got %q", metadata.Title)
+	}
+	if metadata.Description != "Stored description" {
+		t.Errorf("Expected description from og:description, got %q", metadata.Description)
+	}
+	if len(metadata.Images) == 0 || metadata.Images[0].URL != "https://example.com/img/cover.jpg" {
+		t.Errorf("Expected relative image resolved against baseURL, got %+v", metadata.Images)
+	}
+	if metadata.URL != "https://example.com/articles/1" || metadata.ProviderDisplay != "example.com" {
+		t.Errorf("Expected URL and provider from baseURL, got %q / %q", metadata.URL, metadata.ProviderDisplay)
+	}
+	if metadata.Language != "en" {
+		t.Errorf("Expected language 'en', got %q", metadata.Language)
+	}
+}
+
+func TestExtractFromHTMLMatchesExtract(t *testing.T) {
+	server := newStaticServer(mockHTMLBasic)
+	defer server.Close()
+
+	client := NewClient(WithAutoOEmbed(false))
+	fetched, err := client.Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	offline, err := client.ExtractFromHTML(strings.NewReader(mockHTMLBasic), fetched.URL)
+	if err != nil {
+		t.Fatalf("ExtractFromHTML failed: %v", err)
+	}
+
+	if offline.Title != fetched.Title || offline.Description != fetched.Description || len(offline.Images) != len(fetched.Images) {
+		t.Errorf("Expected the same result as Extract, got %+v vs %+v", offline, fetched)
+	}
+}
+
+func TestExtractFromHTMLBaseURL(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		want    error
+	}{
+		{"ftp://example.com/", ErrUnsupportedScheme},
+		{"/relative/path", ErrUnsupportedScheme},
+		{"http://", ErrInvalidURL},
+		{"http://%zz", ErrInvalidURL},
+	}
+
+	for _, tt := range tests {
+		_, err := ExtractFromHTML(strings.NewReader("<html></html>"), tt.baseURL)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("ExtractFromHTML(%q): expected %v, got %v", tt.baseURL, tt.want, err)
+		}
+	}
+}
+
+func TestExtractFromHTMLSizeLimit(t *testing.T) {
+	page := "<html><head><title>Big</title></head><body>" + strings.Repeat("x", 2048) + "</body></html>"
+
+	_, err := NewClient(WithMaxBodySize(1024)).ExtractFromHTML(strings.NewReader(page), "https://example.com/")
+	if !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("Expected ErrBodyTooLarge in strict mode, got %v", err)
+	}
+
+	metadata, err := NewClient(WithMaxBodySize(1024), WithStrictMode(false)).ExtractFromHTML(strings.NewReader(page), "https://example.com/")
+	if err != nil {
+		t.Fatalf("ExtractFromHTML failed: %v", err)
+	}
+	if !metadata.HasWarning(WarningBodyTruncated) {
+		t.Errorf("Expected a body_truncated warning, got %+v", metadata.Warnings)
+	}
+}
+
+func TestExtractFromNode(t *testing.T) {
+	doc, err := html.Parse(strings.NewReader(mockHTMLBasic))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	baseURL := mustParseURL(t, "https://example.com/page")
+
+	fromNode, err := ExtractFromNode(doc, baseURL)
+	if err != nil {
+		t.Fatalf("ExtractFromNode failed: %v", err)
+	}
+	fromHTML, err := ExtractFromHTML(strings.NewReader(mockHTMLBasic), baseURL.String())
+	if err != nil {
+		t.Fatalf("ExtractFromHTML failed: %v", err)
+	}
+
+	if fromNode.Title != fromHTML.Title || fromNode.Description != fromHTML.Description || len(fromNode.Images) != len(fromHTML.Images) {
+		t.Errorf("Expected the same result as ExtractFromHTML, got %+v vs %+v", fromNode, fromHTML)
+	}
+
+	// The document can be extracted again
+	again, err := ExtractFromNode(doc, baseURL)
+	if err != nil || again.Title != fromNode.Title {
+		t.Errorf("Expected a second extraction of the same document to match, got %+v (%v)", again, err)
+	}
+}
+
+func TestExtractFromNodeInvalid(t *testing.T) {
+	doc, _ := html.Parse(strings.NewReader("<html></html>"))
+
+	if _, err := ExtractFromNode(nil, mustParseURL(t, "https://example.com/")); err == nil {
+		t.Error("Expected an error for a nil document")
+	}
+	if _, err := ExtractFromNode(doc, nil); !errors.Is(err, ErrInvalidURL) {
+		t.Errorf("Expected ErrInvalidURL for a nil base URL, got %v", err)
+	}
+	if _, err := ExtractFromNode(doc, mustParseURL(t, "file:///tmp/page.html")); !errors.Is(err, ErrUnsupportedScheme) {
+		t.Errorf("Expected ErrUnsupportedScheme, got %v", err)
+	}
+}
+
+func TestExtractFromResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=iso-8859-1")
+		w.Header().Set("X-Robots-Tag", "nosnippet")
+		// "Café" in Latin-1
+		w.Write([]byte("<html><head><title>Caf\xe9</title><meta name=\"description\" content=\"Hidden\"></head></html>"))
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/menu")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	metadata, err := NewClient(WithRespectRobotsMeta(true)).ExtractFromResponse(resp)
+	if err != nil {
+		t.Fatalf("ExtractFromResponse failed: %v", err)
+	}
+
+	if metadata.Title != "Café" {
+		t.Errorf("Expected Latin-1 title decoded to 'Café', got %q", metadata.Title)
+	}
+	if metadata.URL != server.URL+"/menu" {
+		t.Errorf("Expected URL from the request, got %q", metadata.URL)
+	}
+	if metadata.Description != "" {
+		t.Errorf("Expected X-Robots-Tag nosnippet to apply, got description %q", metadata.Description)
+	}
+}
+
+func TestExtractFromResponseEncoded(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(mockHTMLBasic))
+	gz.Close()
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Content-Type":     {"text/html"},
+			"Content-Encoding": {"gzip"},
+		},
+		Body:    io.NopCloser(&buf),
+		Request: httptest.NewRequest("GET", "https://example.com/", nil),
+	}
+
+	metadata, err := NewClient().ExtractFromResponse(resp)
+	if err != nil {
+		t.Fatalf("ExtractFromResponse failed: %v", err)
+	}
+	if metadata.Title == "" {
+		t.Error("Expected the gzip body to be decoded")
+	}
+}
+
+func TestExtractFromResponseRejects(t *testing.T) {
+	client := NewClient()
+	req := httptest.NewRequest("GET", "https://example.com/", nil)
+
+	tests := []struct {
+		name string
+		resp *http.Response
+		want error
+	}{
+		{"nil response", nil, ErrInvalidURL},
+		{"no request", &http.Response{StatusCode: http.StatusOK}, ErrInvalidURL},
+		{"status", &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}, Body: http.NoBody, Request: req}, &ErrHTTPStatus{Code: http.StatusNotFound}},
+		{"content type", &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": {"application/pdf"}}, Body: http.NoBody, Request: req}, ErrUnsupportedContentType},
+	}
+
+	for _, tt := range tests {
+		if _, err := client.ExtractFromResponse(tt.resp); !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package urlmeta
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"sort"
+)
+
+// maxPaletteImageBytes caps the download when computing an image palette
+const maxPaletteImageBytes = 5 * 1024 * 1024
+
+// maxPaletteImagePixels caps the dimensions of an image decoded for its
+// palette, since a small compressed file can declare a huge canvas
+const maxPaletteImagePixels = 16 * 1024 * 1024
+
+// paletteSampleSize is the number of pixels sampled along each axis
+const paletteSampleSize = 100
+
+// WithImagePalette downloads the preview image picked by BestImage and sets
+// its DominantColor and a Palette of up to size colors ("#rrggbb", most
+// common first), so clients can paint a placeholder while the image loads.
+// PNG, JPEG and GIF images up to 5MB and 16 megapixels are supported. Zero disables palette
+// extraction (default).
+func WithImagePalette(size int) Option {
+	return func(c *Client) {
+		c.paletteSize = size
+	}
+}
+
+// extractPalette fills the palette of metadata's best preview image
+func (c *Client) extractPalette(ctx context.Context, metadata *Metadata) {
+	img := metadata.BestImage(ImageCriteria{})
+	if img == nil {
+		return
+	}
+
+	decoded, err := c.fetchImage(ctx, img.URL)
+	if err != nil {
+		return
+	}
+
+	img.Palette = imagePalette(decoded, c.paletteSize)
+	if len(img.Palette) > 0 {
+		img.DominantColor = img.Palette[0]
+	}
+}
+
+// fetchImage downloads and decodes the image at imageURL
+func (c *Client) fetchImage(ctx context.Context, imageURL string) (image.Image, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &ErrHTTPStatus{Code: resp.StatusCode}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPaletteImageBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxPaletteImageBytes {
+		return nil, ErrBodyTooLarge
+	}
+
+	// Check the declared size before the decoder allocates the pixels
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > maxPaletteImagePixels {
+		return nil, fmt.Errorf("image too large for a palette: %dx%d", config.Width, config.Height)
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	return decoded, err
+}
+
+// imagePalette returns up to size hex colors, most common first. Pixels are
+// sampled on a grid and bucketed to 4 bits per channel; each color is the
+// average of the pixels in its bucket. Mostly transparent pixels are ignored.
+func imagePalette(img image.Image, size int) []string {
+	type bucket struct {
+		r, g, b, count uint64
+	}
+
+	bounds := img.Bounds()
+	stepX := maxInt(bounds.Dx()/paletteSampleSize, 1)
+	stepY := maxInt(bounds.Dy()/paletteSampleSize, 1)
+
+	buckets := make(map[uint16]*bucket)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += stepY {
+		for x := bounds.Min.X; x < bounds.Max.X; x += stepX {
+			r, g, b, a := img.At(x, y).RGBA()
+			if a < 0x8000 {
+				continue
+			}
+			// Undo alpha premultiplication and reduce to 8 bits per channel
+			r, g, b = (r*0xffff/a)>>8, (g*0xffff/a)>>8, (b*0xffff/a)>>8
+
+			key := uint16(r>>4)<<8 | uint16(g>>4)<<4 | uint16(b>>4)
+			bk := buckets[key]
+			if bk == nil {
+				bk = &bucket{}
+				buckets[key] = bk
+			}
+			bk.r += uint64(r)
+			bk.g += uint64(g)
+			bk.b += uint64(b)
+			bk.count++
+		}
+	}
+
+	ranked := make([]*bucket, 0, len(buckets))
+	for _, bk := range buckets {
+		ranked = append(ranked, bk)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].count != ranked[j].count {
+			return ranked[i].count > ranked[j].count
+		}
+		// Deterministic order for equally common colors
+		return ranked[i].r/ranked[i].count < ranked[j].r/ranked[j].count
+	})
+
+	if len(ranked) > size {
+		ranked = ranked[:size]
+	}
+
+	palette := make([]string, 0, len(ranked))
+	for _, bk := range ranked {
+		palette = append(palette, fmt.Sprintf("#%02x%02x%02x", bk.r/bk.count, bk.g/bk.count, bk.b/bk.count))
+	}
+	return palette
+}
+
+// maxInt returns the larger of a and b
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
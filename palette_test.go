@@ -0,0 +1,117 @@
+package urlmeta
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// stripedImage is 100x100: 70 rows of red, 20 of blue, 10 transparent
+func stripedImage() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 100, 100))
+	for y := 0; y < 100; y++ {
+		c := color.NRGBA{R: 255, A: 255}
+		switch {
+		case y >= 90:
+			c = color.NRGBA{G: 255, A: 0}
+		case y >= 70:
+			c = color.NRGBA{B: 255, A: 255}
+		}
+		for x := 0; x < 100; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestImagePalette(t *testing.T) {
+	palette := imagePalette(stripedImage(), 5)
+
+	expected := []string{"#ff0000", "#0000ff"}
+	if !reflect.DeepEqual(palette, expected) {
+		t.Errorf("Expected palette %v, got %v", expected, palette)
+	}
+
+	if limited := imagePalette(stripedImage(), 1); len(limited) != 1 || limited[0] != "#ff0000" {
+		t.Errorf("Expected single dominant color, got %v", limited)
+	}
+}
+
+func TestWithImagePalette(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, stripedImage()); err != nil {
+		t.Fatalf("Failed to encode PNG: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><head>
+				<meta name="twitter:image" content="/other.png">
+				<meta property="og:image" content="/cover.png">
+			</head></html>`))
+		case "/cover.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(buf.Bytes())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	metadata, err := NewClient(WithImagePalette(3)).Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	cover := metadata.Images[1]
+	if cover.DominantColor != "#ff0000" {
+		t.Errorf("Expected dominant color #ff0000, got '%s'", cover.DominantColor)
+	}
+
+	if !reflect.DeepEqual(cover.Palette, []string{"#ff0000", "#0000ff"}) {
+		t.Errorf("Unexpected palette %v", cover.Palette)
+	}
+
+	if other := metadata.Images[0]; other.DominantColor != "" || other.Palette != nil {
+		t.Errorf("Expected only the best image to get a palette, got %+v", other)
+	}
+}
+
+func TestFetchImageRejectsHugeCanvas(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatalf("Failed to encode PNG: %v", err)
+	}
+
+	// Declare a 30000x30000 canvas in the IHDR chunk, which follows the
+	// 8-byte signature, and fix up its CRC
+	data := buf.Bytes()
+	binary.BigEndian.PutUint32(data[16:], 30000)
+	binary.BigEndian.PutUint32(data[20:], 30000)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	_, err := NewClient().fetchImage(context.Background(), server.URL)
+	if err == nil {
+		t.Fatal("Expected an image declaring a huge canvas to be rejected")
+	}
+	if !strings.Contains(err.Error(), "30000x30000") {
+		t.Errorf("Expected the canvas size to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package urlmeta
+
+import (
+	"bytes"
+	"io"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// WithHeadOnly stops reading the response as soon as the document head ends
+// (at </head> or the first <body> tag). Most metadata lives in the head, so
+// this saves bandwidth and latency on large pages at the cost of anything
+// declared inside the body, such as inline microdata.
+func WithHeadOnly(headOnly bool) Option {
+	return func(c *Client) {
+		c.headOnly = headOnly
+	}
+}
+
+// parseHTML parses r into a node tree, honoring head-only mode
+func (c *Client) parseHTML(r io.Reader) (*html.Node, error) {
+	if c.headOnly {
+		return parseHead(r)
+	}
+	return html.Parse(r)
+}
+
+// parseHead streams tokens from r until the head is complete and parses only
+// that prefix. The rest of the body is never read.
+func parseHead(r io.Reader) (*html.Node, error) {
+	var buf bytes.Buffer
+	z := html.NewTokenizer(r)
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if err := z.Err(); err != io.EOF {
+				return nil, err
+			}
+			break
+		}
+
+		raw := z.Raw()
+		if tt == html.StartTagToken || tt == html.EndTagToken {
+			name, _ := z.TagName()
+			a := atom.Lookup(name)
+			if (tt == html.EndTagToken && a == atom.Head) || (tt == html.StartTagToken && a == atom.Body) {
+				if tt == html.EndTagToken {
+					buf.Write(raw)
+				}
+				break
+			}
+		}
+		buf.Write(raw)
+	}
+
+	return html.Parse(&buf)
+}
@@ -0,0 +1,94 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseHead(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		title string
+	}{
+		{
+			name:  "stops at closing head",
+			input: `<html><head><title>Head Title</title></head><body><p>ignored</p></body></html>`,
+			title: "Head Title",
+		},
+		{
+			name:  "stops at body without closing head",
+			input: `<html><head><title>No Close</title><body><title>Body Title</title></body>`,
+			title: "No Close",
+		},
+		{
+			name:  "script containing head tag",
+			input: `<html><head><script>var s = "</head>";</script><title>Script</title></head></html>`,
+			title: "Script",
+		},
+		{
+			name:  "no head at all",
+			input: `<title>Bare</title>`,
+			title: "Bare",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parseHead(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("parseHead failed: %v", err)
+			}
+
+			metadata := &Metadata{}
+			extractFromNode(doc, metadata, nil)
+			if metadata.Title != tt.title {
+				t.Errorf("Expected title '%s', got '%s'", tt.title, metadata.Title)
+			}
+		})
+	}
+}
+
+func TestWithHeadOnlyStopsReading(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Streamed</title><meta name="description" content="Head only"></head><body>`))
+		w.(http.Flusher).Flush()
+
+		// Never finish the body; a full parse would block until timeout
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithHeadOnly(true), WithTimeout(2*time.Second))
+	metadata, err := client.Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.Title != "Streamed" {
+		t.Errorf("Expected title 'Streamed', got '%s'", metadata.Title)
+	}
+
+	if metadata.Description != "Head only" {
+		t.Errorf("Expected description 'Head only', got '%s'", metadata.Description)
+	}
+}
+
+func BenchmarkParseHead(b *testing.B) {
+	page := mockHTMLComplete + strings.Repeat("<p>body content</p>", 5000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = parseHead(strings.NewReader(page))
+	}
+}
@@ -0,0 +1,175 @@
+package urlmeta
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	_ "image/gif"  // register GIF for image.DecodeConfig
+	_ "image/jpeg" // register JPEG for image.DecodeConfig
+	_ "image/png"  // register PNG for image.DecodeConfig
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// imageProbeBytes is how much of an image is read to find its dimensions.
+// PNG, GIF and WebP headers fit in a few dozen bytes; JPEG dimensions follow
+// any EXIF block, which rarely exceeds this.
+const imageProbeBytes = 64 * 1024
+
+// WithProbeImages fetches the start of every image whose width or height
+// wasn't declared by the page and decodes its real dimensions and content
+// type. Requests ask for only the first 64KB with a Range header and stop
+// reading there even if the server ignores it. PNG, JPEG, GIF and WebP are
+// supported; images that fail to load or decode are left unchanged.
+func WithProbeImages(probe bool) Option {
+	return func(c *Client) {
+		c.probeImages = probe
+	}
+}
+
+// probeImageSizes fills in missing dimensions and types of metadata.Images
+func (c *Client) probeImageSizes(ctx context.Context, metadata *Metadata) {
+	eachImage(metadata.Images, func(img *Image) {
+		if img.Width > 0 && img.Height > 0 && img.Type != "" {
+			return
+		}
+
+		width, height, contentType, err := c.probeImageSize(ctx, img.URL)
+		if err != nil {
+			return
+		}
+		if img.Width <= 0 || img.Height <= 0 {
+			img.Width, img.Height = width, height
+		}
+		if img.Type == "" {
+			img.Type = contentType
+		}
+	})
+}
+
+// probeImageSize downloads the head of imageURL and decodes its dimensions
+func (c *Client) probeImageSize(ctx context.Context, imageURL string) (int, int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", imageProbeBytes-1))
+
+	resp, err := c.do(req)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return 0, 0, "", &ErrHTTPStatus{Code: resp.StatusCode}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, imageProbeBytes))
+	if err != nil {
+		return 0, 0, "", err
+	}
+
+	width, height, format, err := decodeImageSize(data)
+	if err != nil {
+		return 0, 0, "", err
+	}
+
+	contentType := strings.TrimSpace(strings.SplitN(resp.Header.Get("Content-Type"), ";", 2)[0])
+	if !strings.HasPrefix(contentType, "image/") {
+		contentType = "image/" + format
+	}
+
+	return width, height, contentType, nil
+}
+
+// WithValidateImages checks every extracted image with a HEAD request
+// (falling back to GET when HEAD is rejected) and drops images that return
+// a non-2xx status or a non-image content type. Surviving images are
+// annotated with the ContentType and ContentLength the server reported.
+func WithValidateImages(validate bool) Option {
+	return func(c *Client) {
+		c.validateImages = validate
+	}
+}
+
+// validateImageURLs removes unreachable images from metadata.Images
+func (c *Client) validateImageURLs(ctx context.Context, metadata *Metadata) {
+	alive := make(map[*Image]bool, len(metadata.Images))
+	var mu sync.Mutex
+
+	eachImage(metadata.Images, func(img *Image) {
+		resp, ok := c.probeImage(ctx, img.URL)
+		if !ok {
+			return
+		}
+
+		img.ContentType = strings.TrimSpace(strings.SplitN(resp.Header.Get("Content-Type"), ";", 2)[0])
+		if resp.ContentLength > 0 {
+			img.ContentLength = resp.ContentLength
+		}
+
+		mu.Lock()
+		alive[img] = true
+		mu.Unlock()
+	})
+
+	kept := metadata.Images[:0]
+	for idx := range metadata.Images {
+		if alive[&metadata.Images[idx]] {
+			kept = append(kept, metadata.Images[idx])
+		}
+	}
+	metadata.Images = kept
+}
+
+// decodeImageSize returns the dimensions and format name of the image whose
+// first bytes are data
+func decodeImageSize(data []byte) (int, int, string, error) {
+	if width, height, ok := decodeWebPSize(data); ok {
+		return width, height, "webp", nil
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, "", err
+	}
+	return config.Width, config.Height, format, nil
+}
+
+// decodeWebPSize parses the canvas size from a WebP header, which the
+// standard library has no decoder for
+func decodeWebPSize(data []byte) (int, int, bool) {
+	if len(data) < 30 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return 0, 0, false
+	}
+
+	switch string(data[12:16]) {
+	case "VP8 ":
+		// Lossy: 3-byte frame tag, start code 9d 01 2a, then 14-bit sizes
+		if data[23] != 0x9d || data[24] != 0x01 || data[25] != 0x2a {
+			return 0, 0, false
+		}
+		width := int(binary.LittleEndian.Uint16(data[26:28]) & 0x3fff)
+		height := int(binary.LittleEndian.Uint16(data[28:30]) & 0x3fff)
+		return width, height, true
+	case "VP8L":
+		// Lossless: signature 0x2f, then 14-bit width-1 and height-1
+		if data[20] != 0x2f {
+			return 0, 0, false
+		}
+		bits := binary.LittleEndian.Uint32(data[21:25])
+		return int(bits&0x3fff) + 1, int((bits>>14)&0x3fff) + 1, true
+	case "VP8X":
+		// Extended: 24-bit canvas width-1 and height-1
+		width := int(data[24]) | int(data[25])<<8 | int(data[26])<<16
+		height := int(data[27]) | int(data[28])<<8 | int(data[29])<<16
+		return width + 1, height + 1, true
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,172 @@
+package urlmeta
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func encodeTestImage(t *testing.T, format string, width, height int) []byte {
+	var buf bytes.Buffer
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatalf("Failed to encode %s: %v", format, err)
+	}
+	return buf.Bytes()
+}
+
+func TestProbeImages(t *testing.T) {
+	pngData := encodeTestImage(t, "png", 640, 480)
+	jpegData := encodeTestImage(t, "jpeg", 320, 200)
+	var rangeRequests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") != "" {
+			atomic.AddInt32(&rangeRequests, 1)
+		}
+
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><head>
+				<meta property="og:image" content="/photo.png">
+				<meta property="og:image" content="/declared.jpg">
+				<meta property="og:image:width" content="1000">
+				<meta property="og:image:height" content="500">
+				<meta property="og:image:type" content="image/jpeg">
+				<meta property="og:image" content="/untyped">
+				<meta property="og:image" content="/missing.png">
+			</head></html>`))
+		case "/photo.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(pngData)
+		case "/untyped":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write(jpegData)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	metadata, err := NewClient(WithProbeImages(true)).Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	expected := []Image{
+		{URL: server.URL + "/photo.png", Width: 640, Height: 480, Type: "image/png", Source: ImageSourceOpenGraph},
+		{URL: server.URL + "/declared.jpg", Width: 1000, Height: 500, Type: "image/jpeg", Source: ImageSourceOpenGraph},
+		{URL: server.URL + "/untyped", Width: 320, Height: 200, Type: "image/jpeg", Source: ImageSourceOpenGraph},
+		{URL: server.URL + "/missing.png", Source: ImageSourceOpenGraph},
+	}
+
+	if len(metadata.Images) != len(expected) {
+		t.Fatalf("Expected %d images, got %d", len(expected), len(metadata.Images))
+	}
+
+	for i, img := range metadata.Images {
+		if !reflect.DeepEqual(img, expected[i]) {
+			t.Errorf("Image %d: expected %+v, got %+v", i, expected[i], img)
+		}
+	}
+
+	// The fully declared image must not be fetched
+	if rangeRequests != 3 {
+		t.Errorf("Expected 3 ranged probe requests, got %d", rangeRequests)
+	}
+}
+
+func TestDecodeWebPSize(t *testing.T) {
+	header := func(chunk string, payload ...byte) []byte {
+		data := append([]byte("RIFF\x00\x00\x00\x00WEBP"+chunk+"\x00\x00\x00\x00"), payload...)
+		return append(data, make([]byte, 32)...)
+	}
+
+	tests := []struct {
+		name          string
+		data          []byte
+		width, height int
+		ok            bool
+	}{
+		{"lossy", header("VP8 ", 0, 0, 0, 0x9d, 0x01, 0x2a, 0x80, 0x02, 0xe0, 0x01), 640, 480, true},
+		{"lossless", header("VP8L", 0x2f, 0x7f, 0xc2, 0x77, 0x00), 640, 480, true},
+		{"extended", header("VP8X", 0, 0, 0, 0, 0x7f, 0x02, 0x00, 0xdf, 0x01, 0x00), 640, 480, true},
+		{"not webp", encodeTestImage(t, "png", 1, 1), 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height, ok := decodeWebPSize(tt.data)
+			if ok != tt.ok || width != tt.width || height != tt.height {
+				t.Errorf("Expected %dx%d ok=%v, got %dx%d ok=%v", tt.width, tt.height, tt.ok, width, height, ok)
+			}
+		})
+	}
+}
+
+func TestValidateImages(t *testing.T) {
+	pngData := encodeTestImage(t, "png", 10, 10)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><head>
+				<meta property="og:image" content="/ok.png">
+				<meta property="og:image" content="/gone.png">
+				<meta property="og:image" content="/page.html">
+				<meta name="twitter:image" content="/nohead.png">
+			</head></html>`))
+		case "/ok.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(pngData)
+		case "/page.html":
+			w.Header().Set("Content-Type", "text/html")
+		case "/nohead.png":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.Header().Set("Content-Type", "image/png; charset=binary")
+			w.Write(pngData)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	metadata, err := NewClient(WithValidateImages(true)).Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	expected := []Image{
+		{URL: server.URL + "/ok.png", Source: ImageSourceOpenGraph, ContentType: "image/png", ContentLength: int64(len(pngData))},
+		{URL: server.URL + "/nohead.png", Source: ImageSourceTwitter, ContentType: "image/png", ContentLength: int64(len(pngData))},
+	}
+
+	if len(metadata.Images) != len(expected) {
+		t.Fatalf("Expected %d images, got %d: %+v", len(expected), len(metadata.Images), metadata.Images)
+	}
+
+	for i, img := range metadata.Images {
+		if !reflect.DeepEqual(img, expected[i]) {
+			t.Errorf("Image %d: expected %+v, got %+v", i, expected[i], img)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package urlmeta
+
+import "strings"
+
+// Product holds Open Graph commerce properties (product:* and og:availability)
+type Product struct {
+	Price        string `json:"price,omitempty"` // raw amount, e.g. "19.99"
+	Currency     string `json:"currency,omitempty"`
+	Availability string `json:"availability,omitempty"` // e.g. "instock", "oos", "preorder"
+	Brand        string `json:"brand,omitempty"`
+}
+
+// product returns metadata.Product, allocating it on first use
+func (m *Metadata) product() *Product {
+	if m.Product == nil {
+		m.Product = &Product{}
+	}
+	return m.Product
+}
+
+// processProduct handles product/commerce Open Graph properties, reporting
+// whether property was one of them. The first value of each property wins.
+func processProduct(property, content string, metadata *Metadata) bool {
+	var target *string
+
+	switch property {
+	case "product:price:amount", "og:price:amount":
+		target = &metadata.product().Price
+	case "product:price:currency", "og:price:currency":
+		content = strings.ToUpper(content)
+		target = &metadata.product().Currency
+	case "og:availability", "product:availability":
+		content = strings.ToLower(content)
+		target = &metadata.product().Availability
+	case "product:brand", "og:brand":
+		target = &metadata.product().Brand
+	default:
+		return false
+	}
+
+	if *target == "" {
+		*target = content
+	}
+	return true
+}
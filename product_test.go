@@ -0,0 +1,65 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const mockHTMLProduct = `
+<!DOCTYPE html>
+<html>
+<head>
+	<title>Product Test</title>
+	<meta property="og:type" content="product">
+	<meta property="product:price:amount" content="19.99">
+	<meta property="product:price:currency" content="usd">
+	<meta property="og:availability" content="InStock">
+	<meta property="product:brand" content="Acme">
+</head>
+<body></body>
+</html>
+`
+
+func TestExtractProduct(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLProduct))
+	}))
+	defer server.Close()
+
+	metadata, err := Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.Product == nil {
+		t.Fatal("Expected product to be extracted")
+	}
+
+	expected := Product{Price: "19.99", Currency: "USD", Availability: "instock", Brand: "Acme"}
+	if *metadata.Product != expected {
+		t.Errorf("Expected product %+v, got %+v", expected, *metadata.Product)
+	}
+
+	if metadata.Type != "product" {
+		t.Errorf("Expected type 'product', got '%s'", metadata.Type)
+	}
+}
+
+func TestExtractProductAbsent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLOpenGraph))
+	}))
+	defer server.Close()
+
+	metadata, err := Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.Product != nil {
+		t.Errorf("Expected nil product, got %+v", metadata.Product)
+	}
+}
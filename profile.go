@@ -0,0 +1,38 @@
+package urlmeta
+
+// Profile holds profile:* Open Graph properties (og:type profile)
+type Profile struct {
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+	Username  string `json:"username,omitempty"`
+}
+
+// profile returns metadata.Profile, allocating it on first use
+func (m *Metadata) profile() *Profile {
+	if m.Profile == nil {
+		m.Profile = &Profile{}
+	}
+	return m.Profile
+}
+
+// processProfile handles the profile: Open Graph namespace, reporting
+// whether property was one of them
+func processProfile(property, content string, metadata *Metadata) bool {
+	var target *string
+
+	switch property {
+	case "profile:first_name":
+		target = &metadata.profile().FirstName
+	case "profile:last_name":
+		target = &metadata.profile().LastName
+	case "profile:username":
+		target = &metadata.profile().Username
+	default:
+		return false
+	}
+
+	if *target == "" {
+		*target = content
+	}
+	return true
+}
@@ -0,0 +1,38 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExtractProfile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head>
+			<meta property="og:type" content="profile">
+			<meta property="profile:first_name" content="Jane">
+			<meta property="profile:last_name" content="Doe">
+			<meta property="profile:username" content="janedoe">
+		</head></html>`))
+	}))
+	defer server.Close()
+
+	metadata, err := Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.Profile == nil {
+		t.Fatal("Expected profile to be extracted")
+	}
+
+	expected := Profile{FirstName: "Jane", LastName: "Doe", Username: "janedoe"}
+	if *metadata.Profile != expected {
+		t.Errorf("Expected profile %+v, got %+v", expected, *metadata.Profile)
+	}
+
+	if metadata.Book != nil {
+		t.Errorf("Expected nil book, got %+v", metadata.Book)
+	}
+}
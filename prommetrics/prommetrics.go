@@ -0,0 +1,91 @@
+// Package prommetrics exports urlmeta client metrics to Prometheus.
+//
+//	m := prommetrics.New(prometheus.DefaultRegisterer)
+//	client := urlmeta.NewClient(urlmeta.WithMetrics(m))
+//
+// The cache hit ratio can be computed as
+//
+//	sum(rate(urlmeta_cache_lookups_total{result="hit"}[5m]))
+//	  / sum(rate(urlmeta_cache_lookups_total[5m]))
+package prommetrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/alfarisi/urlmeta"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics implements urlmeta.Metrics with Prometheus collectors
+type Metrics struct {
+	extractions        *prometheus.CounterVec
+	extractionDuration *prometheus.HistogramVec
+	fetchDuration      *prometheus.HistogramVec
+	bytesDownloaded    prometheus.Counter
+	cacheLookups       *prometheus.CounterVec
+}
+
+var _ urlmeta.Metrics = (*Metrics)(nil)
+
+// New creates the collectors and registers them with reg
+// (prometheus.DefaultRegisterer when nil). It panics if a collector with
+// the same name is already registered, like prometheus.MustRegister.
+func New(reg prometheus.Registerer) *Metrics {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+
+	m := &Metrics{
+		extractions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "urlmeta_extractions_total",
+			Help: "Extractions by strategy (oembed_first, html_only or parallel) and outcome (success or error).",
+		}, []string{"strategy", "outcome"}),
+		extractionDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "urlmeta_extraction_duration_seconds",
+			Help:    "Time spent extracting a URL, by strategy.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"strategy"}),
+		fetchDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "urlmeta_fetch_duration_seconds",
+			Help:    "Time until response headers arrived, by status code (0 when the request failed).",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"code"}),
+		bytesDownloaded: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "urlmeta_downloaded_bytes_total",
+			Help: "Response body bytes read, after decompression.",
+		}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "urlmeta_cache_lookups_total",
+			Help: "Cache lookups by result (hit or miss).",
+		}, []string{"result"}),
+	}
+
+	reg.MustRegister(m.extractions, m.extractionDuration, m.fetchDuration, m.bytesDownloaded, m.cacheLookups)
+	return m
+}
+
+// ObserveExtraction implements urlmeta.Metrics
+func (m *Metrics) ObserveExtraction(strategy urlmeta.ExtractionStrategy, outcome string, duration time.Duration) {
+	m.extractions.WithLabelValues(strategy.String(), outcome).Inc()
+	m.extractionDuration.WithLabelValues(strategy.String()).Observe(duration.Seconds())
+}
+
+// ObserveFetch implements urlmeta.Metrics
+func (m *Metrics) ObserveFetch(status int, duration time.Duration) {
+	m.fetchDuration.WithLabelValues(strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+// AddBytesDownloaded implements urlmeta.Metrics
+func (m *Metrics) AddBytesDownloaded(n int64) {
+	m.bytesDownloaded.Add(float64(n))
+}
+
+// ObserveCacheLookup implements urlmeta.Metrics
+func (m *Metrics) ObserveCacheLookup(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cacheLookups.WithLabelValues(result).Inc()
+}
@@ -0,0 +1,58 @@
+package prommetrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alfarisi/urlmeta"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := New(reg)
+
+	m.ObserveExtraction(urlmeta.StrategyOEmbedFirst, urlmeta.OutcomeSuccess, time.Second)
+	m.ObserveExtraction(urlmeta.StrategyHTMLOnly, urlmeta.OutcomeError, time.Second)
+	m.ObserveExtraction(urlmeta.StrategyHTMLOnly, urlmeta.OutcomeError, time.Second)
+	m.ObserveFetch(200, 100*time.Millisecond)
+	m.ObserveFetch(0, time.Second)
+	m.AddBytesDownloaded(512)
+	m.AddBytesDownloaded(512)
+	m.ObserveCacheLookup(true)
+	m.ObserveCacheLookup(false)
+	m.ObserveCacheLookup(false)
+
+	if got := testutil.ToFloat64(m.extractions.WithLabelValues("oembed_first", "success")); got != 1 {
+		t.Errorf("Expected 1 successful oembed_first extraction, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.extractions.WithLabelValues("html_only", "error")); got != 2 {
+		t.Errorf("Expected 2 failed html_only extractions, got %v", got)
+	}
+	if got := testutil.CollectAndCount(m.fetchDuration); got != 2 {
+		t.Errorf("Expected fetch histograms for 2 status codes, got %d", got)
+	}
+	if got := testutil.ToFloat64(m.bytesDownloaded); got != 1024 {
+		t.Errorf("Expected 1024 bytes downloaded, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.cacheLookups.WithLabelValues("miss")); got != 2 {
+		t.Errorf("Expected 2 cache misses, got %v", got)
+	}
+
+	if count, err := testutil.GatherAndCount(reg); err != nil || count == 0 {
+		t.Errorf("Expected metrics to be registered, got %d (%v)", count, err)
+	}
+}
+
+func TestNewRegistersOnce(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	New(reg)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected registering twice on the same registry to panic")
+		}
+	}()
+	New(reg)
+}
@@ -1,5 +1,7 @@
 package urlmeta
 
+import "sync"
+
 // This file contains oEmbed provider definitions
 // To add a new provider, add a new OEmbedProvider entry to knownProviders
 
@@ -11,7 +13,7 @@ package urlmeta
 // - Compile-time validation
 //
 // Source: https://oembed.com/providers.json (curated and verified)
-// Last updated: 2025-01-XX
+// Last updated: 2026-10-16
 var knownProviders = []OEmbedProvider{
 	{
 		Name: "YouTube",
@@ -147,19 +149,287 @@ var knownProviders = []OEmbedProvider{
 			},
 		},
 	},
+	{
+		Name: "Bluesky",
+		URL:  "https://bsky.app",
+		Endpoints: []OEmbedEndpoint{
+			{
+				Schemes: []string{
+					"https://bsky.app/profile/*/post/*",
+				},
+				URL:       "https://embed.bsky.app/oembed",
+				Discovery: true,
+			},
+		},
+	},
+	{
+		Name: "Dailymotion",
+		URL:  "https://www.dailymotion.com",
+		Endpoints: []OEmbedEndpoint{
+			{
+				Schemes: []string{
+					"https://www.dailymotion.com/video/*",
+					"https://dailymotion.com/video/*",
+					"https://dai.ly/*",
+				},
+				URL:       "https://www.dailymotion.com/services/oembed",
+				Discovery: true,
+			},
+		},
+	},
+	{
+		Name: "Giphy",
+		URL:  "https://giphy.com",
+		Endpoints: []OEmbedEndpoint{
+			{
+				Schemes: []string{
+					"https://giphy.com/gifs/*",
+					"https://giphy.com/clips/*",
+					"https://media.giphy.com/media/*",
+					"https://*.giphy.com/media/*",
+					"http://gph.is/*",
+					"https://gph.is/*",
+				},
+				URL:       "https://giphy.com/services/oembed",
+				Discovery: true,
+			},
+		},
+	},
+	{
+		Name: "Tenor",
+		URL:  "https://tenor.com",
+		Endpoints: []OEmbedEndpoint{
+			{
+				Schemes: []string{
+					"https://tenor.com/view/*",
+					"https://tenor.com/*/view/*", // Localized pages, e.g. /de/view/
+				},
+				URL:       "https://tenor.com/oembed",
+				Discovery: true,
+			},
+		},
+	},
+	{
+		Name: "Imgur",
+		URL:  "https://imgur.com",
+		Endpoints: []OEmbedEndpoint{
+			{
+				Schemes: []string{
+					"https://imgur.com/*",
+					"https://www.imgur.com/*",
+					"https://i.imgur.com/*", // Direct images are built from the URL
+				},
+				URL:       "https://api.imgur.com/oembed",
+				Discovery: true,
+			},
+		},
+	},
+	{
+		Name: "CodePen",
+		URL:  "https://codepen.io",
+		Endpoints: []OEmbedEndpoint{
+			{
+				Schemes: []string{
+					"https://codepen.io/*/pen/*",
+					"https://codepen.io/*/full/*",
+					"http://codepen.io/*/pen/*",
+				},
+				URL:       "https://codepen.io/api/oembed",
+				Discovery: true,
+			},
+		},
+	},
+	{
+		Name: "JSFiddle",
+		URL:  "https://jsfiddle.net",
+		Endpoints: []OEmbedEndpoint{
+			{
+				Schemes: []string{
+					"https://jsfiddle.net/*",
+					"http://jsfiddle.net/*",
+				},
+				URL:       "https://jsfiddle.net/services/oembed/",
+				Discovery: true,
+			},
+		},
+	},
+	{
+		Name: "CodeSandbox",
+		URL:  "https://codesandbox.io",
+		Endpoints: []OEmbedEndpoint{
+			{
+				Schemes: []string{
+					"https://codesandbox.io/s/*",
+					"https://codesandbox.io/embed/*",
+					"https://codesandbox.io/p/sandbox/*",
+					"https://codesandbox.io/p/devbox/*",
+				},
+				URL:       "https://codesandbox.io/oembed",
+				Discovery: true,
+			},
+		},
+	},
+	{
+		Name: "Figma",
+		URL:  "https://www.figma.com",
+		Endpoints: []OEmbedEndpoint{
+			{
+				Schemes: []string{
+					"https://www.figma.com/file/*",
+					"https://www.figma.com/proto/*",
+					"https://www.figma.com/design/*",
+					"https://figma.com/file/*",
+					"https://figma.com/proto/*",
+					"https://figma.com/design/*",
+				},
+				URL:       "https://www.figma.com/api/oembed",
+				Discovery: true,
+			},
+		},
+	},
+	{
+		Name: "Loom",
+		URL:  "https://www.loom.com",
+		Endpoints: []OEmbedEndpoint{
+			{
+				Schemes: []string{
+					"https://www.loom.com/share/*",
+					"https://loom.com/share/*",
+				},
+				URL:       "https://www.loom.com/v1/oembed",
+				Discovery: true,
+			},
+		},
+	},
+	{
+		Name: "SlideShare",
+		URL:  "https://www.slideshare.net",
+		Endpoints: []OEmbedEndpoint{
+			{
+				Schemes: []string{
+					"https://www.slideshare.net/*/*",
+					"https://*.slideshare.net/*/*", // Localized, e.g. fr.slideshare.net
+					"https://slideshare.net/*/*",
+				},
+				URL:       "https://www.slideshare.net/api/oembed/2",
+				Discovery: true,
+			},
+		},
+	},
+	{
+		Name: "Speaker Deck",
+		URL:  "https://speakerdeck.com",
+		Endpoints: []OEmbedEndpoint{
+			{
+				Schemes: []string{
+					"https://speakerdeck.com/*/*",
+					"http://speakerdeck.com/*/*",
+				},
+				URL:       "https://speakerdeck.com/oembed.json",
+				Discovery: true,
+			},
+		},
+	},
+	{
+		Name: "Mixcloud",
+		URL:  "https://www.mixcloud.com",
+		Endpoints: []OEmbedEndpoint{
+			{
+				Schemes: []string{
+					"https://www.mixcloud.com/*/*/",
+					"https://mixcloud.com/*/*/",
+					"http://www.mixcloud.com/*/*/",
+				},
+				URL:       "https://www.mixcloud.com/oembed/",
+				Discovery: true,
+			},
+		},
+	},
+	{
+		Name: "Kickstarter",
+		URL:  "https://www.kickstarter.com",
+		Endpoints: []OEmbedEndpoint{
+			{
+				Schemes: []string{
+					"https://www.kickstarter.com/projects/*",
+					"https://kickstarter.com/projects/*",
+					"http://www.kickstarter.com/projects/*",
+				},
+				URL:       "https://www.kickstarter.com/services/oembed",
+				Discovery: true,
+			},
+		},
+	},
+	{
+		Name: "Typeform",
+		URL:  "https://www.typeform.com",
+		Endpoints: []OEmbedEndpoint{
+			{
+				Schemes: []string{
+					"https://form.typeform.com/to/*",
+					"https://*.typeform.com/to/*", // Account subdomains
+				},
+				URL:       "https://form.typeform.com/oembed",
+				Discovery: true,
+			},
+		},
+	},
+	{
+		Name: "Canva",
+		URL:  "https://www.canva.com",
+		Endpoints: []OEmbedEndpoint{
+			{
+				Schemes: []string{
+					"https://www.canva.com/design/*",
+					"https://canva.com/design/*",
+				},
+				URL:       "https://canva.com/_oembed",
+				Discovery: true,
+			},
+		},
+	},
+}
+
+// providersMu guards knownProviders. The slice is copy-on-write: writers
+// replace it under the write lock and never modify it in place, so a
+// snapshot taken under the read lock can be iterated without holding it.
+var providersMu sync.RWMutex
+
+// providerSnapshot returns the current provider list, which must not be
+// modified
+func providerSnapshot() []OEmbedProvider {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	return knownProviders
+}
+
+// cloneProvider returns a copy of p sharing no slices with it
+func cloneProvider(p OEmbedProvider) OEmbedProvider {
+	endpoints := make([]OEmbedEndpoint, len(p.Endpoints))
+	for i, e := range p.Endpoints {
+		endpoints[i] = e
+		endpoints[i].Schemes = append([]string(nil), e.Schemes...)
+	}
+	p.Endpoints = endpoints
+	return p
 }
 
 // GetKnownProviders returns a copy of the known providers list
 // This is useful for displaying supported providers to users
 func GetKnownProviders() []OEmbedProvider {
 	// Return a copy to prevent modifications
-	providers := make([]OEmbedProvider, len(knownProviders))
-	copy(providers, knownProviders)
+	snapshot := providerSnapshot()
+	providers := make([]OEmbedProvider, len(snapshot))
+	for i, p := range snapshot {
+		providers[i] = cloneProvider(p)
+	}
 	return providers
 }
 
 // AddCustomProvider allows users to add custom oEmbed providers at runtime
-// This is useful for private/internal services or new providers not yet in the list
+// This is useful for private/internal services or new providers not yet in the list.
+// Like the other provider functions, it is safe to call while extractions
+// are running.
 //
 // Example:
 //
@@ -175,28 +445,71 @@ func GetKnownProviders() []OEmbedProvider {
 //	}
 //	urlmeta.AddCustomProvider(provider)
 func AddCustomProvider(provider OEmbedProvider) {
-	knownProviders = append(knownProviders, provider)
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	addProviderLocked(provider)
 }
 
-// ProviderCount returns the number of supported oEmbed providers
-func ProviderCount() int {
-	return len(knownProviders)
+// addProviderLocked appends provider; providersMu must be held
+func addProviderLocked(provider OEmbedProvider) {
+	providers := make([]OEmbedProvider, len(knownProviders), len(knownProviders)+1)
+	copy(providers, knownProviders)
+	knownProviders = append(providers, cloneProvider(provider))
 }
 
-// IsProviderSupported checks if a provider name is supported
-func IsProviderSupported(providerName string) bool {
-	for _, p := range knownProviders {
-		if p.Name == providerName {
+// RemoveProvider removes the provider with the given name, so URLs it
+// matched are no longer sent to its oEmbed endpoint (e.g. providers
+// requiring access tokens). It reports whether the provider was found.
+func RemoveProvider(name string) bool {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+
+	for i, p := range knownProviders {
+		if p.Name == name {
+			knownProviders = append(knownProviders[:i:i], knownProviders[i+1:]...)
 			return true
 		}
 	}
 	return false
 }
 
-// GetProviderByName returns a provider by its name
+// ReplaceProvider swaps the provider sharing provider.Name for provider,
+// e.g. to point its endpoint at an internal proxy, keeping its place in
+// the matching order. A provider not yet known is added as with
+// AddCustomProvider. It reports whether a provider was replaced.
+func ReplaceProvider(provider OEmbedProvider) bool {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+
+	for i, p := range knownProviders {
+		if p.Name == provider.Name {
+			providers := make([]OEmbedProvider, len(knownProviders))
+			copy(providers, knownProviders)
+			providers[i] = cloneProvider(provider)
+			knownProviders = providers
+			return true
+		}
+	}
+	addProviderLocked(provider)
+	return false
+}
+
+// ProviderCount returns the number of supported oEmbed providers
+func ProviderCount() int {
+	return len(providerSnapshot())
+}
+
+// IsProviderSupported checks if a provider name is supported
+func IsProviderSupported(providerName string) bool {
+	return GetProviderByName(providerName) != nil
+}
+
+// GetProviderByName returns a copy of the provider with the given name,
+// or nil
 func GetProviderByName(name string) *OEmbedProvider {
-	for _, p := range knownProviders {
+	for _, p := range providerSnapshot() {
 		if p.Name == name {
+			p = cloneProvider(p)
 			return &p
 		}
 	}
@@ -1,7 +1,9 @@
 package urlmeta
 
 import (
+	"fmt"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -245,3 +247,111 @@ func BenchmarkGetKnownProviders(b *testing.B) {
 		_ = GetKnownProviders()
 	}
 }
+
+// restoreProviders puts the provider list back after a test changes it
+func restoreProviders(t *testing.T) {
+	saved := providerSnapshot()
+	t.Cleanup(func() {
+		providersMu.Lock()
+		knownProviders = saved
+		providersMu.Unlock()
+	})
+}
+
+func TestRemoveProvider(t *testing.T) {
+	restoreProviders(t)
+	initialCount := ProviderCount()
+
+	if !IsOEmbedSupported("https://www.instagram.com/p/abc123/") {
+		t.Fatal("Expected Instagram URLs to be supported before removal")
+	}
+	if !RemoveProvider("Instagram") {
+		t.Fatal("Expected Instagram to be removed")
+	}
+
+	if ProviderCount() != initialCount-1 || IsProviderSupported("Instagram") {
+		t.Error("Expected Instagram to be gone")
+	}
+	if IsOEmbedSupported("https://www.instagram.com/p/abc123/") {
+		t.Error("Expected Instagram URLs to no longer be supported")
+	}
+	if RemoveProvider("Instagram") {
+		t.Error("Expected a second removal to report false")
+	}
+}
+
+func TestReplaceProvider(t *testing.T) {
+	restoreProviders(t)
+	initialCount := ProviderCount()
+
+	youtube := *GetProviderByName("YouTube")
+	youtube.Endpoints = []OEmbedEndpoint{{
+		Schemes: youtube.Endpoints[0].Schemes,
+		URL:     "https://oembed-proxy.internal/youtube",
+	}}
+
+	if !ReplaceProvider(youtube) {
+		t.Fatal("Expected YouTube to be replaced")
+	}
+	if ProviderCount() != initialCount {
+		t.Errorf("Expected %d providers, got %d", initialCount, ProviderCount())
+	}
+	if got := findOEmbedEndpoint("https://www.youtube.com/watch?v=123"); got != "https://oembed-proxy.internal/youtube" {
+		t.Errorf("Expected the proxy endpoint, got %s", got)
+	}
+
+	if ReplaceProvider(OEmbedProvider{Name: "Brand New", URL: "https://new.example"}) {
+		t.Error("Expected an unknown provider to be added, not replaced")
+	}
+	if !IsProviderSupported("Brand New") {
+		t.Error("Expected the unknown provider to be added")
+	}
+}
+
+func TestProvidersConcurrentUse(t *testing.T) {
+	restoreProviders(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("Concurrent%d", i)
+			AddCustomProvider(OEmbedProvider{
+				Name:      name,
+				URL:       "https://concurrent.example",
+				Endpoints: []OEmbedEndpoint{{Schemes: []string{"https://concurrent.example/*"}, URL: "https://concurrent.example/oembed"}},
+			})
+			ReplaceProvider(*GetProviderByName(name))
+			RemoveProvider(name)
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				IsOEmbedSupported("https://www.youtube.com/watch?v=123")
+				GetKnownProviders()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if IsProviderSupported("Concurrent0") {
+		t.Error("Expected the concurrent providers to be removed")
+	}
+}
+
+func TestProviderCopies(t *testing.T) {
+	restoreProviders(t)
+
+	youtube := GetProviderByName("YouTube")
+	original := youtube.Endpoints[0].URL
+	youtube.Endpoints[0].URL = "https://changed.example/oembed"
+	GetKnownProviders()[0].Endpoints[0].Schemes[0] = "https://changed.example/*"
+
+	if got := GetProviderByName("YouTube").Endpoints[0].URL; got != original {
+		t.Errorf("Expected returned providers to be copies, registry now has %s", got)
+	}
+	if !IsOEmbedSupported("https://www.youtube.com/watch?v=123") {
+		t.Error("Expected the registry schemes to be unaffected")
+	}
+}
@@ -0,0 +1,136 @@
+package urlmeta
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"sync/atomic"
+	"testing"
+)
+
+// newTestProxy returns a forward proxy answering every request itself and
+// counting them
+func newTestProxy(t *testing.T, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if !r.URL.IsAbs() || r.URL.Host != "example.test" {
+			http.Error(w, "expected a proxied request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Via Proxy</title></head></html>`))
+	}))
+	t.Cleanup(proxy.Close)
+	return proxy
+}
+
+func TestWithProxy(t *testing.T) {
+	var hits atomic.Int32
+	proxy := newTestProxy(t, &hits)
+
+	client := NewClient(WithProxy(proxy.URL), WithAutoOEmbed(false))
+	metadata, err := client.Extract("http://example.test/page")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.Title != "Via Proxy" {
+		t.Errorf("Expected title from proxy, got %q", metadata.Title)
+	}
+	if hits.Load() != 1 {
+		t.Errorf("Expected 1 proxied request, got %d", hits.Load())
+	}
+}
+
+func TestWithProxyInvalid(t *testing.T) {
+	for _, proxyURL := range []string{"proxy:3128", "http://%zz"} {
+		client := NewClient(WithProxy(proxyURL), WithAutoOEmbed(false))
+		if _, err := client.Extract("http://example.test/"); !errors.Is(err, ErrInvalidURL) {
+			t.Errorf("WithProxy(%q): expected ErrInvalidURL, got %v", proxyURL, err)
+		}
+	}
+}
+
+func TestWithProxyFuncRotation(t *testing.T) {
+	var hitsA, hitsB atomic.Int32
+	proxies := []*url.URL{
+		mustParseURL(t, newTestProxy(t, &hitsA).URL),
+		mustParseURL(t, newTestProxy(t, &hitsB).URL),
+	}
+
+	var next atomic.Int32
+	client := NewClient(
+		WithProxyFunc(func(*http.Request) (*url.URL, error) {
+			return proxies[int(next.Add(1)-1)%len(proxies)], nil
+		}),
+		WithAutoOEmbed(false),
+	)
+
+	for i := 0; i < 4; i++ {
+		if _, err := client.Extract("http://example.test/"); err != nil {
+			t.Fatalf("Extract failed: %v", err)
+		}
+	}
+
+	if hitsA.Load() != 2 || hitsB.Load() != 2 {
+		t.Errorf("Expected requests split 2/2 across proxies, got %d/%d", hitsA.Load(), hitsB.Load())
+	}
+}
+
+func TestWithProxySSRFProtection(t *testing.T) {
+	var hits atomic.Int32
+	proxy := newTestProxy(t, &hits)
+
+	client := NewClient(WithProxy(proxy.URL), WithSSRFProtection(true), WithAutoOEmbed(false))
+	if _, err := client.Extract("http://127.0.0.1/admin"); !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("Expected ErrBlockedAddress for internal target, got %v", err)
+	}
+	if hits.Load() != 0 {
+		t.Errorf("Expected blocked request not to reach the proxy, got %d hits", hits.Load())
+	}
+}
+
+func TestEnvironmentProxySSRFProtection(t *testing.T) {
+	// http.ProxyFromEnvironment reads the environment once per process,
+	// so the check runs in a fresh test binary with HTTP_PROXY set
+	if os.Getenv("URLMETA_TEST_ENV_PROXY") == "" {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestEnvironmentProxySSRFProtection$", "-test.v")
+		cmd.Env = append(os.Environ(), "URLMETA_TEST_ENV_PROXY=1")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Proxy test failed: %v\n%s", err, out)
+		}
+		return
+	}
+
+	var hits atomic.Int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Via Proxy</title></head></html>`))
+	}))
+	defer proxy.Close()
+	t.Setenv("HTTP_PROXY", proxy.URL)
+	t.Setenv("NO_PROXY", "")
+
+	client := NewClient(WithSSRFProtection(true), WithAutoOEmbed(false))
+	if _, err := client.Extract("http://169.254.169.254/latest/meta-data/"); !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("Expected ErrBlockedAddress for the metadata service, got %v", err)
+	}
+	if hits.Load() != 0 {
+		t.Errorf("Expected the blocked request not to reach the proxy, got %d hits", hits.Load())
+	}
+
+	// Public targets still go through the proxy, even though it is on
+	// loopback
+	metadata, err := client.Extract("http://93.184.216.34/")
+	if err != nil {
+		t.Fatalf("Extract through the proxy failed: %v", err)
+	}
+	if metadata.Title != "Via Proxy" || hits.Load() != 1 {
+		t.Errorf("Expected the request to go through the proxy, got %q with %d hits", metadata.Title, hits.Load())
+	}
+}
@@ -0,0 +1,343 @@
+// Package queue extracts URL metadata in the background and delivers the
+// results to a callback or webhook, for applications where submitting a
+// link and rendering its preview happen at different times.
+//
+//	q := queue.New(client, queue.Options{
+//		Concurrency: 8,
+//		WebhookURL:  "https://cms.example.com/hooks/link-preview",
+//	})
+//	defer q.Close(context.Background())
+//
+//	err := q.Submit(ctx, "https://example.com/article")
+package queue
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/alfarisi/urlmeta"
+)
+
+// ErrClosed is returned by Submit once Close has been called
+var ErrClosed = errors.New("queue closed")
+
+// Defaults for zero Options fields
+const (
+	defaultConcurrency    = 4
+	defaultQueueSize      = 100
+	defaultMaxAttempts    = 3
+	defaultRetryBaseDelay = time.Second
+	defaultRetryMaxDelay  = time.Minute
+	defaultWebhookTimeout = 10 * time.Second
+)
+
+// Options configures a Queue. The zero value is usable, though results
+// are only observable with Callback or WebhookURL set.
+type Options struct {
+	// Concurrency is the number of URLs processed at once (default: 4)
+	Concurrency int
+
+	// QueueSize is the number of submitted URLs waiting for a worker
+	// before Submit blocks (default: 100)
+	QueueSize int
+
+	// MaxAttempts bounds the tries per URL, and per webhook delivery
+	// (default: 3)
+	MaxAttempts int
+
+	// Backoff returns the wait before retry n (default:
+	// urlmeta.ExponentialBackoff(time.Second, time.Minute))
+	Backoff urlmeta.BackoffFunc
+
+	// Retryable reports whether a failed extraction is worth another try
+	// (default: Retryable)
+	Retryable func(error) bool
+
+	// Timeout bounds each extraction attempt (0 uses the client's
+	// timeouts only)
+	Timeout time.Duration
+
+	// Callback receives every result, from the worker goroutines
+	Callback func(Result)
+
+	// WebhookURL receives every result as a JSON POST. Non-2xx answers
+	// are retried like extractions.
+	WebhookURL string
+
+	// WebhookHeader is added to webhook requests (e.g. Authorization)
+	WebhookHeader http.Header
+
+	// WebhookClient sends webhook requests (default: an http.Client with
+	// a 10s timeout)
+	WebhookClient *http.Client
+
+	// Logger reports retries and failed webhook deliveries (nil disables
+	// logging)
+	Logger *slog.Logger
+}
+
+// Result is the outcome of one submitted URL
+type Result struct {
+	URL      string            `json:"url"`
+	Metadata *urlmeta.Metadata `json:"metadata,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	Attempts int               `json:"attempts"`
+
+	// Err is the extraction error, for errors.Is in callbacks
+	Err error `json:"-"`
+}
+
+// Queue processes submitted URLs with a fixed pool of workers
+type Queue struct {
+	client *urlmeta.Client
+	opts   Options
+	jobs   chan string
+
+	// ctx ends when Close gives up waiting, aborting work in progress
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	// closing is closed by Close to turn away Submit calls, including
+	// those waiting for room; jobs is closed once they have all returned
+	closing   chan struct{}
+	senders   sync.WaitGroup
+	closeJobs sync.Once
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// New starts a queue extracting with client. Call Close to stop it.
+func New(client *urlmeta.Client, opts Options) *Queue {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultConcurrency
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultQueueSize
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultMaxAttempts
+	}
+	if opts.Backoff == nil {
+		opts.Backoff = urlmeta.ExponentialBackoff(defaultRetryBaseDelay, defaultRetryMaxDelay)
+	}
+	if opts.Retryable == nil {
+		opts.Retryable = Retryable
+	}
+	if opts.WebhookClient == nil {
+		opts.WebhookClient = &http.Client{Timeout: defaultWebhookTimeout}
+	}
+
+	q := &Queue{
+		client:  client,
+		opts:    opts,
+		jobs:    make(chan string, opts.QueueSize),
+		closing: make(chan struct{}),
+	}
+	q.ctx, q.cancel = context.WithCancel(context.Background())
+
+	for i := 0; i < opts.Concurrency; i++ {
+		q.wg.Add(1)
+		go q.worker()
+	}
+	return q
+}
+
+// Submit adds targetURL to the queue, waiting for room while the queue is
+// full or until ctx ends. It returns ErrClosed after Close.
+func (q *Queue) Submit(ctx context.Context, targetURL string) error {
+	q.mu.RLock()
+	if q.closed {
+		q.mu.RUnlock()
+		return ErrClosed
+	}
+	q.senders.Add(1)
+	q.mu.RUnlock()
+	defer q.senders.Done()
+
+	select {
+	case q.jobs <- targetURL:
+		return nil
+	case <-q.closing:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting URLs and waits for the queued ones to be processed
+// and delivered. Submit calls waiting for room return ErrClosed. If ctx
+// ends first, work in progress and pending webhook deliveries are aborted,
+// the remaining results reach the callback with the cancellation error,
+// and ctx.Err() is returned.
+func (q *Queue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.closing)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.senders.Wait()
+		q.closeJobs.Do(func() { close(q.jobs) })
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// worker processes queued URLs until the queue is closed
+func (q *Queue) worker() {
+	defer q.wg.Done()
+	for targetURL := range q.jobs {
+		q.deliver(q.process(targetURL))
+	}
+}
+
+// process extracts targetURL, retrying failures the options allow
+func (q *Queue) process(targetURL string) Result {
+	result := Result{URL: targetURL}
+
+	for {
+		result.Attempts++
+		metadata, err := q.extract(targetURL)
+		if err == nil {
+			result.Metadata = metadata
+			return result
+		}
+
+		if result.Attempts >= q.opts.MaxAttempts || !q.opts.Retryable(err) || q.wait(result.Attempts) != nil {
+			result.Err = err
+			result.Error = err.Error()
+			return result
+		}
+		q.log("urlmeta/queue: retrying extraction", "url", targetURL, "attempt", result.Attempts+1, "error", err)
+	}
+}
+
+// extract runs a single extraction attempt
+func (q *Queue) extract(targetURL string) (*urlmeta.Metadata, error) {
+	ctx := q.ctx
+	if q.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, q.opts.Timeout)
+		defer cancel()
+	}
+	return q.client.ExtractContext(ctx, targetURL)
+}
+
+// deliver hands result to the callback and the webhook
+func (q *Queue) deliver(result Result) {
+	if q.opts.Callback != nil {
+		q.opts.Callback(result)
+	}
+	if q.opts.WebhookURL == "" {
+		return
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		q.log("urlmeta/queue: encoding webhook payload failed", "url", result.URL, "error", err)
+		return
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = q.post(body)
+		if err == nil {
+			return
+		}
+		if attempt >= q.opts.MaxAttempts || q.wait(attempt) != nil {
+			q.log("urlmeta/queue: webhook delivery failed", "url", result.URL, "attempts", attempt, "error", err)
+			return
+		}
+	}
+}
+
+// post sends one webhook request
+func (q *Queue) post(body []byte) error {
+	req, err := http.NewRequestWithContext(q.ctx, http.MethodPost, q.opts.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for key, values := range q.opts.WebhookHeader {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := q.opts.WebhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+// wait sleeps before retry number attempt, failing if the queue is
+// aborted meanwhile
+func (q *Queue) wait(attempt int) error {
+	timer := time.NewTimer(q.opts.Backoff(attempt))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-q.ctx.Done():
+		return q.ctx.Err()
+	}
+}
+
+// log reports through the configured logger, if any
+func (q *Queue) log(msg string, args ...any) {
+	if q.opts.Logger != nil {
+		q.opts.Logger.Warn(msg, args...)
+	}
+}
+
+// Retryable is the default Options.Retryable. It gives up on errors that
+// another try won't fix: invalid or refused URLs, non-HTML pages, oversized
+// bodies, redirect loops and 4xx answers other than 408 and 429. Anything
+// else, such as timeouts, connection errors and 5xx answers, is retried.
+func Retryable(err error) bool {
+	switch {
+	case errors.Is(err, context.Canceled),
+		errors.Is(err, urlmeta.ErrInvalidURL),
+		errors.Is(err, urlmeta.ErrUnsupportedScheme),
+		errors.Is(err, urlmeta.ErrUnsupportedContentType),
+		errors.Is(err, urlmeta.ErrHostNotAllowed),
+		errors.Is(err, urlmeta.ErrBlockedAddress),
+		errors.Is(err, urlmeta.ErrBodyTooLarge),
+		errors.Is(err, urlmeta.ErrTooManyRedirects):
+		return false
+	}
+
+	var httpErr *urlmeta.ErrHTTPStatus
+	if errors.As(err, &httpErr) && httpErr.Code >= 400 && httpErr.Code < 500 {
+		return httpErr.Code == http.StatusRequestTimeout || httpErr.Code == http.StatusTooManyRequests
+	}
+	return true
+}
@@ -0,0 +1,245 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alfarisi/urlmeta"
+)
+
+func noBackoff(int) time.Duration { return 0 }
+
+// newSite serves pages titled after their path; /flaky fails with 503
+// until its third request, /missing always answers 404
+func newSite(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var flaky atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+			return
+		case "/flaky":
+			if flaky.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, "<html><head><title>Page %s</title></head></html>", r.URL.Path)
+	}))
+	t.Cleanup(server.Close)
+	return server, &flaky
+}
+
+// collector gathers results delivered to a callback
+type collector struct {
+	mu      sync.Mutex
+	results map[string]Result
+}
+
+func (c *collector) callback(r Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.results == nil {
+		c.results = make(map[string]Result)
+	}
+	c.results[r.URL] = r
+}
+
+func TestQueueCallback(t *testing.T) {
+	site, flaky := newSite(t)
+	var got collector
+
+	q := New(urlmeta.NewClient(), Options{Concurrency: 2, Backoff: noBackoff, Callback: got.callback})
+	for _, path := range []string{"/a", "/flaky", "/missing"} {
+		if err := q.Submit(context.Background(), site.URL+path); err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+	}
+	if err := q.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if r := got.results[site.URL+"/a"]; r.Metadata == nil || r.Metadata.Title != "Page /a" || r.Attempts != 1 {
+		t.Errorf("Unexpected result for /a: %+v", r)
+	}
+	if r := got.results[site.URL+"/flaky"]; r.Metadata == nil || r.Attempts != 3 || flaky.Load() != 3 {
+		t.Errorf("Expected /flaky to succeed on the third attempt, got %+v", r)
+	}
+	r := got.results[site.URL+"/missing"]
+	if r.Metadata != nil || r.Error == "" || r.Attempts != 1 {
+		t.Errorf("Expected /missing to fail without retries, got %+v", r)
+	}
+	if !errors.Is(r.Err, &urlmeta.ErrHTTPStatus{Code: http.StatusNotFound}) {
+		t.Errorf("Expected the 404 error, got %v", r.Err)
+	}
+}
+
+func TestQueueWebhook(t *testing.T) {
+	site, _ := newSite(t)
+
+	var (
+		mu       sync.Mutex
+		received []Result
+		calls    atomic.Int32
+	)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		// The first delivery fails and is retried
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var result Result
+		if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+			t.Errorf("Invalid webhook payload: %v", err)
+		}
+		mu.Lock()
+		received = append(received, result)
+		mu.Unlock()
+	}))
+	defer hook.Close()
+
+	q := New(urlmeta.NewClient(), Options{
+		Concurrency:   1,
+		Backoff:       noBackoff,
+		WebhookURL:    hook.URL,
+		WebhookHeader: http.Header{"Authorization": {"Bearer token"}},
+	})
+	q.Submit(context.Background(), site.URL+"/a")
+	q.Submit(context.Background(), site.URL+"/missing")
+	q.Close(context.Background())
+
+	sort.Slice(received, func(i, j int) bool { return received[i].URL < received[j].URL })
+	if len(received) != 2 {
+		t.Fatalf("Expected 2 deliveries, got %+v", received)
+	}
+	if received[0].Metadata == nil || received[0].Metadata.Title != "Page /a" {
+		t.Errorf("Unexpected payload: %+v", received[0])
+	}
+	if received[1].Error == "" {
+		t.Errorf("Expected an error payload, got %+v", received[1])
+	}
+}
+
+func TestQueueClosed(t *testing.T) {
+	q := New(urlmeta.NewClient(), Options{})
+	q.Close(context.Background())
+
+	if err := q.Submit(context.Background(), "https://example.com"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+	if err := q.Close(context.Background()); err != nil {
+		t.Errorf("Expected a second Close to succeed, got %v", err)
+	}
+}
+
+func TestQueueCloseTimeout(t *testing.T) {
+	block := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-block:
+		case <-r.Context().Done():
+		}
+	}))
+	defer slow.Close()
+	defer close(block)
+
+	var got collector
+	q := New(urlmeta.NewClient(), Options{Callback: got.callback})
+	q.Submit(context.Background(), slow.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := q.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the Close deadline, got %v", err)
+	}
+	if r := got.results[slow.URL]; r.Err == nil || r.Attempts != 1 {
+		t.Errorf("Expected the aborted extraction without retries, got %+v", r)
+	}
+}
+
+func TestQueueCloseWithBlockedSubmit(t *testing.T) {
+	block := make(chan struct{})
+	started := make(chan struct{}, 1)
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		select {
+		case <-block:
+		case <-r.Context().Done():
+		}
+	}))
+	defer slow.Close()
+	defer close(block)
+
+	q := New(urlmeta.NewClient(), Options{Concurrency: 1, QueueSize: 1})
+	q.Submit(context.Background(), slow.URL+"/1")
+	<-started
+	q.Submit(context.Background(), slow.URL+"/2")
+
+	// The queue is full, so this Submit waits for room
+	submitted := make(chan error, 1)
+	go func() {
+		submitted <- q.Submit(context.Background(), slow.URL+"/3")
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	closed := make(chan error, 1)
+	go func() { closed <- q.Close(ctx) }()
+
+	select {
+	case err := <-closed:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected the Close deadline, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close ignored its deadline while a Submit was waiting")
+	}
+	select {
+	case err := <-submitted:
+		if !errors.Is(err, ErrClosed) {
+			t.Errorf("Expected the waiting Submit to get ErrClosed, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Submit kept waiting after Close")
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{urlmeta.ErrTimeout, true},
+		{&urlmeta.ErrHTTPStatus{Code: 503}, true},
+		{&urlmeta.ErrHTTPStatus{Code: 429}, true},
+		{&urlmeta.ErrHTTPStatus{Code: 404}, false},
+		{fmt.Errorf("fetch: %w", urlmeta.ErrUnsupportedContentType), false},
+		{urlmeta.ErrBlockedAddress, false},
+		{errors.New("connection reset"), true},
+	}
+
+	for _, tt := range tests {
+		if got := Retryable(tt.err); got != tt.want {
+			t.Errorf("Retryable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package urlmeta
+
+import (
+	"bytes"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Content is the main article of a page, as found by WithFullContent
+type Content struct {
+	HTML string `json:"html"` // cleaned article markup
+	Text string `json:"text"` // plain text, one paragraph per block
+}
+
+// WithFullContent runs a readability-style extractor over the page and
+// fills Metadata.Content with the main article as cleaned HTML and plain
+// text. Scripts, navigation, sidebars, comments and ads are dropped, and
+// links and image sources are made absolute. Has no effect with
+// WithHeadOnly, since the body is never read.
+func WithFullContent(enabled bool) Option {
+	return func(c *Client) {
+		c.fullContent = enabled
+	}
+}
+
+var (
+	// unlikelyCandidates matches class/id values of page chrome
+	unlikelyCandidates = regexp.MustCompile(`(?i)banner|breadcrumb|combx|comment|community|cookie|disqus|footer|header|menu|modal|nav|popup|related|remark|replies|rss|share|shoutbox|sidebar|social|sponsor|subscribe|widget|\bad-|\bads\b|advert|promo`)
+	// maybeCandidates rescues elements matching unlikelyCandidates
+	maybeCandidates = regexp.MustCompile(`(?i)and|article|body|column|main|shadow|content`)
+	// positiveHints and negativeHints adjust a candidate's class weight
+	positiveHints = regexp.MustCompile(`(?i)article|body|content|entry|hentry|main|page|post|text|blog|story`)
+	negativeHints = regexp.MustCompile(`(?i)comment|com-|contact|foot|footer|footnote|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+)
+
+// strippedElements never make it into extracted content
+var strippedElements = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Svg:      true,
+	atom.Iframe:   true,
+	atom.Object:   true,
+	atom.Embed:    true,
+	atom.Form:     true,
+	atom.Button:   true,
+	atom.Input:    true,
+	atom.Select:   true,
+	atom.Textarea: true,
+	atom.Nav:      true,
+	atom.Aside:    true,
+	atom.Footer:   true,
+	atom.Header:   true,
+	atom.Link:     true,
+	atom.Meta:     true,
+}
+
+// keptAttributes are the only attributes preserved in cleaned HTML
+var keptAttributes = map[string]bool{
+	"href":    true,
+	"src":     true,
+	"alt":     true,
+	"title":   true,
+	"colspan": true,
+	"rowspan": true,
+}
+
+// blockElements start a new paragraph in the plain text rendering
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Section: true, atom.Article: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Ul: true, atom.Ol: true, atom.Li: true, atom.Blockquote: true, atom.Pre: true,
+	atom.Table: true, atom.Tr: true, atom.Figure: true, atom.Figcaption: true,
+	atom.Br: true, atom.Hr: true, atom.Dl: true, atom.Dt: true, atom.Dd: true,
+}
+
+// minParagraphLength is the shortest paragraph that contributes to scoring
+const minParagraphLength = 25
+
+// extractContent finds the main article in doc and returns it cleaned, or
+// nil when the page has no body text
+func extractContent(doc *html.Node, baseURL *url.URL) *Content {
+	root := topCandidate(doc)
+	if root == nil {
+		return nil
+	}
+
+	cleaned := cleanContentNode(root, baseURL)
+	if cleaned == nil {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for c := cleaned.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(&buf, c); err != nil {
+			return nil
+		}
+	}
+
+	var sb strings.Builder
+	writeContentText(cleaned, &sb)
+
+	return &Content{
+		HTML: strings.TrimSpace(buf.String()),
+		Text: normalizeParagraphs(sb.String()),
+	}
+}
+
+// topCandidate scores the ancestors of every substantial paragraph and
+// returns the highest scoring one, falling back to <article>, <main> and
+// <body> when nothing qualifies
+func topCandidate(doc *html.Node) *html.Node {
+	scores := make(map[*html.Node]float64)
+	var candidates []*html.Node
+
+	addScore := func(n *html.Node, score float64) {
+		if n == nil || n.Type != html.ElementNode {
+			return
+		}
+		if _, ok := scores[n]; !ok {
+			scores[n] = initialScore(n)
+			candidates = append(candidates, n)
+		}
+		scores[n] += score
+	}
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if strippedElements[n.DataAtom] || isUnlikelyCandidate(n) {
+				return
+			}
+			if n.DataAtom == atom.P || n.DataAtom == atom.Pre {
+				text := strings.Join(strings.Fields(textContent(n)), " ")
+				if len(text) >= minParagraphLength {
+					score := 1 + float64(strings.Count(text, ",")) + minFloat(float64(len(text))/100, 3)
+					addScore(n.Parent, score)
+					if n.Parent != nil {
+						addScore(n.Parent.Parent, score/2)
+					}
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	if len(candidates) == 0 {
+		for _, a := range []atom.Atom{atom.Article, atom.Main, atom.Body} {
+			if n := findElement(doc, a); n != nil {
+				return n
+			}
+		}
+		return nil
+	}
+
+	for _, n := range candidates {
+		scores[n] *= 1 - linkDensity(n)
+	}
+
+	// Stable order keeps ties deterministic (first in document wins)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return scores[candidates[i]] > scores[candidates[j]]
+	})
+	return candidates[0]
+}
+
+// initialScore seeds a candidate's score from its tag and class/id hints
+func initialScore(n *html.Node) float64 {
+	score := classWeight(n)
+	switch n.DataAtom {
+	case atom.Article:
+		score += 10
+	case atom.Div, atom.Main, atom.Section:
+		score += 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		score += 3
+	case atom.Ol, atom.Ul, atom.Dl, atom.Dd, atom.Dt, atom.Li, atom.Form:
+		score -= 3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		score -= 5
+	}
+	return score
+}
+
+// classWeight scores an element's class and id against content hints
+func classWeight(n *html.Node) float64 {
+	weight := 0.0
+	for _, hint := range []string{getAttr(n, "class"), getAttr(n, "id")} {
+		if hint == "" {
+			continue
+		}
+		if negativeHints.MatchString(hint) {
+			weight -= 25
+		}
+		if positiveHints.MatchString(hint) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+// isUnlikelyCandidate reports whether n looks like page chrome by its
+// class, id or role
+func isUnlikelyCandidate(n *html.Node) bool {
+	if n.DataAtom == atom.Body || n.DataAtom == atom.Article || n.DataAtom == atom.Main {
+		return false
+	}
+
+	switch getAttr(n, "role") {
+	case "navigation", "complementary", "banner", "contentinfo", "dialog":
+		return true
+	}
+
+	hints := getAttr(n, "class") + " " + getAttr(n, "id")
+	return unlikelyCandidates.MatchString(hints) && !maybeCandidates.MatchString(hints)
+}
+
+// linkDensity is the fraction of n's text that sits inside links
+func linkDensity(n *html.Node) float64 {
+	total := len(strings.Join(strings.Fields(textContent(n)), " "))
+	if total == 0 {
+		return 0
+	}
+
+	linked := 0
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.DataAtom == atom.A {
+			linked += len(strings.Join(strings.Fields(textContent(n)), " "))
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+
+	return float64(linked) / float64(total)
+}
+
+// cleanContentNode returns a detached copy of n without chrome, unknown
+// attributes or empty elements, with href/src resolved against baseURL and
+// kept only when they are http or https URLs
+func cleanContentNode(n *html.Node, baseURL *url.URL) *html.Node {
+	switch n.Type {
+	case html.TextNode:
+		return &html.Node{Type: html.TextNode, Data: n.Data}
+	case html.ElementNode:
+	default:
+		return nil
+	}
+
+	if strippedElements[n.DataAtom] || isUnlikelyCandidate(n) {
+		return nil
+	}
+
+	clone := &html.Node{Type: html.ElementNode, DataAtom: n.DataAtom, Data: n.Data}
+	for _, attr := range n.Attr {
+		if !keptAttributes[attr.Key] {
+			continue
+		}
+		if attr.Key == "href" || attr.Key == "src" {
+			// Callers render this HTML: javascript:, data: and other
+			// schemes are dropped
+			attr.Val = resolveURL(strings.TrimSpace(attr.Val), baseURL)
+			if !isWebURL(attr.Val) {
+				continue
+			}
+		}
+		clone.Attr = append(clone.Attr, html.Attribute{Key: attr.Key, Val: attr.Val})
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if child := cleanContentNode(c, baseURL); child != nil {
+			clone.AppendChild(child)
+		}
+	}
+
+	if isEmptyContentNode(clone) {
+		return nil
+	}
+	return clone
+}
+
+// isWebURL reports whether s is an absolute http or https URL
+func isWebURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (strings.EqualFold(u.Scheme, "http") || strings.EqualFold(u.Scheme, "https"))
+}
+
+// isEmptyContentNode reports whether an element carries neither text nor
+// media and can be dropped from cleaned content
+func isEmptyContentNode(n *html.Node) bool {
+	switch n.DataAtom {
+	case atom.Img, atom.Br, atom.Hr, atom.Video, atom.Audio, atom.Picture, atom.Source:
+		return false
+	}
+	if strings.TrimSpace(textContent(n)) != "" {
+		return false
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode {
+			return false
+		}
+	}
+	return true
+}
+
+// writeContentText renders n as text, marking block boundaries with
+// newlines for normalizeParagraphs
+func writeContentText(n *html.Node, sb *strings.Builder) {
+	if n.Type == html.TextNode {
+		sb.WriteString(n.Data)
+		return
+	}
+
+	block := n.Type == html.ElementNode && blockElements[n.DataAtom]
+	if block {
+		sb.WriteByte('\n')
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writeContentText(c, sb)
+	}
+	if block {
+		sb.WriteByte('\n')
+	}
+}
+
+// normalizeParagraphs collapses whitespace within each line and separates
+// non-empty lines with a blank line
+func normalizeParagraphs(text string) string {
+	var paragraphs []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			paragraphs = append(paragraphs, line)
+		}
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// minFloat returns the smaller of a and b
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
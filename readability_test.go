@@ -0,0 +1,119 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const mockHTMLArticle = `
+<!DOCTYPE html>
+<html>
+<head><title>Readability Test</title></head>
+<body>
+	<header><a href="/">Home</a> <a href="/about">About</a></header>
+	<div class="sidebar">
+		<p>Subscribe to our newsletter, follow us everywhere, and read our other posts.</p>
+	</div>
+	<div id="story" class="post-content" onclick="track()">
+		<h1>The Article Heading</h1>
+		<p>This is the first paragraph of the article, with enough text, commas, and words to score well.</p>
+		<script>console.log("tracking");</script>
+		<p>The second paragraph links to <a href="/related" class="inline">a related page</a> and keeps going for a while.</p>
+		<div class="share-buttons"><a href="https://twitter.com/share">Tweet</a></div>
+		<img src="/images/figure.png" alt="Figure" width="600">
+		<p></p>
+	</div>
+	<div class="comments">
+		<p>Great article, thanks for writing it, I learned a lot from reading this!</p>
+	</div>
+	<footer>Copyright 2025, all rights reserved, no part may be reproduced.</footer>
+</body>
+</html>
+`
+
+func TestExtractFullContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLArticle))
+	}))
+	defer server.Close()
+
+	metadata, err := NewClient(WithFullContent(true)).Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	content := metadata.Content
+	if content == nil {
+		t.Fatal("Expected content to be extracted")
+	}
+
+	for _, want := range []string{
+		"<h1>The Article Heading</h1>",
+		"first paragraph of the article",
+		`<a href="` + server.URL + `/related">a related page</a>`,
+		`<img src="` + server.URL + `/images/figure.png" alt="Figure"/>`,
+	} {
+		if !strings.Contains(content.HTML, want) {
+			t.Errorf("Expected HTML to contain %q, got:\n%s", want, content.HTML)
+		}
+	}
+
+	for _, unwanted := range []string{"tracking", "Tweet", "newsletter", "Great article", "Copyright", "onclick", "class=", "<p></p>"} {
+		if strings.Contains(content.HTML, unwanted) || strings.Contains(content.Text, unwanted) {
+			t.Errorf("Expected content to exclude %q, got:\n%s", unwanted, content.HTML)
+		}
+	}
+
+	expectedText := "The Article Heading\n\n" +
+		"This is the first paragraph of the article, with enough text, commas, and words to score well.\n\n" +
+		"The second paragraph links to a related page and keeps going for a while."
+	if content.Text != expectedText {
+		t.Errorf("Unexpected text:\n%q\nexpected:\n%q", content.Text, expectedText)
+	}
+
+	plain, err := Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if plain.Content != nil {
+		t.Error("Expected no content without WithFullContent")
+	}
+}
+
+func TestFullContentDropsScriptURLs(t *testing.T) {
+	page := `<html><body><article>
+		<p>This paragraph is long enough, with commas, words and more words, to be picked as the content.</p>
+		<p>Click <a href="javascript:alert(document.cookie)">here</a>, <a href=" JavaScript:void(0)">there</a>
+		or <a href="vbscript:msgbox">elsewhere</a>, then read <a href="/next">the next part</a> of it.</p>
+		<img src="data:image/svg+xml;base64,PHN2Zz48L3N2Zz4=" alt="Inline">
+	</article></body></html>`
+
+	metadata, err := NewClient(WithFullContent(true)).ExtractFromHTML(strings.NewReader(page), "https://example.com/post")
+	if err != nil {
+		t.Fatalf("ExtractFromHTML failed: %v", err)
+	}
+	if metadata.Content == nil {
+		t.Fatal("Expected content to be extracted")
+	}
+
+	html := metadata.Content.HTML
+	for _, unwanted := range []string{"javascript:", "JavaScript:", "vbscript:", "data:"} {
+		if strings.Contains(html, unwanted) {
+			t.Errorf("Expected %q URLs to be dropped, got:\n%s", unwanted, html)
+		}
+	}
+	if !strings.Contains(html, `<a href="https://example.com/next">the next part</a>`) || !strings.Contains(html, `<a>here</a>`) {
+		t.Errorf("Expected web links kept and other links stripped of href, got:\n%s", html)
+	}
+}
+
+func TestNormalizeParagraphs(t *testing.T) {
+	input := "\n  Hello   world \n\n\n second\tline  \n"
+	if got := normalizeParagraphs(input); got != "Hello world\n\nsecond line" {
+		t.Errorf("Unexpected result %q", got)
+	}
+}
@@ -0,0 +1,164 @@
+package urlmeta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// RedditPost holds the Reddit-specific details of a post (see
+// RedditExtractor)
+type RedditPost struct {
+	Subreddit   string `json:"subreddit"`
+	Score       int    `json:"score"`
+	NumComments int    `json:"num_comments"`
+	Permalink   string `json:"permalink"`
+
+	// LinkURL is the page a link post points to (empty for text posts)
+	LinkURL string `json:"link_url,omitempty"`
+
+	NSFW bool `json:"nsfw,omitempty"`
+}
+
+// redditPostPath matches post URLs: /r/<sub>/comments/<id>/... and
+// /comments/<id>/...
+var redditPostPath = regexp.MustCompile(`^(?:/r/[^/]+)?/comments/([a-z0-9]+)(?:/|$)`)
+
+// redditShortPath matches redd.it short links: /<id>
+var redditShortPath = regexp.MustCompile(`^/([a-z0-9]+)/?$`)
+
+// redditExtractor is the SiteExtractor for Reddit posts
+type redditExtractor struct {
+	apiBase string
+}
+
+// RedditExtractor returns a SiteExtractor for Reddit posts on reddit.com
+// (www, old and new) and redd.it short links. It reads the post from
+// Reddit's public JSON API and sets Title, Description (the text of a
+// text post), Author, the publication date, preview images and
+// Metadata.Reddit (subreddit, score, comment count).
+func RedditExtractor() SiteExtractor {
+	return &redditExtractor{apiBase: "https://www.reddit.com"}
+}
+
+// Name implements SiteExtractor
+func (r *redditExtractor) Name() string {
+	return "reddit"
+}
+
+// Match implements SiteExtractor
+func (r *redditExtractor) Match(u *url.URL) bool {
+	return redditPostID(u) != ""
+}
+
+// redditPostID returns the ID of the post u links to, or ""
+func redditPostID(u *url.URL) string {
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case host == "redd.it":
+		if m := redditShortPath.FindStringSubmatch(strings.ToLower(u.Path)); m != nil {
+			return m[1]
+		}
+	case host == "reddit.com" || strings.HasSuffix(host, ".reddit.com"):
+		if m := redditPostPath.FindStringSubmatch(strings.ToLower(u.Path)); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// redditListing is the part of a /comments/<id>.json response used here
+type redditListing struct {
+	Data struct {
+		Children []struct {
+			Data redditPostData `json:"data"`
+		} `json:"children"`
+	} `json:"data"`
+}
+
+// redditPostData is a post as returned by the JSON API
+type redditPostData struct {
+	Title       string  `json:"title"`
+	Author      string  `json:"author"`
+	Subreddit   string  `json:"subreddit"`
+	Score       int     `json:"score"`
+	NumComments int     `json:"num_comments"`
+	Permalink   string  `json:"permalink"`
+	URL         string  `json:"url"`
+	Selftext    string  `json:"selftext"`
+	IsSelf      bool    `json:"is_self"`
+	Over18      bool    `json:"over_18"`
+	CreatedUTC  float64 `json:"created_utc"`
+	Preview     struct {
+		Images []struct {
+			Source struct {
+				URL    string `json:"url"`
+				Width  int    `json:"width"`
+				Height int    `json:"height"`
+			} `json:"source"`
+		} `json:"images"`
+	} `json:"preview"`
+}
+
+// Extract implements SiteExtractor
+func (r *redditExtractor) Extract(ctx context.Context, client *http.Client, u *url.URL) (*Metadata, error) {
+	id := redditPostID(u)
+	apiURL := fmt.Sprintf("%s/comments/%s.json?raw_json=1&limit=1", r.apiBase, id)
+
+	// The response holds the post listing followed by the comments
+	var listings []redditListing
+	if err := getJSON(ctx, client, apiURL, nil, &listings); err != nil {
+		return nil, err
+	}
+	if len(listings) == 0 || len(listings[0].Data.Children) == 0 {
+		return nil, errors.New("reddit post not found")
+	}
+	post := listings[0].Data.Children[0].Data
+
+	permalink := "https://www.reddit.com" + post.Permalink
+	metadata := &Metadata{
+		Title:           post.Title,
+		Description:     strings.TrimSpace(post.Selftext),
+		CanonicalURL:    permalink,
+		ProviderName:    "Reddit",
+		ProviderURL:     "https://www.reddit.com",
+		ProviderDisplay: "reddit.com",
+		SiteName:        "Reddit",
+		Type:            "article",
+		Author:          post.Author,
+		Reddit: &RedditPost{
+			Subreddit:   post.Subreddit,
+			Score:       post.Score,
+			NumComments: post.NumComments,
+			Permalink:   permalink,
+			NSFW:        post.Over18,
+		},
+	}
+	if !post.IsSelf {
+		metadata.Reddit.LinkURL = post.URL
+	}
+	if post.CreatedUTC > 0 {
+		published := time.Unix(int64(post.CreatedUTC), 0).UTC()
+		metadata.PublishedTime = published.Format(time.RFC3339)
+		metadata.PublishedAt = &published
+		metadata.PublishedTimeSource = DateSourceAPI
+	}
+	for _, image := range post.Preview.Images {
+		if image.Source.URL == "" {
+			continue
+		}
+		metadata.Images = append(metadata.Images, Image{
+			URL:    image.Source.URL,
+			Width:  image.Source.Width,
+			Height: image.Source.Height,
+			Source: ImageSourceAPI,
+		})
+	}
+
+	return metadata, nil
+}
@@ -0,0 +1,86 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+const mockRedditPost = `[
+	{"kind": "Listing", "data": {"children": [{"kind": "t3", "data": {
+		"title": "Go 1.23 is released",
+		"author": "gopher",
+		"subreddit": "golang",
+		"score": 1234,
+		"num_comments": 56,
+		"permalink": "/r/golang/comments/abc123/go_123_is_released/",
+		"url": "https://go.dev/blog/go1.23",
+		"selftext": "",
+		"is_self": false,
+		"over_18": false,
+		"created_utc": 1723593600.0,
+		"preview": {"images": [{"source": {"url": "https://preview.redd.it/abc.png?width=1200&s=x", "width": 1200, "height": 630}}]}
+	}}]}},
+	{"kind": "Listing", "data": {"children": []}}
+]`
+
+func TestRedditMatch(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://www.reddit.com/r/golang/comments/abc123/go_123_is_released/", "abc123"},
+		{"https://old.reddit.com/r/golang/comments/abc123/", "abc123"},
+		{"https://reddit.com/comments/abc123", "abc123"},
+		{"https://redd.it/abc123", "abc123"},
+		{"https://www.reddit.com/r/golang/", ""},
+		{"https://www.reddit.com/user/gopher/", ""},
+		{"https://notreddit.com/r/golang/comments/abc123/", ""},
+	}
+
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		if got := redditPostID(u); got != tt.want {
+			t.Errorf("redditPostID(%s) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestRedditExtractor(t *testing.T) {
+	var apiPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(mockRedditPost))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithSiteExtractors(&redditExtractor{apiBase: server.URL}))
+	metadata, err := client.Extract("https://www.reddit.com/r/golang/comments/abc123/go_123_is_released/")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if apiPath != "/comments/abc123.json" {
+		t.Errorf("Expected the post API to be queried, got %s", apiPath)
+	}
+	if metadata.Title != "Go 1.23 is released" || metadata.Author != "gopher" || metadata.ProviderName != "Reddit" {
+		t.Errorf("Unexpected post fields: %+v", metadata)
+	}
+	if metadata.PublishedTime != "2024-08-14T00:00:00Z" || metadata.PublishedTimeSource != DateSourceAPI {
+		t.Errorf("Expected the creation time, got %q (%s)", metadata.PublishedTime, metadata.PublishedTimeSource)
+	}
+
+	post := metadata.Reddit
+	if post == nil {
+		t.Fatal("Expected Reddit details")
+	}
+	if post.Subreddit != "golang" || post.Score != 1234 || post.NumComments != 56 || post.LinkURL != "https://go.dev/blog/go1.23" {
+		t.Errorf("Unexpected Reddit details: %+v", post)
+	}
+
+	if len(metadata.Images) != 1 || metadata.Images[0].URL != "https://preview.redd.it/abc.png?width=1200&s=x" || metadata.Images[0].Source != ImageSourceAPI {
+		t.Errorf("Expected the preview image, got %+v", metadata.Images)
+	}
+}
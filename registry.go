@@ -0,0 +1,319 @@
+package urlmeta
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OEmbedProvidersURL is the official oembed.com provider registry
+const OEmbedProvidersURL = "https://oembed.com/providers.json"
+
+// maxProvidersSize caps a downloaded provider registry (the official one
+// is a few hundred KB)
+const maxProvidersSize = 10 * 1024 * 1024
+
+// providerLoadTimeout bounds LoadProvidersFromURL when ctx has no deadline
+const providerLoadTimeout = 30 * time.Second
+
+// registryProvider is a provider in the oembed.com providers.json format,
+// which provider files written in YAML follow as well
+type registryProvider struct {
+	Name      string `json:"provider_name" yaml:"provider_name"`
+	URL       string `json:"provider_url" yaml:"provider_url"`
+	Endpoints []struct {
+		Schemes   []string `json:"schemes" yaml:"schemes"`
+		URL       string   `json:"url" yaml:"url"`
+		Discovery bool     `json:"discovery" yaml:"discovery"`
+	} `json:"endpoints" yaml:"endpoints"`
+}
+
+// LoadProvidersFromJSON reads providers in the oembed.com providers.json
+// format and merges them into the provider registry: providers with a
+// known name replace it, others are added. Endpoints without schemes are
+// skipped and "{format}" in endpoint URLs is replaced by "json". It returns
+// the number of providers loaded. The registry is left unchanged on error.
+func LoadProvidersFromJSON(r io.Reader) (int, error) {
+	providers, err := parseProviders(r)
+	if err != nil {
+		return 0, err
+	}
+	mergeProviders(providers)
+	return len(providers), nil
+}
+
+// LoadProvidersFromURL downloads a providers.json registry, such as
+// OEmbedProvidersURL, and merges it as LoadProvidersFromJSON does
+func LoadProvidersFromURL(ctx context.Context, registryURL string) (int, error) {
+	providers, _, err := fetchProviders(ctx, http.DefaultClient, "", registryURL, "")
+	if err != nil {
+		return 0, err
+	}
+	mergeProviders(providers)
+	return len(providers), nil
+}
+
+// fetchProviders downloads and parses a providers.json registry. With an
+// etag, an unchanged registry returns nil providers and the same etag.
+func fetchProviders(ctx context.Context, client *http.Client, userAgent, registryURL, etag string) ([]OEmbedProvider, string, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, providerLoadTimeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, registryURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %w", ErrInvalidURL, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch providers: %w", classifyFetchError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && etag != "" {
+		return nil, etag, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", &ErrHTTPStatus{Code: resp.StatusCode}
+	}
+
+	providers, err := parseProviders(&maxBytesReader{r: resp.Body, limit: maxProvidersSize, remaining: maxProvidersSize})
+	if err != nil {
+		return nil, "", err
+	}
+	return providers, resp.Header.Get("ETag"), nil
+}
+
+// WithProviderAutoRefresh downloads the provider registry when the client
+// is created and again every interval, so long-running services pick up
+// new providers without a redeploy. The downloaded providers belong to
+// this client only: they are consulted after the package-wide providers,
+// and entries named like a built-in provider are ignored so the curated
+// built-ins are never replaced. Unchanged registries are skipped using the
+// ETag header and failed downloads keep the current providers. The
+// registry is OEmbedProvidersURL unless set with WithProviderRegistryURL.
+// Call Client.Close to stop refreshing. Disabled by default.
+func WithProviderAutoRefresh(interval time.Duration) Option {
+	return func(c *Client) {
+		c.providerRefresh = interval
+	}
+}
+
+// WithProviderRegistryURL sets the providers.json location used by
+// WithProviderAutoRefresh, e.g. an internal mirror
+func WithProviderRegistryURL(registryURL string) Option {
+	return func(c *Client) {
+		c.providerRegistryURL = registryURL
+	}
+}
+
+// Close stops background work started by the client, such as
+// WithProviderAutoRefresh, and waits for it to finish. It is safe to call
+// more than once.
+func (c *Client) Close() error {
+	if c.stopRefresh != nil {
+		c.stopRefresh()
+		<-c.refreshDone
+	}
+	return nil
+}
+
+// startProviderRefresh runs the WithProviderAutoRefresh loop until Close
+func (c *Client) startProviderRefresh() {
+	if c.providerRefresh <= 0 {
+		return
+	}
+	if c.providerRegistryURL == "" {
+		c.providerRegistryURL = OEmbedProvidersURL
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.stopRefresh = cancel
+	c.refreshDone = make(chan struct{})
+
+	go func() {
+		defer close(c.refreshDone)
+
+		ticker := time.NewTicker(c.providerRefresh)
+		defer ticker.Stop()
+
+		etag := ""
+		for {
+			etag = c.refreshProviders(ctx, etag)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// refreshProviders downloads the registry unless it still matches etag
+// and returns the ETag to send next time
+func (c *Client) refreshProviders(ctx context.Context, etag string) string {
+	providers, newETag, err := fetchProviders(ctx, c.httpClient, c.userAgent, c.providerRegistryURL, etag)
+	if err != nil {
+		if ctx.Err() == nil {
+			c.logInfo(ctx, "urlmeta: provider refresh failed", "url", c.providerRegistryURL, "error", err)
+		}
+		return etag
+	}
+	if providers == nil {
+		c.logDebug(ctx, "urlmeta: providers unchanged", "url", c.providerRegistryURL)
+		return newETag
+	}
+
+	kept := make([]OEmbedProvider, 0, len(providers))
+	for _, p := range providers {
+		if !builtinProviderNames[p.Name] {
+			kept = append(kept, p)
+		}
+	}
+	c.refreshed.Store(&kept)
+	c.logDebug(ctx, "urlmeta: providers refreshed", "url", c.providerRegistryURL, "count", len(providers))
+	return newETag
+}
+
+// refreshedProviders returns the providers downloaded by
+// WithProviderAutoRefresh, which must not be modified
+func (c *Client) refreshedProviders() []OEmbedProvider {
+	if providers := c.refreshed.Load(); providers != nil {
+		return *providers
+	}
+	return nil
+}
+
+// builtinProviderNames holds the names of the providers compiled into the
+// package, which WithProviderAutoRefresh never replaces
+var builtinProviderNames = func() map[string]bool {
+	names := make(map[string]bool, len(knownProviders))
+	for _, p := range knownProviders {
+		names[p.Name] = true
+	}
+	return names
+}()
+
+// LoadProvidersFromFile reads providers from a local file, e.g. internal
+// oEmbed providers kept alongside deployment config, and merges them as
+// LoadProvidersFromJSON does. Files ending in .yaml or .yml are read as
+// YAML with the same field names as providers.json; others as JSON.
+func LoadProvidersFromFile(path string) (int, error) {
+	return loadProvidersFile(os.ReadFile, path)
+}
+
+// LoadProvidersFromFS is LoadProvidersFromFile for a file in fsys, such
+// as an embed.FS compiled into the service
+func LoadProvidersFromFS(fsys fs.FS, name string) (int, error) {
+	return loadProvidersFile(func(name string) ([]byte, error) {
+		return fs.ReadFile(fsys, name)
+	}, name)
+}
+
+// loadProvidersFile reads name with readFile and merges its providers
+func loadProvidersFile(readFile func(string) ([]byte, error), name string) (int, error) {
+	data, err := readFile(name)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read providers: %w", err)
+	}
+
+	var providers []OEmbedProvider
+	switch strings.ToLower(path.Ext(name)) {
+	case ".yaml", ".yml":
+		providers, err = parseProvidersYAML(data)
+	default:
+		providers, err = parseProviders(bytes.NewReader(data))
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", name, err)
+	}
+
+	mergeProviders(providers)
+	return len(providers), nil
+}
+
+// parseProviders decodes a providers.json registry
+func parseProviders(r io.Reader) ([]OEmbedProvider, error) {
+	var entries []registryProvider
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("invalid providers JSON: %w", err)
+	}
+	return convertProviders(entries), nil
+}
+
+// parseProvidersYAML decodes providers written in YAML
+func parseProvidersYAML(data []byte) ([]OEmbedProvider, error) {
+	var entries []registryProvider
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid providers YAML: %w", err)
+	}
+	return convertProviders(entries), nil
+}
+
+// convertProviders turns registry entries into providers, skipping
+// endpoints without schemes and providers left without endpoints
+func convertProviders(entries []registryProvider) []OEmbedProvider {
+	providers := make([]OEmbedProvider, 0, len(entries))
+	for _, entry := range entries {
+		provider := OEmbedProvider{Name: entry.Name, URL: entry.URL}
+		for _, e := range entry.Endpoints {
+			if len(e.Schemes) == 0 || e.URL == "" {
+				continue
+			}
+			provider.Endpoints = append(provider.Endpoints, OEmbedEndpoint{
+				Schemes:   e.Schemes,
+				URL:       strings.ReplaceAll(e.URL, "{format}", "json"),
+				Discovery: e.Discovery,
+			})
+		}
+		if provider.Name != "" && len(provider.Endpoints) > 0 {
+			providers = append(providers, provider)
+		}
+	}
+	return providers
+}
+
+// mergeProviders swaps in a registry where providers replace those of the
+// same name and are added otherwise
+func mergeProviders(providers []OEmbedProvider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+
+	merged := make([]OEmbedProvider, len(knownProviders), len(knownProviders)+len(providers))
+	copy(merged, knownProviders)
+
+	index := make(map[string]int, len(merged))
+	for i, p := range merged {
+		index[p.Name] = i
+	}
+	for _, p := range providers {
+		if i, ok := index[p.Name]; ok {
+			merged[i] = p
+			continue
+		}
+		index[p.Name] = len(merged)
+		merged = append(merged, p)
+	}
+
+	knownProviders = merged
+}
@@ -0,0 +1,249 @@
+package urlmeta
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+const testRegistryJSON = `[
+	{
+		"provider_name": "YouTube",
+		"provider_url": "https://www.youtube.com/",
+		"endpoints": [{
+			"schemes": ["https://*.youtube.com/watch*"],
+			"url": "https://www.youtube.com/oembed",
+			"discovery": true
+		}]
+	},
+	{
+		"provider_name": "Example Video",
+		"provider_url": "https://video.example.com",
+		"endpoints": [{
+			"schemes": ["https://video.example.com/v/*"],
+			"url": "https://video.example.com/oembed.{format}",
+			"formats": ["json", "xml"]
+		}]
+	},
+	{
+		"provider_name": "Discovery Only",
+		"provider_url": "https://discover.example.com",
+		"endpoints": [{"url": "https://discover.example.com/oembed", "discovery": true}]
+	}
+]`
+
+func TestLoadProvidersFromJSON(t *testing.T) {
+	restoreProviders(t)
+	initialCount := ProviderCount()
+
+	n, err := LoadProvidersFromJSON(strings.NewReader(testRegistryJSON))
+	if err != nil {
+		t.Fatalf("LoadProvidersFromJSON failed: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Expected 2 providers loaded, got %d", n)
+	}
+	if ProviderCount() != initialCount+1 {
+		t.Errorf("Expected one provider added, got %d -> %d", initialCount, ProviderCount())
+	}
+
+	youtube := GetProviderByName("YouTube")
+	if youtube == nil || len(youtube.Endpoints) != 1 || youtube.Endpoints[0].Schemes[0] != "https://*.youtube.com/watch*" {
+		t.Errorf("Expected YouTube to be replaced, got %+v", youtube)
+	}
+
+	video := GetProviderByName("Example Video")
+	if video == nil || video.Endpoints[0].URL != "https://video.example.com/oembed.json" {
+		t.Errorf("Expected {format} replaced with json, got %+v", video)
+	}
+	if !IsOEmbedSupported("https://video.example.com/v/123") {
+		t.Error("Expected loaded schemes to be matched")
+	}
+	if GetProviderByName("Discovery Only") != nil {
+		t.Error("Expected a provider without schemes to be skipped")
+	}
+}
+
+func TestLoadProvidersFromJSONInvalid(t *testing.T) {
+	restoreProviders(t)
+	initialCount := ProviderCount()
+
+	if _, err := LoadProvidersFromJSON(strings.NewReader(`{"not": "a list"}`)); err == nil {
+		t.Error("Expected an error for invalid JSON")
+	}
+	if ProviderCount() != initialCount {
+		t.Error("Expected the registry unchanged after an error")
+	}
+}
+
+func TestLoadProvidersFromURL(t *testing.T) {
+	restoreProviders(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/providers.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(testRegistryJSON))
+	}))
+	defer server.Close()
+
+	n, err := LoadProvidersFromURL(context.Background(), server.URL+"/providers.json")
+	if err != nil || n != 2 {
+		t.Fatalf("Expected 2 providers, got %d (%v)", n, err)
+	}
+	if GetProviderByName("Example Video") == nil {
+		t.Error("Expected the downloaded provider to be registered")
+	}
+
+	_, err = LoadProvidersFromURL(context.Background(), server.URL+"/missing.json")
+	if !errors.Is(err, &ErrHTTPStatus{Code: http.StatusNotFound}) {
+		t.Errorf("Expected a 404 status error, got %v", err)
+	}
+}
+
+func TestProviderAutoRefresh(t *testing.T) {
+	restoreProviders(t)
+
+	var requests, notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(testRegistryJSON))
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithProviderAutoRefresh(10*time.Millisecond),
+		WithProviderRegistryURL(server.URL),
+	)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for notModified.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if notModified.Load() < 2 {
+		t.Fatalf("Expected conditional requests with the ETag, got %d of %d", notModified.Load(), requests.Load())
+	}
+	if client.findOEmbedEndpoint("https://video.example.com/v/1") == "" {
+		t.Error("Expected the refreshed registry to be loaded")
+	}
+	if GetProviderByName("Example Video") != nil {
+		t.Error("Expected the refreshed registry to stay on the client")
+	}
+	if findOEmbedEndpoint("https://video.example.com/v/1") != "" {
+		t.Error("Expected other clients not to see the refreshed registry")
+	}
+	for _, p := range client.refreshedProviders() {
+		if p.Name == "YouTube" {
+			t.Error("Expected built-in providers not to be replaced")
+		}
+	}
+
+	// Nothing runs after Close
+	stopped := requests.Load()
+	time.Sleep(30 * time.Millisecond)
+	if requests.Load() != stopped {
+		t.Error("Expected refreshing to stop after Close")
+	}
+	client.Close()
+}
+
+func TestProviderAutoRefreshFailure(t *testing.T) {
+	restoreProviders(t)
+	initialCount := ProviderCount()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithProviderAutoRefresh(time.Hour), WithProviderRegistryURL(server.URL))
+	time.Sleep(20 * time.Millisecond)
+	client.Close()
+
+	if ProviderCount() != initialCount {
+		t.Error("Expected the current providers kept after a failed refresh")
+	}
+}
+
+const testProvidersYAML = `
+- provider_name: Internal Wiki
+  provider_url: https://wiki.corp.example
+  endpoints:
+    - schemes:
+        - https://wiki.corp.example/pages/*
+      url: https://wiki.corp.example/api/oembed
+`
+
+func TestLoadProvidersFromFile(t *testing.T) {
+	restoreProviders(t)
+
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "providers.json")
+	yamlPath := filepath.Join(dir, "providers.yaml")
+	if err := os.WriteFile(jsonPath, []byte(testRegistryJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(yamlPath, []byte(testProvidersYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := LoadProvidersFromFile(jsonPath); err != nil || n != 2 {
+		t.Fatalf("Expected 2 providers from JSON, got %d (%v)", n, err)
+	}
+	if n, err := LoadProvidersFromFile(yamlPath); err != nil || n != 1 {
+		t.Fatalf("Expected 1 provider from YAML, got %d (%v)", n, err)
+	}
+
+	if GetProviderByName("Example Video") == nil {
+		t.Error("Expected the JSON provider to be registered")
+	}
+	if !IsOEmbedSupported("https://wiki.corp.example/pages/onboarding") {
+		t.Error("Expected the YAML provider's schemes to be matched")
+	}
+
+	if _, err := LoadProvidersFromFile(filepath.Join(dir, "missing.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected a not-exist error, got %v", err)
+	}
+}
+
+func TestLoadProvidersFromFS(t *testing.T) {
+	restoreProviders(t)
+	initialCount := ProviderCount()
+
+	fsys := fstest.MapFS{
+		"config/providers.yml": {Data: []byte(testProvidersYAML)},
+		"config/broken.yml":    {Data: []byte("provider_name: [unclosed")},
+	}
+
+	if n, err := LoadProvidersFromFS(fsys, "config/providers.yml"); err != nil || n != 1 {
+		t.Fatalf("Expected 1 provider, got %d (%v)", n, err)
+	}
+	if ProviderCount() != initialCount+1 {
+		t.Errorf("Expected one provider added, got %d -> %d", initialCount, ProviderCount())
+	}
+
+	_, err := LoadProvidersFromFS(fsys, "config/broken.yml")
+	if err == nil || !strings.Contains(err.Error(), "config/broken.yml") {
+		t.Errorf("Expected a YAML error naming the file, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package urlmeta
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Renderer loads a page in a (headless) browser and returns the DOM after
+// scripts have run, for single-page apps that emit their meta tags
+// client-side. finalURL is the page URL after redirects; empty means the
+// requested URL.
+type Renderer interface {
+	Render(ctx context.Context, url string) (html []byte, finalURL string, err error)
+}
+
+// RendererFunc adapts a function to the Renderer interface
+type RendererFunc func(ctx context.Context, url string) ([]byte, string, error)
+
+// Render implements Renderer
+func (f RendererFunc) Render(ctx context.Context, url string) ([]byte, string, error) {
+	return f(ctx, url)
+}
+
+// WithRenderer loads HTML pages through r instead of a plain HTTP fetch
+// (see WithRenderFallback to render only when needed). oEmbed lookups and
+// image requests still use HTTP. The renderer does its own fetching, so
+// only the allow/block host lists are checked up front; SSRF protection,
+// proxies and other transport options don't apply to it.
+func WithRenderer(r Renderer) Option {
+	return func(c *Client) {
+		c.renderer = r
+	}
+}
+
+// WithRenderFallback makes the renderer a fallback: pages are fetched
+// statically first and only rendered when the static HTML has no
+// meaningful metadata (no description, image or OpenGraph/Twitter title).
+// If rendering fails the static result is kept with a WarningRenderFailed
+// warning. Requires WithRenderer.
+func WithRenderFallback(fallback bool) Option {
+	return func(c *Client) {
+		c.renderFallback = fallback
+	}
+}
+
+// renderPage loads targetURL through the renderer, applying the same size
+// limit as HTTP fetches
+func (c *Client) renderPage(ctx context.Context, targetURL string) (*htmlPage, error) {
+	requested, err := url.Parse(targetURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidURL, err)
+	}
+	if err := c.checkHost(requested); err != nil {
+		return nil, err
+	}
+
+	body, finalURL, err := c.renderer.Render(ctx, targetURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render URL: %w", classifyFetchError(err))
+	}
+
+	pageURL := requested
+	if finalURL != "" {
+		if pageURL, err = url.Parse(finalURL); err != nil {
+			return nil, fmt.Errorf("%w: renderer returned %q: %w", ErrInvalidURL, finalURL, err)
+		}
+	}
+
+	if c.strict && c.maxBodySize > 0 && int64(len(body)) > c.maxBodySize {
+		return nil, fmt.Errorf("%w: rendered page exceeds %d bytes", ErrBodyTooLarge, c.maxBodySize)
+	}
+
+	return c.readPage(bytes.NewReader(body), pageURL)
+}
+
+// loadPage fetches targetURL, through the renderer when it replaces
+// static fetching
+func (c *Client) loadPage(ctx context.Context, targetURL string) (*htmlPage, error) {
+	if c.renderer != nil && !c.renderFallback {
+		return c.renderPage(ctx, targetURL)
+	}
+	return c.fetchHTML(ctx, targetURL)
+}
+
+// needsRendering reports whether statically extracted metadata is too thin
+// to be the real page, as with single-page app shells
+func (c *Client) needsRendering(m *Metadata) bool {
+	if c.renderer == nil || !c.renderFallback {
+		return false
+	}
+	return m.Description == "" && len(m.Images) == 0 && m.OGTitle == "" && m.TwitterTitle == ""
+}
@@ -0,0 +1,145 @@
+package urlmeta
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+const spaShell = `<html><head><title>App</title></head><body><div id="root"></div><script src="/app.js"></script></body></html>`
+
+const spaRendered = `<html><head><title>App</title>
+<meta property="og:title" content="Rendered Title">
+<meta property="og:description" content="Rendered description">
+<meta property="og:image" content="/cover.png">
+</head><body><div id="root"><h1>Rendered</h1></div></body></html>`
+
+// testRenderer returns fixed HTML and counts calls
+func testRenderer(calls *atomic.Int32, html, finalURL string, err error) Renderer {
+	return RendererFunc(func(ctx context.Context, url string) ([]byte, string, error) {
+		calls.Add(1)
+		if err != nil {
+			return nil, "", err
+		}
+		return []byte(html), finalURL, nil
+	})
+}
+
+func newStaticServer(page string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(page))
+	}))
+}
+
+func TestWithRenderer(t *testing.T) {
+	server := newStaticServer(spaShell)
+	defer server.Close()
+
+	var calls atomic.Int32
+	client := NewClient(WithRenderer(testRenderer(&calls, spaRendered, server.URL+"/home", nil)))
+
+	metadata, err := client.Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.Title != "Rendered Title" {
+		t.Errorf("Expected rendered title, got %q", metadata.Title)
+	}
+	if metadata.URL != server.URL+"/home" {
+		t.Errorf("Expected renderer final URL, got %q", metadata.URL)
+	}
+	if len(metadata.Images) != 1 || metadata.Images[0].URL != server.URL+"/cover.png" {
+		t.Errorf("Expected image resolved against final URL, got %v", metadata.Images)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 render, got %d", calls.Load())
+	}
+}
+
+func TestWithRenderFallback(t *testing.T) {
+	tests := []struct {
+		name        string
+		static      string
+		renderErr   error
+		title       string
+		renders     int32
+		wantWarning bool
+	}{
+		{
+			name:    "meaningful static HTML is not rendered",
+			static:  mockHTMLBasic,
+			title:   "Test Page Title",
+			renders: 0,
+		},
+		{
+			name:    "SPA shell is rendered",
+			static:  spaShell,
+			title:   "Rendered Title",
+			renders: 1,
+		},
+		{
+			name:        "render failure keeps static result",
+			static:      spaShell,
+			renderErr:   errors.New("browser crashed"),
+			title:       "App",
+			renders:     1,
+			wantWarning: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newStaticServer(tt.static)
+			defer server.Close()
+
+			var calls atomic.Int32
+			client := NewClient(
+				WithRenderer(testRenderer(&calls, spaRendered, "", tt.renderErr)),
+				WithRenderFallback(true),
+			)
+
+			metadata, err := client.Extract(server.URL)
+			if err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+
+			if metadata.Title != tt.title {
+				t.Errorf("Expected title %q, got %q", tt.title, metadata.Title)
+			}
+			if calls.Load() != tt.renders {
+				t.Errorf("Expected %d renders, got %d", tt.renders, calls.Load())
+			}
+			if metadata.HasWarning(WarningRenderFailed) != tt.wantWarning {
+				t.Errorf("Expected render warning %v, got %v", tt.wantWarning, metadata.Warnings)
+			}
+		})
+	}
+}
+
+func TestRendererLimits(t *testing.T) {
+	var calls atomic.Int32
+
+	blocked := NewClient(
+		WithRenderer(testRenderer(&calls, spaRendered, "", nil)),
+		WithBlockedHosts([]string{"example.com"}),
+	)
+	if _, err := blocked.Extract("https://example.com"); !errors.Is(err, ErrHostNotAllowed) {
+		t.Errorf("Expected ErrHostNotAllowed, got %v", err)
+	}
+	if calls.Load() != 0 {
+		t.Error("Expected blocked host not to be rendered")
+	}
+
+	small := NewClient(
+		WithRenderer(testRenderer(&calls, spaRendered, "", nil)),
+		WithMaxBodySize(32),
+	)
+	if _, err := small.Extract("https://example.com"); !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("Expected ErrBodyTooLarge, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package urlmeta
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// requestConfig holds per-call overrides for ExtractWithOptions
+type requestConfig struct {
+	timeout     time.Duration
+	userAgent   string
+	header      http.Header
+	strategy    *ExtractionStrategy
+	bypassCache bool
+
+	// host is the target URL's host, the only one sent the header
+	// overrides
+	host string
+}
+
+// RequestOption is a function that configures a single ExtractWithOptions
+// call
+type RequestOption func(*requestConfig)
+
+// WithRequestTimeout bounds the whole call, including oEmbed lookups and
+// image post-processing. The client timeout still applies to each request.
+func WithRequestTimeout(timeout time.Duration) RequestOption {
+	return func(r *requestConfig) {
+		r.timeout = timeout
+	}
+}
+
+// WithRequestUserAgent overrides the client User-Agent for this call
+func WithRequestUserAgent(ua string) RequestOption {
+	return func(r *requestConfig) {
+		r.userAgent = ua
+	}
+}
+
+// WithRequestHeader sets a header on the requests this call makes to the
+// target URL's host, replacing any value the client would send (e.g.
+// Accept-Language). Like WithBasicAuth credentials, the header is not sent
+// to other hosts, such as oEmbed endpoints, image servers or redirect
+// targets elsewhere.
+func WithRequestHeader(key, value string) RequestOption {
+	return func(r *requestConfig) {
+		if r.header == nil {
+			r.header = make(http.Header)
+		}
+		r.header.Set(key, value)
+	}
+}
+
+// WithRequestStrategy overrides the client extraction strategy for this call
+func WithRequestStrategy(strategy ExtractionStrategy) RequestOption {
+	return func(r *requestConfig) {
+		r.strategy = &strategy
+	}
+}
+
+// WithCacheBypass skips the cache lookup for this call. The fresh result
+// still replaces the cached entry.
+func WithCacheBypass() RequestOption {
+	return func(r *requestConfig) {
+		r.bypassCache = true
+	}
+}
+
+// requestConfigKey is the context key under which ExtractWithOptions
+// passes its overrides down to the request path
+type requestConfigKey struct{}
+
+// requestConfigFrom returns the per-call overrides carried by ctx, or nil
+func requestConfigFrom(ctx context.Context) *requestConfig {
+	cfg, _ := ctx.Value(requestConfigKey{}).(*requestConfig)
+	return cfg
+}
+
+// ExtractWithOptions is ExtractContext with per-call overrides of the
+// timeout, User-Agent, headers, strategy and cache, so a shared Client can
+// serve callers with different needs. Calls overriding the User-Agent,
+// headers or strategy bypass the client cache entirely.
+func (c *Client) ExtractWithOptions(ctx context.Context, targetURL string, opts ...RequestOption) (*Metadata, error) {
+	cfg := &requestConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if u, err := url.Parse(normalizeURL(targetURL)); err == nil {
+		cfg.host = canonicalHost(u)
+	}
+
+	if cfg.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.timeout)
+		defer cancel()
+	}
+
+	return c.ExtractContext(context.WithValue(ctx, requestConfigKey{}, cfg), targetURL)
+}
+
+// bypassCache reports whether ctx asks to skip the cache lookup
+func bypassCache(ctx context.Context) bool {
+	cfg := requestConfigFrom(ctx)
+	return cfg != nil && cfg.bypassCache
+}
+
+// skipCache reports whether ctx carries overrides changing what is
+// fetched. Such results are neither served from nor stored in the cache,
+// which is keyed by URL alone, so one caller's headers (e.g. a Cookie)
+// can't leak their result to another.
+func skipCache(ctx context.Context) bool {
+	cfg := requestConfigFrom(ctx)
+	return cfg != nil && (cfg.userAgent != "" || len(cfg.header) > 0 || cfg.strategy != nil)
+}
+
+// applyRequestConfig applies the per-call overrides carried by the request
+// context: the User-Agent to every request, headers only to the target host
+func applyRequestConfig(req *http.Request) {
+	cfg := requestConfigFrom(req.Context())
+	if cfg == nil {
+		return
+	}
+
+	if cfg.userAgent != "" {
+		req.Header.Set("User-Agent", cfg.userAgent)
+	}
+	if cfg.host == "" || canonicalHost(req.URL) != cfg.host {
+		return
+	}
+	for key, values := range cfg.header {
+		req.Header[key] = values
+	}
+}
+
+// canonicalHost returns the lower-cased host of u without a trailing dot
+func canonicalHost(u *url.URL) string {
+	return strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+}
@@ -0,0 +1,201 @@
+package urlmeta
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExtractWithOptionsHeaders(t *testing.T) {
+	var userAgent, language, custom atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent.Store(r.Header.Get("User-Agent"))
+		language.Store(r.Header.Get("Accept-Language"))
+		custom.Store(r.Header.Get("X-Tenant"))
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithUserAgent("DefaultBot/1.0"))
+
+	_, err := client.ExtractWithOptions(context.Background(), server.URL,
+		WithRequestUserAgent("TenantBot/2.0"),
+		WithRequestHeader("Accept-Language", "de-DE"),
+		WithRequestHeader("X-Tenant", "acme"),
+	)
+	if err != nil {
+		t.Fatalf("ExtractWithOptions failed: %v", err)
+	}
+
+	if got := userAgent.Load(); got != "TenantBot/2.0" {
+		t.Errorf("Expected overridden User-Agent, got %q", got)
+	}
+	if got := language.Load(); got != "de-DE" {
+		t.Errorf("Expected overridden Accept-Language, got %q", got)
+	}
+	if got := custom.Load(); got != "acme" {
+		t.Errorf("Expected X-Tenant header, got %q", got)
+	}
+
+	// Overrides don't leak into later calls
+	if _, err := client.Extract(server.URL); err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if got := userAgent.Load(); got != "DefaultBot/1.0" {
+		t.Errorf("Expected client User-Agent, got %q", got)
+	}
+	if got := custom.Load(); got != "" {
+		t.Errorf("Expected no X-Tenant header, got %q", got)
+	}
+}
+
+func TestExtractWithOptionsTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	_, err := client.ExtractWithOptions(context.Background(), server.URL, WithRequestTimeout(20*time.Millisecond))
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("Expected ErrTimeout, got %v", err)
+	}
+}
+
+func TestExtractWithOptionsStrategy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	// oEmbed-first on a URL without a provider falls back with a warning
+	client := NewClient(WithStrategy(StrategyOEmbedFirst))
+	metadata, err := client.Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if !metadata.HasWarning(WarningOEmbedFailed) {
+		t.Fatal("Expected oEmbed-first extraction to record WarningOEmbedFailed")
+	}
+
+	metadata, err = client.ExtractWithOptions(context.Background(), server.URL, WithRequestStrategy(StrategyHTMLOnly))
+	if err != nil {
+		t.Fatalf("ExtractWithOptions failed: %v", err)
+	}
+	if metadata.HasWarning(WarningOEmbedFailed) {
+		t.Error("Expected HTML-only extraction without an oEmbed attempt")
+	}
+}
+
+func TestExtractWithOptionsCacheBypass(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithCache(10, time.Minute))
+	ctx := context.Background()
+
+	for _, opts := range [][]RequestOption{nil, nil, {WithCacheBypass()}, nil} {
+		if _, err := client.ExtractWithOptions(ctx, server.URL, opts...); err != nil {
+			t.Fatalf("ExtractWithOptions failed: %v", err)
+		}
+	}
+
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected 2 requests (first call and bypass), got %d", got)
+	}
+}
+
+func TestExtractWithOptionsOverridesSkipCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.Header.Get("Cookie") == "session=tenant-a" {
+			w.Write([]byte(`<html><head><title>Private to tenant A</title></head></html>`))
+			return
+		}
+		w.Write([]byte(`<html><head><title>Public</title></head></html>`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithCache(10, time.Minute))
+	ctx := context.Background()
+
+	private, err := client.ExtractWithOptions(ctx, server.URL, WithRequestHeader("Cookie", "session=tenant-a"))
+	if err != nil {
+		t.Fatalf("ExtractWithOptions failed: %v", err)
+	}
+	if private.Title != "Private to tenant A" {
+		t.Fatalf("Expected the private page, got %q", private.Title)
+	}
+
+	// The private result is not served to a caller without the cookie
+	public, err := client.ExtractWithOptions(ctx, server.URL)
+	if err != nil {
+		t.Fatalf("ExtractWithOptions failed: %v", err)
+	}
+	if public.Title != "Public" {
+		t.Errorf("Expected the public page, got %q", public.Title)
+	}
+
+	// Nor does a cached public result answer a call with overrides
+	private, err = client.ExtractWithOptions(ctx, server.URL, WithRequestHeader("Cookie", "session=tenant-a"))
+	if err != nil {
+		t.Fatalf("ExtractWithOptions failed: %v", err)
+	}
+	if private.Title != "Private to tenant A" {
+		t.Errorf("Expected the private page again, got %q", private.Title)
+	}
+}
+
+func TestExtractWithOptionsHeadersStayOnTargetHost(t *testing.T) {
+	var imageAuth, imageAgent atomic.Value
+	images := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		imageAuth.Store(r.Header.Get("Authorization"))
+		imageAgent.Store(r.Header.Get("User-Agent"))
+		w.Header().Set("Content-Type", "image/png")
+	}))
+	defer images.Close()
+
+	var pageAuth atomic.Value
+	page := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pageAuth.Store(r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Page</title>
+			<meta property="og:image" content="` + images.URL + `/cover.png"></head></html>`))
+	}))
+	defer page.Close()
+
+	// The image server runs on the same loopback IP under another name
+	pageURL := strings.Replace(page.URL, "127.0.0.1", "localhost", 1)
+	client := NewClient(WithValidateImages(true))
+	_, err := client.ExtractWithOptions(context.Background(), pageURL,
+		WithRequestHeader("Authorization", "Bearer tenant-token"),
+		WithRequestUserAgent("TenantBot/2.0"),
+	)
+	if err != nil {
+		t.Fatalf("ExtractWithOptions failed: %v", err)
+	}
+
+	if got := pageAuth.Load(); got != "Bearer tenant-token" {
+		t.Errorf("Expected the header on the target host, got %q", got)
+	}
+	if got := imageAuth.Load(); got != "" {
+		t.Errorf("Expected no Authorization header on the image host, got %q", got)
+	}
+	if got := imageAgent.Load(); got != "TenantBot/2.0" {
+		t.Errorf("Expected the User-Agent override on every request, got %q", got)
+	}
+}
@@ -0,0 +1,180 @@
+package urlmeta
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// maxRetryAfter is the longest Retry-After the client will wait out; later
+// retries are left to the caller
+const maxRetryAfter = 30 * time.Second
+
+// Default backoff used by WithRetry when none is given
+const (
+	defaultRetryBaseDelay = 200 * time.Millisecond
+	defaultRetryMaxDelay  = 5 * time.Second
+)
+
+// BackoffFunc returns how long to wait before retry number attempt
+// (1 for the first retry)
+type BackoffFunc func(attempt int) time.Duration
+
+// ExponentialBackoff returns a BackoffFunc doubling base on every attempt
+// up to max, with jitter: each delay is picked at random between half and
+// all of the nominal value, so clients retrying together spread out
+func ExponentialBackoff(base, max time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		delay := base
+		for i := 1; i < attempt && delay < max; i++ {
+			delay *= 2
+		}
+		if delay > max {
+			delay = max
+		}
+		if delay <= 0 {
+			return 0
+		}
+		half := delay / 2
+		return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+	}
+}
+
+// WithRetry retries requests that fail transiently: timeouts, connection
+// resets, 429 Too Many Requests and 5xx responses other than 501 and 505.
+// Each request is tried at most maxAttempts times in total, waiting
+// backoff(n) before retry n (ExponentialBackoff(200ms, 5s) when nil). A
+// Retry-After header of up to 30s is honored when longer than the backoff.
+// Host policy errors, SSRF blocks and context cancellation are never
+// retried. Retries are disabled by default.
+func WithRetry(maxAttempts int, backoff BackoffFunc) Option {
+	return func(c *Client) {
+		if backoff == nil {
+			backoff = ExponentialBackoff(defaultRetryBaseDelay, defaultRetryMaxDelay)
+		}
+		c.retryAttempts = maxAttempts
+		c.retryBackoff = backoff
+	}
+}
+
+// doWithRetry sends req, retrying transient failures per WithRetry. Only
+// bodiless requests are sent by the package, so req can be reused as is.
+func (c *Client) doWithRetry(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(req)
+		if attempt >= c.retryAttempts || ctx.Err() != nil {
+			return resp, err
+		}
+
+		var delay time.Duration
+		var reason string
+		if err != nil {
+			if !isTransientError(err) {
+				return resp, err
+			}
+			delay = c.retryBackoff(attempt)
+			reason = err.Error()
+		} else {
+			if !isRetryableStatus(resp.StatusCode) {
+				return resp, nil
+			}
+			retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"))
+			if !ok {
+				return resp, nil
+			}
+			delay = c.retryBackoff(attempt)
+			if retryAfter > delay {
+				delay = retryAfter
+			}
+			reason = resp.Status
+			// Drain a little so the connection can be reused
+			_, _ = io.CopyN(io.Discard, resp.Body, 4096)
+			resp.Body.Close()
+		}
+
+		c.logInfo(ctx, "urlmeta: retrying request", "url", req.URL.String(), "attempt", attempt+1, "delay", delay, "reason", reason)
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isTransientError reports whether a transport error is worth retrying
+func isTransientError(err error) bool {
+	if errors.Is(err, ErrBlockedAddress) || errors.Is(err, ErrHostNotAllowed) || errors.Is(err, ErrTooManyRedirects) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// isRetryableStatus reports whether a response status is worth retrying
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusNotImplemented, http.StatusHTTPVersionNotSupported:
+		return false
+	}
+	return code >= 500 && code <= 599
+}
+
+// parseRetryAfter parses a Retry-After header (seconds or HTTP date). It
+// returns false when the server asks for a wait longer than maxRetryAfter.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, true
+	}
+
+	var wait time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(value); err == nil {
+		wait = time.Until(at)
+	} else {
+		return 0, true
+	}
+
+	if wait > maxRetryAfter {
+		return 0, false
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	return wait, true
+}
+
+// sleepContext waits for d or until ctx ends
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
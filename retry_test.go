@@ -0,0 +1,181 @@
+package urlmeta
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func noBackoff(int) time.Duration { return 0 }
+
+func TestWithRetry(t *testing.T) {
+	tests := []struct {
+		name       string
+		failures   int
+		fail       func(w http.ResponseWriter)
+		attempts   int
+		wantErr    bool
+		wantStatus int
+	}{
+		{
+			name:     "recovers from 503",
+			failures: 2,
+			fail:     func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) },
+			attempts: 3,
+		},
+		{
+			name:     "recovers from 429",
+			failures: 1,
+			fail: func(w http.ResponseWriter) {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			attempts: 2,
+		},
+		{
+			name:     "recovers from connection reset",
+			failures: 1,
+			fail: func(w http.ResponseWriter) {
+				conn, _, _ := w.(http.Hijacker).Hijack()
+				conn.Close()
+			},
+			attempts: 2,
+		},
+		{
+			name:       "gives up after max attempts",
+			failures:   5,
+			fail:       func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) },
+			attempts:   3,
+			wantErr:    true,
+			wantStatus: http.StatusBadGateway,
+		},
+		{
+			name:       "404 is not retried",
+			failures:   5,
+			fail:       func(w http.ResponseWriter) { w.WriteHeader(http.StatusNotFound) },
+			attempts:   1,
+			wantErr:    true,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:     "long Retry-After is not waited out",
+			failures: 5,
+			fail: func(w http.ResponseWriter) {
+				w.Header().Set("Retry-After", "3600")
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			attempts:   1,
+			wantErr:    true,
+			wantStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if int(requests.Add(1)) <= tt.failures {
+					tt.fail(w)
+					return
+				}
+				w.Header().Set("Content-Type", "text/html")
+				w.Write([]byte(mockHTMLBasic))
+			}))
+			defer server.Close()
+
+			client := NewClient(WithRetry(3, noBackoff), WithAutoOEmbed(false))
+			metadata, err := client.Extract(server.URL)
+
+			if got := int(requests.Load()); got != tt.attempts {
+				t.Errorf("Expected %d attempts, got %d", tt.attempts, got)
+			}
+
+			if tt.wantErr {
+				if !errors.Is(err, &ErrHTTPStatus{Code: tt.wantStatus}) {
+					t.Errorf("Expected HTTP %d error, got %v", tt.wantStatus, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+			if metadata.Title == "" {
+				t.Error("Expected metadata from the successful attempt")
+			}
+		})
+	}
+}
+
+func TestRetryDisabledByDefault(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithAutoOEmbed(false))
+	if _, err := client.Extract(server.URL); err == nil {
+		t.Error("Expected error for 503 response")
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("Expected 1 attempt, got %d", got)
+	}
+}
+
+func TestRetryHostPolicyNotRetried(t *testing.T) {
+	client := NewClient(WithRetry(3, noBackoff), WithBlockedHosts([]string{"example.com"}))
+	if _, err := client.Extract("https://example.com"); !errors.Is(err, ErrHostNotAllowed) {
+		t.Errorf("Expected ErrHostNotAllowed, got %v", err)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(100*time.Millisecond, time.Second)
+
+	tests := []struct {
+		attempt int
+		nominal time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{10, time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			d := backoff(tt.attempt)
+			if d < tt.nominal/2 || d > tt.nominal {
+				t.Errorf("attempt %d: expected delay in [%v, %v], got %v", tt.attempt, tt.nominal/2, tt.nominal, d)
+				break
+			}
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		wait  time.Duration
+		ok    bool
+	}{
+		{"", 0, true},
+		{"5", 5 * time.Second, true},
+		{"3600", 0, false},
+		{"garbage", 0, true},
+		{time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), 0, true},
+	}
+
+	for _, tt := range tests {
+		wait, ok := parseRetryAfter(tt.value)
+		if wait != tt.wait || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; expected %v, %v", tt.value, wait, ok, tt.wait, tt.ok)
+		}
+	}
+}
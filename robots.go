@@ -0,0 +1,147 @@
+package urlmeta
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// RobotsDirectives are the indexing and preview directives a page declares
+// in <meta name="robots"> and the X-Robots-Tag header
+type RobotsDirectives struct {
+	NoIndex      bool `json:"noindex,omitempty"`
+	NoFollow     bool `json:"nofollow,omitempty"`
+	NoSnippet    bool `json:"nosnippet,omitempty"`
+	NoImageIndex bool `json:"noimageindex,omitempty"`
+	// MaxSnippet is the longest text snippet allowed, in characters
+	// (0 when unrestricted; max-snippet:0 sets NoSnippet instead)
+	MaxSnippet int `json:"max_snippet,omitempty"`
+	// MaxImagePreview is "none", "standard" or "large" when declared
+	MaxImagePreview string `json:"max_image_preview,omitempty"`
+}
+
+// WithRespectRobotsMeta makes the client honor robots directives when
+// building previews. Directives are always reported in Metadata.Robots;
+// with this option on:
+//   - noindex (or none) strips everything but the URL and provider fields
+//   - nosnippet removes the description and extracted content
+//   - max-snippet:N truncates the description to N characters
+//   - noimageindex and max-image-preview:none remove images
+//
+// Directives scoped to a specific crawler (e.g. "googlebot: noindex") are
+// ignored. A WarningRobotsRestricted warning records any change made.
+func WithRespectRobotsMeta(respect bool) Option {
+	return func(c *Client) {
+		c.respectRobots = respect
+	}
+}
+
+// robots returns metadata.Robots, allocating it on first use
+func (m *Metadata) robots() *RobotsDirectives {
+	if m.Robots == nil {
+		m.Robots = &RobotsDirectives{}
+	}
+	return m.Robots
+}
+
+// parseRobotsDirectives applies a comma-separated directive list, as found
+// in a robots meta tag or one X-Robots-Tag header value
+func parseRobotsDirectives(value string, metadata *Metadata) {
+	for _, directive := range strings.Split(value, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(directive), ":")
+		name = strings.ToLower(strings.TrimSpace(name))
+		arg = strings.ToLower(strings.TrimSpace(arg))
+
+		switch name {
+		case "noindex":
+			metadata.robots().NoIndex = true
+		case "nofollow":
+			metadata.robots().NoFollow = true
+		case "none":
+			metadata.robots().NoIndex = true
+			metadata.robots().NoFollow = true
+		case "nosnippet":
+			metadata.robots().NoSnippet = true
+		case "noimageindex":
+			metadata.robots().NoImageIndex = true
+		case "max-snippet":
+			if n, err := strconv.Atoi(arg); err == nil {
+				switch {
+				case n == 0:
+					metadata.robots().NoSnippet = true
+				case n > 0:
+					metadata.robots().MaxSnippet = n
+				}
+			}
+		case "max-image-preview":
+			if arg == "none" || arg == "standard" || arg == "large" {
+				metadata.robots().MaxImagePreview = arg
+			}
+		}
+	}
+}
+
+// parseRobotsHeader applies X-Robots-Tag values that aren't scoped to a
+// named crawler
+func parseRobotsHeader(header http.Header, metadata *Metadata) {
+	for _, value := range header.Values("X-Robots-Tag") {
+		if scope, rest, ok := strings.Cut(value, ":"); ok && isCrawlerScope(scope) {
+			if strings.TrimSpace(scope) != "*" {
+				continue
+			}
+			value = rest
+		}
+		parseRobotsDirectives(value, metadata)
+	}
+}
+
+// isCrawlerScope reports whether the text before a colon in an X-Robots-Tag
+// value names a crawler rather than a directive such as max-snippet
+func isCrawlerScope(scope string) bool {
+	switch strings.ToLower(strings.TrimSpace(scope)) {
+	case "max-snippet", "max-image-preview", "max-video-preview", "unavailable_after":
+		return false
+	}
+	return !strings.Contains(scope, ",")
+}
+
+// applyRobots limits metadata according to its robots directives
+func applyRobots(metadata *Metadata) {
+	r := metadata.Robots
+	if r == nil {
+		return
+	}
+
+	if r.NoIndex {
+		*metadata = Metadata{
+			URL:             metadata.URL,
+			ProviderName:    metadata.ProviderName,
+			ProviderURL:     metadata.ProviderURL,
+			ProviderDisplay: metadata.ProviderDisplay,
+			Images:          []Image{},
+			Videos:          []Video{},
+			Keywords:        []string{},
+			Robots:          r,
+			Warnings:        metadata.Warnings,
+		}
+		metadata.addWarning(WarningRobotsRestricted, "page is marked noindex; preview data was removed")
+		return
+	}
+
+	if r.NoSnippet {
+		if metadata.Description != "" || metadata.Content != nil {
+			metadata.Description = ""
+			metadata.Content = nil
+			metadata.addWarning(WarningRobotsRestricted, "page is marked nosnippet; description was removed")
+		}
+	} else if r.MaxSnippet > 0 && utf8.RuneCountInString(metadata.Description) > r.MaxSnippet {
+		metadata.Description = truncateAtWord(metadata.Description, r.MaxSnippet)
+		metadata.addWarning(WarningRobotsRestricted, "description truncated to max-snippet:%d", r.MaxSnippet)
+	}
+
+	if (r.NoImageIndex || r.MaxImagePreview == "none") && len(metadata.Images) > 0 {
+		metadata.Images = []Image{}
+		metadata.addWarning(WarningRobotsRestricted, "page disallows image previews; images were removed")
+	}
+}
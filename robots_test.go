@@ -0,0 +1,169 @@
+package urlmeta
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const robotsTestPage = `<html><head>
+<title>Robots Test</title>
+%s
+<meta name="description" content="A fairly long description of the page that goes on for a while">
+<meta property="og:image" content="https://example.com/image.jpg">
+</head><body><p>Body text</p></body></html>`
+
+func TestParseRobotsDirectives(t *testing.T) {
+	tests := []struct {
+		name     string
+		meta     string
+		header   []string
+		expected *RobotsDirectives
+	}{
+		{
+			name:     "no directives",
+			expected: nil,
+		},
+		{
+			name:     "meta noindex",
+			meta:     `<meta name="robots" content="noindex, nofollow">`,
+			expected: &RobotsDirectives{NoIndex: true, NoFollow: true},
+		},
+		{
+			name:     "meta none",
+			meta:     `<meta name="ROBOTS" content="NONE">`,
+			expected: &RobotsDirectives{NoIndex: true, NoFollow: true},
+		},
+		{
+			name:     "preview limits",
+			meta:     `<meta name="robots" content="max-snippet:20, max-image-preview:large, noimageindex">`,
+			expected: &RobotsDirectives{NoImageIndex: true, MaxSnippet: 20, MaxImagePreview: "large"},
+		},
+		{
+			name:     "max-snippet zero is nosnippet",
+			meta:     `<meta name="robots" content="max-snippet:0">`,
+			expected: &RobotsDirectives{NoSnippet: true},
+		},
+		{
+			name:     "unlimited max-snippet",
+			meta:     `<meta name="robots" content="max-snippet:-1">`,
+			expected: nil,
+		},
+		{
+			name:     "header",
+			header:   []string{"nosnippet", "max-image-preview:none"},
+			expected: &RobotsDirectives{NoSnippet: true, MaxImagePreview: "none"},
+		},
+		{
+			name:     "header for other crawler ignored",
+			header:   []string{"googlebot: noindex", "*: nofollow"},
+			expected: &RobotsDirectives{NoFollow: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for _, v := range tt.header {
+					w.Header().Add("X-Robots-Tag", v)
+				}
+				w.Header().Set("Content-Type", "text/html")
+				w.Write([]byte(robotsPage(tt.meta)))
+			}))
+			defer server.Close()
+
+			metadata, err := Extract(server.URL)
+			if err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+
+			if !reflect.DeepEqual(metadata.Robots, tt.expected) {
+				t.Errorf("Expected robots %+v, got %+v", tt.expected, metadata.Robots)
+			}
+			// Directives are reported but not applied by default
+			if metadata.Description == "" || len(metadata.Images) != 1 {
+				t.Error("Expected preview data to be kept without WithRespectRobotsMeta")
+			}
+		})
+	}
+}
+
+func TestWithRespectRobotsMeta(t *testing.T) {
+	tests := []struct {
+		name        string
+		meta        string
+		title       string
+		description string
+		images      int
+	}{
+		{
+			name:        "no directives",
+			title:       "Robots Test",
+			description: "A fairly long description of the page that goes on for a while",
+			images:      1,
+		},
+		{
+			name:   "noindex",
+			meta:   `<meta name="robots" content="noindex">`,
+			images: 0,
+		},
+		{
+			name:   "nosnippet",
+			meta:   `<meta name="robots" content="nosnippet">`,
+			title:  "Robots Test",
+			images: 1,
+		},
+		{
+			name:        "max-snippet",
+			meta:        `<meta name="robots" content="max-snippet:20">`,
+			title:       "Robots Test",
+			description: "A fairly long…",
+			images:      1,
+		},
+		{
+			name:        "max-image-preview none",
+			meta:        `<meta name="robots" content="max-image-preview:none">`,
+			title:       "Robots Test",
+			description: "A fairly long description of the page that goes on for a while",
+			images:      0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+				w.Write([]byte(robotsPage(tt.meta)))
+			}))
+			defer server.Close()
+
+			client := NewClient(WithRespectRobotsMeta(true))
+			metadata, err := client.Extract(server.URL)
+			if err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+
+			if metadata.Title != tt.title {
+				t.Errorf("Expected title %q, got %q", tt.title, metadata.Title)
+			}
+			if metadata.Description != tt.description {
+				t.Errorf("Expected description %q, got %q", tt.description, metadata.Description)
+			}
+			if len(metadata.Images) != tt.images {
+				t.Errorf("Expected %d images, got %d", tt.images, len(metadata.Images))
+			}
+			if metadata.URL == "" || metadata.ProviderName == "" {
+				t.Error("Expected URL and provider to be kept")
+			}
+			if restricted := tt.meta != ""; metadata.HasWarning(WarningRobotsRestricted) != restricted {
+				t.Errorf("Expected robots warning %v, got %v", restricted, metadata.Warnings)
+			}
+		})
+	}
+}
+
+func robotsPage(meta string) string {
+	return fmt.Sprintf(robotsTestPage, meta)
+}
@@ -0,0 +1,322 @@
+package urlmeta
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Dialer settings matching http.DefaultTransport
+const (
+	defaultDialTimeout = 30 * time.Second
+	defaultKeepAlive   = 30 * time.Second
+)
+
+// blockedNetworks lists ranges that are never reachable with SSRF protection
+// on, in addition to the loopback/private/link-local checks on net.IP
+var blockedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",     // "this" network
+	"100.64.0.0/10", // carrier-grade NAT
+	"192.0.0.0/24",  // IETF protocol assignments
+	"198.18.0.0/15", // benchmarking
+	"240.0.0.0/4",   // reserved
+	"64:ff9b::/96",  // NAT64, may embed internal IPv4
+)
+
+// WithSSRFProtection refuses connections to loopback, RFC1918, link-local
+// (including the 169.254.169.254 metadata service) and other internal
+// addresses. The check runs on the resolved IP at dial time, so it also
+// covers every redirect hop and oEmbed endpoint fetch and is not fooled by
+// DNS names pointing at internal hosts. A custom DialContext (from
+// WithTransport or WithHTTPClient) is kept and only given checked IPs.
+//
+// Requests sent through a proxy (WithProxy, or HTTP_PROXY and HTTPS_PROXY
+// from the environment), and all requests of a client whose transport is
+// not an *http.Transport, connect somewhere this package can't check: the
+// target host is resolved and checked before the request is sent, which
+// is only best-effort protection as DNS rebinding can get past it. The
+// proxies themselves may be internal.
+func WithSSRFProtection(enabled bool) Option {
+	return func(c *Client) {
+		c.ssrfProtection = enabled
+	}
+}
+
+// WithAllowedHosts restricts fetching to the given hosts. Patterns are
+// matched case-insensitively against the hostname; a leading "*." matches
+// any subdomain (e.g. "*.example.com" matches "www.example.com"). The list
+// is enforced for the initial URL, every redirect hop and oEmbed endpoints.
+func WithAllowedHosts(hosts []string) Option {
+	return func(c *Client) {
+		c.allowedHosts = normalizeHostPatterns(hosts)
+	}
+}
+
+// WithBlockedHosts refuses to fetch from the given hosts, using the same
+// pattern syntax as WithAllowedHosts. Blocked hosts take precedence over
+// allowed ones.
+func WithBlockedHosts(hosts []string) Option {
+	return func(c *Client) {
+		c.blockedHosts = normalizeHostPatterns(hosts)
+	}
+}
+
+// checkHost applies the allow/block lists to u
+func (c *Client) checkHost(u *url.URL) error {
+	if len(c.allowedHosts) == 0 && len(c.blockedHosts) == 0 {
+		return nil
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+
+	if matchHostPatterns(host, c.blockedHosts) {
+		return fmt.Errorf("%w: %s is blocked", ErrHostNotAllowed, host)
+	}
+
+	if len(c.allowedHosts) > 0 && !matchHostPatterns(host, c.allowedHosts) {
+		return fmt.Errorf("%w: %s is not in the allowlist", ErrHostNotAllowed, host)
+	}
+
+	return nil
+}
+
+// matchHostPatterns reports whether host matches any of patterns
+func matchHostPatterns(host string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "*.") {
+			if strings.HasSuffix(host, pattern[1:]) {
+				return true
+			}
+			continue
+		}
+		if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeHostPatterns lowercases patterns and drops empty entries
+func normalizeHostPatterns(hosts []string) []string {
+	patterns := make([]string, 0, len(hosts))
+	for _, h := range hosts {
+		h = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(h)), ".")
+		if h != "" {
+			patterns = append(patterns, h)
+		}
+	}
+	return patterns
+}
+
+// isBlockedIP reports whether ip belongs to a range SSRF protection rejects
+func isBlockedIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// guardTransport returns a RoundTripper enforcing SSRF protection on top of rt
+func guardTransport(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	transport, ok := rt.(*http.Transport)
+	if !ok {
+		// Unknown transport: we can't hook its dialer, so resolve and
+		// check the host before handing the request over
+		return &ssrfRoundTripper{next: rt}
+	}
+
+	transport = transport.Clone()
+	proxies := &proxyAddrs{}
+	if transport.Proxy != nil {
+		// The connection goes to the proxy (WithProxy, or HTTP_PROXY and
+		// friends through http.ProxyFromEnvironment), so the target host
+		// is checked before the request is handed to it
+		transport.Proxy = guardProxy(transport.Proxy, proxies)
+	}
+
+	dial := transport.DialContext
+	if dial == nil {
+		dialer := &net.Dialer{
+			Timeout:   defaultDialTimeout,
+			KeepAlive: defaultKeepAlive,
+		}
+		dial = dialer.DialContext
+	}
+	// A caller's dialer (instrumentation, custom resolvers, ...) is kept
+	// but only handed addresses that passed the check
+	transport.DialContext = guardDial(dial, proxies)
+	if transport.DialTLSContext != nil {
+		transport.DialTLSContext = guardDial(transport.DialTLSContext, proxies)
+	}
+
+	return transport
+}
+
+// proxyFunc is the signature of http.Transport.Proxy
+type proxyFunc func(*http.Request) (*url.URL, error)
+
+// proxyAddrs records the host:port of the proxies requests were handed to.
+// Proxies are configured by the operator and may well be internal, so the
+// dialer connects to them without the address check.
+type proxyAddrs struct {
+	addrs sync.Map
+}
+
+// add records addr as a proxy
+func (p *proxyAddrs) add(addr string) {
+	p.addrs.Store(addr, struct{}{})
+}
+
+// contains reports whether addr is a recorded proxy
+func (p *proxyAddrs) contains(addr string) bool {
+	_, ok := p.addrs.Load(addr)
+	return ok
+}
+
+// guardProxy wraps proxy so that requests it sends through a proxy have
+// their target host resolved and checked first. As with ssrfRoundTripper
+// this is best-effort, since the proxy resolves the host again.
+func guardProxy(proxy proxyFunc, proxies *proxyAddrs) proxyFunc {
+	return func(req *http.Request) (*url.URL, error) {
+		proxyURL, err := proxy(req)
+		if err != nil || proxyURL == nil {
+			return proxyURL, err
+		}
+		if _, err := resolveChecked(req.Context(), req.URL.Hostname()); err != nil {
+			return nil, err
+		}
+		proxies.add(proxyAddr(proxyURL))
+		return proxyURL, nil
+	}
+}
+
+// proxyAddr returns the host:port the transport dials for proxyURL
+func proxyAddr(proxyURL *url.URL) string {
+	port := proxyURL.Port()
+	if port == "" {
+		switch strings.ToLower(proxyURL.Scheme) {
+		case "https":
+			port = "443"
+		case "socks5", "socks5h":
+			port = "1080"
+		default:
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(proxyURL.Hostname(), port)
+}
+
+// dialFunc is the signature of http.Transport.DialContext
+type dialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// guardDial wraps dial so that it resolves the host, rejects blocked
+// addresses and then dials the checked IPs themselves, so a second DNS
+// answer can't point the connection elsewhere. Proxies in proxies are
+// dialed unchecked.
+func guardDial(dial dialFunc, proxies *proxyAddrs) dialFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		if proxies.contains(address) {
+			return dial(ctx, network, address)
+		}
+
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+
+		ips, err := resolveChecked(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		var dialErr error
+		for _, ip := range ips {
+			conn, err := dial(ctx, network, net.JoinHostPort(ip.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			dialErr = err
+		}
+		return nil, dialErr
+	}
+}
+
+// resolveChecked resolves host and returns its addresses, failing with
+// ErrBlockedAddress if any of them is blocked
+func resolveChecked(ctx context.Context, host string) ([]net.IP, error) {
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = []net.IP{ip}
+	} else {
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+	}
+
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("%w: no addresses for %s", ErrBlockedAddress, host)
+	}
+	for _, ip := range ips {
+		if isBlockedIP(ip) {
+			return nil, fmt.Errorf("%w: %s", ErrBlockedAddress, ip)
+		}
+	}
+	return ips, nil
+}
+
+// ssrfRoundTripper checks the resolved request host before delegating.
+// The transport resolves the host again when it connects, so this is only
+// best-effort: a DNS answer changing in between (DNS rebinding) gets past
+// it. It is used for transports other than *http.Transport, whose dialers
+// can't be hooked.
+type ssrfRoundTripper struct {
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (s *ssrfRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if _, err := resolveChecked(req.Context(), req.URL.Hostname()); err != nil {
+		return nil, err
+	}
+
+	return s.next.RoundTrip(req)
+}
+
+// mustParseCIDRs parses CIDR literals, panicking on programmer error
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
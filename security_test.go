@@ -0,0 +1,173 @@
+package urlmeta
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestIsBlockedIP(t *testing.T) {
+	tests := []struct {
+		ip      string
+		blocked bool
+	}{
+		{"127.0.0.1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"100.64.0.1", true},
+		{"0.0.0.0", true},
+		{"::1", true},
+		{"fe80::1", true},
+		{"fd00::1", true},
+		{"::ffff:127.0.0.1", true},
+		{"8.8.8.8", false},
+		{"93.184.216.34", false},
+		{"2606:4700:4700::1111", false},
+	}
+
+	for _, tt := range tests {
+		if result := isBlockedIP(net.ParseIP(tt.ip)); result != tt.blocked {
+			t.Errorf("isBlockedIP(%s) = %v, expected %v", tt.ip, result, tt.blocked)
+		}
+	}
+}
+
+func TestSSRFProtectionBlocksLoopback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithSSRFProtection(true))
+	_, err := client.Extract(server.URL)
+	if err == nil {
+		t.Fatal("Expected loopback request to be blocked, got nil")
+	}
+
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("Expected ErrBlockedAddress, got: %v", err)
+	}
+}
+
+func TestSSRFProtectionDisabledByDefault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	if _, err := NewClient().Extract(server.URL); err != nil {
+		t.Errorf("Expected loopback to be reachable without protection, got: %v", err)
+	}
+}
+
+func TestSSRFRoundTripperWrapsCustomTransport(t *testing.T) {
+	custom := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		t.Error("Custom transport should not be reached for blocked host")
+		return nil, errors.New("unreachable")
+	})}
+
+	client := NewClient(WithHTTPClient(custom), WithSSRFProtection(true))
+	_, err := client.Extract("http://127.0.0.1/")
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("Expected ErrBlockedAddress, got: %v", err)
+	}
+}
+
+func TestMatchHostPatterns(t *testing.T) {
+	patterns := normalizeHostPatterns([]string{"Example.com", "*.internal.corp", " "})
+
+	tests := []struct {
+		host  string
+		match bool
+	}{
+		{"example.com", true},
+		{"www.example.com", false},
+		{"api.internal.corp", true},
+		{"a.b.internal.corp", true},
+		{"internal.corp", false},
+		{"evilinternal.corp", false},
+		{"other.org", false},
+	}
+
+	for _, tt := range tests {
+		if result := matchHostPatterns(tt.host, patterns); result != tt.match {
+			t.Errorf("matchHostPatterns(%s) = %v, expected %v", tt.host, result, tt.match)
+		}
+	}
+}
+
+func TestBlockedHosts(t *testing.T) {
+	client := NewClient(WithBlockedHosts([]string{"127.0.0.1"}))
+
+	_, err := client.Extract("http://127.0.0.1:1/")
+	if !errors.Is(err, ErrHostNotAllowed) {
+		t.Errorf("Expected ErrHostNotAllowed, got: %v", err)
+	}
+}
+
+func TestAllowedHostsEnforcedOnRedirect(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer target.Close()
+
+	// Same loopback IP, but reached through "localhost" so it has a different host
+	redirectURL := strings.Replace(target.URL, "127.0.0.1", "localhost", 1)
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, redirectURL, http.StatusFound)
+	}))
+	defer origin.Close()
+
+	client := NewClient(WithAllowedHosts([]string{"127.0.0.1"}))
+	_, err := client.Extract(origin.URL)
+	if !errors.Is(err, ErrHostNotAllowed) {
+		t.Errorf("Expected redirect to non-allowed host to fail with ErrHostNotAllowed, got: %v", err)
+	}
+
+	if _, err := client.Extract(target.URL); err != nil {
+		t.Errorf("Expected allowed host to succeed, got: %v", err)
+	}
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestSSRFKeepsCustomDialer(t *testing.T) {
+	var dialed []string
+	custom := &http.Transport{DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+		dialed = append(dialed, address)
+		return nil, errors.New("not connecting in tests")
+	}}
+
+	client := NewClient(WithTransport(custom), WithSSRFProtection(true))
+	transport, ok := baseTransport(t, client).(*http.Transport)
+	if !ok || transport.DialContext == nil {
+		t.Fatalf("Expected a guarded *http.Transport, got %T", baseTransport(t, client))
+	}
+
+	if _, err := client.Extract("http://127.0.0.1/"); !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("Expected ErrBlockedAddress, got: %v", err)
+	}
+	if len(dialed) != 0 {
+		t.Errorf("Expected the custom dialer not to see blocked addresses, got %v", dialed)
+	}
+
+	// Allowed addresses reach the custom dialer
+	transport.DialContext(context.Background(), "tcp", "93.184.216.34:80")
+	if len(dialed) != 1 || dialed[0] != "93.184.216.34:80" {
+		t.Errorf("Expected the custom dialer to be used for allowed addresses, got %v", dialed)
+	}
+}
@@ -0,0 +1,133 @@
+package urlmeta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// SiteExtractor builds metadata for a site from its API rather than its
+// HTML, for sites whose pages make poor previews (see WithSiteExtractors)
+type SiteExtractor interface {
+	// Name identifies the extractor in warnings and logs
+	Name() string
+
+	// Match reports whether the extractor handles u
+	Match(u *url.URL) bool
+
+	// Extract returns the metadata for u. Requests made with client go
+	// through the Client's own pipeline: host policy, SSRF protection,
+	// rate limits, retries, hooks and User-Agent.
+	Extract(ctx context.Context, client *http.Client, u *url.URL) (*Metadata, error)
+}
+
+// WithSiteExtractors sets the extractors tried, in order, before the
+// extraction strategy, e.g. WithSiteExtractors(RedditExtractor()). The
+// first one matching a URL builds its metadata; if it fails, the URL is
+// extracted as usual with a WarningSiteExtractorFailed. Image processing,
+// fallbacks and caching apply as for any result. No site extractors are
+// used by default.
+func WithSiteExtractors(extractors ...SiteExtractor) Option {
+	return func(c *Client) {
+		c.siteExtractors = extractors
+	}
+}
+
+// extractSite runs the first site extractor matching parsedURL and returns
+// its result and name. The name is empty when no extractor matches.
+func (c *Client) extractSite(ctx context.Context, targetURL string, parsedURL *url.URL) (*Metadata, string, error) {
+	for _, extractor := range c.siteExtractors {
+		if !extractor.Match(parsedURL) {
+			continue
+		}
+
+		c.logDebug(ctx, "urlmeta: site extractor selected", "url", targetURL, "extractor", extractor.Name())
+		metadata, err := extractor.Extract(ctx, c.pipelineClient(), parsedURL)
+		if err != nil {
+			c.logDebug(ctx, "urlmeta: site extractor failed", "url", targetURL, "extractor", extractor.Name(), "error", err)
+			return nil, extractor.Name(), err
+		}
+		completeSiteMetadata(metadata, targetURL, parsedURL)
+		return metadata, extractor.Name(), nil
+	}
+	return nil, "", nil
+}
+
+// completeSiteMetadata fills the fields every result carries when a site
+// extractor left them out
+func completeSiteMetadata(metadata *Metadata, targetURL string, parsedURL *url.URL) {
+	if metadata.URL == "" {
+		metadata.URL = targetURL
+	}
+	if metadata.ProviderURL == "" {
+		metadata.ProviderURL = fmt.Sprintf("%s://%s", parsedURL.Scheme, parsedURL.Host)
+	}
+	if metadata.ProviderDisplay == "" {
+		metadata.ProviderDisplay = parsedURL.Host
+	}
+	if metadata.ProviderName == "" {
+		metadata.ProviderName = parsedURL.Host
+	}
+	if metadata.Images == nil {
+		metadata.Images = []Image{}
+	}
+	if metadata.Videos == nil {
+		metadata.Videos = []Video{}
+	}
+	if metadata.Keywords == nil {
+		metadata.Keywords = []string{}
+	}
+}
+
+// pipelineClient returns an *http.Client sending requests through c.do
+func (c *Client) pipelineClient() *http.Client {
+	return &http.Client{Transport: &pipelineTransport{client: c}}
+}
+
+// pipelineTransport is an http.RoundTripper backed by a Client's request
+// pipeline. Redirects are followed by the pipeline itself.
+type pipelineTransport struct {
+	client *Client
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *pipelineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", t.client.userAgent)
+	}
+	return t.client.do(req)
+}
+
+// maxAPIResponseSize caps the JSON responses read by site extractors
+const maxAPIResponseSize = 5 * 1024 * 1024
+
+// getJSON fetches apiURL with client and decodes the JSON response into v
+func getJSON(ctx context.Context, client *http.Client, apiURL string, header http.Header, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &ErrHTTPStatus{Code: resp.StatusCode}
+	}
+
+	body := &maxBytesReader{r: resp.Body, limit: maxAPIResponseSize, remaining: maxAPIResponseSize}
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		return fmt.Errorf("invalid API response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package urlmeta
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// testExtractor is a SiteExtractor for paths starting with prefix
+type testExtractor struct {
+	prefix string
+	err    error
+}
+
+func (e *testExtractor) Name() string { return "test" }
+
+func (e *testExtractor) Match(u *url.URL) bool { return strings.HasPrefix(u.Path, e.prefix) }
+
+func (e *testExtractor) Extract(ctx context.Context, client *http.Client, u *url.URL) (*Metadata, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	var data struct {
+		Title string `json:"title"`
+		Agent string `json:"agent"`
+	}
+	if err := getJSON(ctx, client, "http://"+u.Host+"/api", nil, &data); err != nil {
+		return nil, err
+	}
+	return &Metadata{Title: data.Title, Description: data.Agent}, nil
+}
+
+func newSiteServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"title":"From the API","agent":"` + r.UserAgent() + `"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>From the page</title></head></html>`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSiteExtractor(t *testing.T) {
+	server := newSiteServer(t)
+	client := NewClient(
+		WithUserAgent("site-test/1.0"),
+		WithSiteExtractors(&testExtractor{prefix: "/posts/"}),
+	)
+
+	metadata, err := client.Extract(server.URL + "/posts/1")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if metadata.Title != "From the API" {
+		t.Errorf("Expected the extractor's result, got %q", metadata.Title)
+	}
+	if metadata.Description != "site-test/1.0" {
+		t.Errorf("Expected API requests to use the client's User-Agent, got %q", metadata.Description)
+	}
+	if metadata.URL != server.URL+"/posts/1" || metadata.Images == nil {
+		t.Errorf("Expected missing fields to be filled in, got %+v", metadata)
+	}
+
+	// Other URLs are extracted as usual
+	metadata, err = client.Extract(server.URL + "/about")
+	if err != nil || metadata.Title != "From the page" {
+		t.Errorf("Expected HTML extraction for unmatched URLs, got %+v (%v)", metadata, err)
+	}
+}
+
+func TestSiteExtractorFailure(t *testing.T) {
+	server := newSiteServer(t)
+	client := NewClient(WithSiteExtractors(&testExtractor{prefix: "/", err: errors.New("api down")}))
+
+	metadata, err := client.Extract(server.URL + "/posts/1")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if metadata.Title != "From the page" {
+		t.Errorf("Expected HTML extraction after the extractor failed, got %q", metadata.Title)
+	}
+	if !metadata.HasWarning(WarningSiteExtractorFailed) {
+		t.Errorf("Expected site_extractor_failed warning, got %v", metadata.Warnings)
+	}
+}
+
+func TestSiteExtractorHostPolicy(t *testing.T) {
+	server := newSiteServer(t)
+	client := NewClient(WithBlockedHosts([]string{"blocked.example.com"}))
+
+	var data map[string]any
+	err := getJSON(context.Background(), client.pipelineClient(), "http://blocked.example.com/api", nil, &data)
+	if !errors.Is(err, ErrHostNotAllowed) {
+		t.Errorf("Expected the host policy to apply to API requests, got %v", err)
+	}
+	if err := getJSON(context.Background(), client.pipelineClient(), server.URL+"/api", nil, &data); err != nil || data["title"] != "From the API" {
+		t.Errorf("Expected the API response, got %v (%v)", data, err)
+	}
+}
@@ -0,0 +1,154 @@
+package urlmeta
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// BestTitle returns the title to display: og:title, then twitter:title,
+// then the page title, then the first <h1>. Placeholder titles such as
+// "Untitled" or "Home" are passed over while a better one exists.
+// Whitespace is collapsed and leftover HTML entities (from double-escaped
+// markup) are decoded.
+func (m *Metadata) BestTitle() string {
+	return bestText(isGenericTitle, m.OGTitle, m.TwitterTitle, m.Title, m.Heading)
+}
+
+// BestDescription returns the description to display: og:description,
+// then twitter:description, then Description (the meta description, or
+// a body paragraph with WithDescriptionFallback). Text is cleaned as in
+// BestTitle.
+func (m *Metadata) BestDescription() string {
+	return bestText(nil, m.OGDescription, m.TwitterDescription, m.Description)
+}
+
+// titleSeparators split a title from the site name, most specific first
+var titleSeparators = []string{" :: ", " | ", " – ", " — ", " - ", " · ", " • ", " » ", " / ", "|"}
+
+// WithCleanTitle strips the site name from Title, OGTitle and TwitterTitle
+// when the page declares og:site_name, as CleanTitle does. Disabled by
+// default.
+func WithCleanTitle(clean bool) Option {
+	return func(c *Client) {
+		c.cleanTitle = clean
+	}
+}
+
+// CleanTitle removes siteName from either end of title when it is joined
+// by a separator such as " | ", " - ", " – ", " — ", " :: ", " · " or " » ",
+// e.g. "Release notes | Example Blog" becomes "Release notes" for site
+// "Example Blog". Site names are matched case-insensitively. The title is
+// returned unchanged when siteName is empty, doesn't match, or is all
+// there is.
+func CleanTitle(title, siteName string) string {
+	title = strings.TrimSpace(title)
+	site := strings.Join(strings.Fields(siteName), " ")
+	if site == "" {
+		return title
+	}
+
+	for _, sep := range titleSeparators {
+		if i := strings.LastIndex(title, sep); i > 0 && sameSiteName(title[i+len(sep):], site) {
+			if rest := strings.TrimSpace(title[:i]); rest != "" {
+				return rest
+			}
+		}
+		if i := strings.Index(title, sep); i > 0 && sameSiteName(title[:i], site) {
+			if rest := strings.TrimSpace(title[i+len(sep):]); rest != "" {
+				return rest
+			}
+		}
+	}
+	return title
+}
+
+// sameSiteName reports whether s is site, ignoring case and spacing
+func sameSiteName(s, site string) bool {
+	return strings.EqualFold(strings.Join(strings.Fields(s), " "), site)
+}
+
+// WithMaxDescriptionLength shortens Description, OGDescription and
+// TwitterDescription to at most n characters with TruncateDescription. A
+// value of zero or less leaves descriptions as written (default).
+func WithMaxDescriptionLength(n int) Option {
+	return func(c *Client) {
+		c.maxDescriptionLength = n
+	}
+}
+
+// TruncateDescription prepares s for display in at most max characters
+// (runes): leftover HTML tags are removed, entities decoded and whitespace
+// collapsed, then text over max is cut at the last word boundary and ends
+// with an ellipsis, which counts towards max. A max of zero or less only
+// cleans s.
+func TruncateDescription(s string, max int) string {
+	s = cleanText(stripTags(s))
+	if max <= 0 || utf8.RuneCountInString(s) <= max {
+		return s
+	}
+
+	// Keep room for the ellipsis; back off to a word boundary unless the
+	// cut already falls on one
+	runes := []rune(s)
+	cut := string(runes[:max-1])
+	if !unicode.IsSpace(runes[max-1]) {
+		if i := strings.LastIndex(cut, " "); i > 0 {
+			cut = cut[:i]
+		}
+	}
+	return strings.TrimRight(cut, " ,;:.-") + "…"
+}
+
+// stripTags returns the text of s without any markup in it
+func stripTags(s string) string {
+	if !strings.Contains(s, "<") {
+		return s
+	}
+
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return b.String()
+		case html.TextToken:
+			b.Write(z.Text())
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			// Block tags separate words, e.g. "one<br>two"
+			if name, _ := z.TagName(); blockElements[atom.Lookup(name)] {
+				b.WriteByte(' ')
+			}
+		}
+	}
+}
+
+// bestText returns the first candidate that cleans up to a non-empty text
+// not rejected by generic, or the first non-empty one when all are
+func bestText(generic func(string) bool, candidates ...string) string {
+	fallback := ""
+	for _, candidate := range candidates {
+		text := cleanText(candidate)
+		if text == "" {
+			continue
+		}
+		if generic == nil || !generic(text) {
+			return text
+		}
+		if fallback == "" {
+			fallback = text
+		}
+	}
+	return fallback
+}
+
+// cleanText decodes leftover HTML entities and collapses whitespace
+func cleanText(s string) string {
+	if strings.Contains(s, "&") {
+		s = html.UnescapeString(s)
+	}
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,184 @@
+package urlmeta
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestBestTitle(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata Metadata
+		want     string
+	}{
+		{"og wins", Metadata{OGTitle: "OG", TwitterTitle: "Twitter", Title: "Tag", Heading: "H1"}, "OG"},
+		{"twitter next", Metadata{TwitterTitle: "Twitter", Title: "Tag", Heading: "H1"}, "Twitter"},
+		{"title tag next", Metadata{Title: "Tag", Heading: "H1"}, "Tag"},
+		{"h1 last", Metadata{Heading: "H1"}, "H1"},
+		{"whitespace and entities", Metadata{OGTitle: "  Tom &amp; Jerry\n\t Show "}, "Tom & Jerry Show"},
+		{"blank og skipped", Metadata{OGTitle: "   ", Title: "Tag"}, "Tag"},
+		{"generic skipped", Metadata{Title: "Home", Heading: "Real Heading"}, "Real Heading"},
+		{"generic kept when alone", Metadata{Title: "Home"}, "Home"},
+		{"empty", Metadata{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.metadata.BestTitle(); got != tt.want {
+				t.Errorf("BestTitle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBestDescription(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata Metadata
+		want     string
+	}{
+		{"og wins", Metadata{OGDescription: "OG", TwitterDescription: "Twitter", Description: "Meta"}, "OG"},
+		{"twitter next", Metadata{TwitterDescription: "Twitter", Description: "Meta"}, "Twitter"},
+		{"description last", Metadata{Description: " Meta  text "}, "Meta text"},
+		{"entities", Metadata{Description: "Fish &amp;amp; chips"}, "Fish &amp; chips"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.metadata.BestDescription(); got != tt.want {
+				t.Errorf("BestDescription() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBestTitleFromPage(t *testing.T) {
+	page := `<html><head>
+		<meta name="description" content="Meta description">
+		<meta name="twitter:description" content="Twitter description">
+		<title>Home</title>
+	</head><body><h1>  Launch
+		Announcement </h1></body></html>`
+
+	metadata, err := ExtractFromHTML(strings.NewReader(page), "https://example.com/")
+	if err != nil {
+		t.Fatalf("ExtractFromHTML failed: %v", err)
+	}
+
+	if metadata.Heading != "Launch Announcement" {
+		t.Errorf("Expected Heading from the first <h1>, got %q", metadata.Heading)
+	}
+	if got := metadata.BestTitle(); got != "Launch Announcement" {
+		t.Errorf("Expected the h1 over a generic title, got %q", got)
+	}
+	if metadata.Description != "Meta description" {
+		t.Errorf("Expected Description to keep the first declared description, got %q", metadata.Description)
+	}
+	if got := metadata.BestDescription(); got != "Twitter description" {
+		t.Errorf("Expected twitter:description over the meta description, got %q", got)
+	}
+}
+
+func TestCleanTitle(t *testing.T) {
+	tests := []struct {
+		title    string
+		siteName string
+		want     string
+	}{
+		{"Release notes | Example Blog", "Example Blog", "Release notes"},
+		{"Release notes – Example Blog", "Example Blog", "Release notes"},
+		{"Release notes — Example Blog", "Example Blog", "Release notes"},
+		{"Release notes :: Example", "Example", "Release notes"},
+		{"Release notes - example blog", "Example  Blog", "Release notes"},
+		{"Release notes|Example", "Example", "Release notes"},
+		{"Example Blog | Release notes", "Example Blog", "Release notes"},
+		{"Spider-Man - Marvel", "Marvel", "Spider-Man"},
+		{"Coca-Cola - Drinks", "Cola", "Coca-Cola - Drinks"},
+		{"Tips | Tricks | Example", "Example", "Tips | Tricks"},
+		{"Release notes | Other Site", "Example", "Release notes | Other Site"},
+		{"Example Blog", "Example Blog", "Example Blog"},
+		{"  Release notes | Example  ", "", "Release notes | Example"},
+	}
+
+	for _, tt := range tests {
+		if got := CleanTitle(tt.title, tt.siteName); got != tt.want {
+			t.Errorf("CleanTitle(%q, %q) = %q, want %q", tt.title, tt.siteName, got, tt.want)
+		}
+	}
+}
+
+func TestWithCleanTitle(t *testing.T) {
+	page := `<html><head>
+		<title>Release notes | Example Blog</title>
+		<meta property="og:site_name" content="Example Blog">
+		<meta name="twitter:title" content="Release notes - Example Blog">
+	</head></html>`
+
+	metadata, err := NewClient(WithCleanTitle(true)).ExtractFromHTML(strings.NewReader(page), "https://example.com/")
+	if err != nil {
+		t.Fatalf("ExtractFromHTML failed: %v", err)
+	}
+	if metadata.Title != "Release notes" || metadata.TwitterTitle != "Release notes" {
+		t.Errorf("Expected site name stripped, got %q / %q", metadata.Title, metadata.TwitterTitle)
+	}
+
+	metadata, err = ExtractFromHTML(strings.NewReader(page), "https://example.com/")
+	if err != nil {
+		t.Fatalf("ExtractFromHTML failed: %v", err)
+	}
+	if metadata.Title != "Release notes - Example Blog" {
+		t.Errorf("Expected titles untouched by default, got %q", metadata.Title)
+	}
+}
+
+func TestTruncateDescription(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		max  int
+		want string
+	}{
+		{"short", "A short description.", 50, "A short description."},
+		{"word boundary", "The quick brown fox jumps over the lazy dog", 20, "The quick brown fox…"},
+		{"trailing punctuation", "Hello, world, and more words", 14, "Hello, world…"},
+		{"tags", "<p>Bold <b>new</b> release.</p><p>Second</p>", 0, "Bold new release. Second"},
+		{"inline tags keep words", "Wo<b>rd</b>s", 0, "Words"},
+		{"line break", "one<br>two", 0, "one two"},
+		{"entities", "Fish &amp; chips&nbsp;&mdash; daily", 0, "Fish & chips — daily"},
+		{"whitespace", "  spread \n\t out  ", 0, "spread out"},
+		{"less than", "a < b and b > c", 0, "a < b and b > c"},
+		{"runes", "Ünïcödé wörds everywhere", 13, "Ünïcödé…"},
+		{"tiny max", "Hello world", 1, "…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateDescription(tt.in, tt.max)
+			if got != tt.want {
+				t.Errorf("TruncateDescription(%q, %d) = %q, want %q", tt.in, tt.max, got, tt.want)
+			}
+			if tt.max > 0 && utf8.RuneCountInString(got) > tt.max {
+				t.Errorf("Result %q exceeds %d runes", got, tt.max)
+			}
+		})
+	}
+}
+
+func TestWithMaxDescriptionLength(t *testing.T) {
+	page := `<html><head>
+		<meta name="description" content="An unusually long description that goes on and on">
+		<meta property="og:description" content="Another long description for social previews">
+	</head></html>`
+
+	metadata, err := NewClient(WithMaxDescriptionLength(20)).ExtractFromHTML(strings.NewReader(page), "https://example.com/")
+	if err != nil {
+		t.Fatalf("ExtractFromHTML failed: %v", err)
+	}
+	if metadata.Description != "An unusually long…" {
+		t.Errorf("Expected truncated description, got %q", metadata.Description)
+	}
+	if metadata.OGDescription != "Another long…" {
+		t.Errorf("Expected truncated og:description, got %q", metadata.OGDescription)
+	}
+}
@@ -0,0 +1,132 @@
+package urlmeta
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// WithMaxConcurrentRequests caps the number of outbound HTTP requests the
+// client has in flight at once, across all goroutines calling Extract,
+// ExtractAll and the helper methods. A request holds its slot until the
+// response body is closed; further requests wait for a free slot or for
+// their context to end. Zero means no limit (default).
+func WithMaxConcurrentRequests(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.requestSlots = make(chan struct{}, n)
+		} else {
+			c.requestSlots = nil
+		}
+	}
+}
+
+// WithRateLimit limits the client to requestsPerSecond outbound HTTP
+// requests on average, allowing bursts of up to burst requests (at least
+// one). The limit is shared by every caller of the client; requests wait
+// for their turn or for their context to end. Zero disables rate limiting
+// (default).
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(c *Client) {
+		if requestsPerSecond > 0 {
+			c.rateLimiter = newRateLimiter(requestsPerSecond, burst)
+		} else {
+			c.rateLimiter = nil
+		}
+	}
+}
+
+// acquire waits for the rate limiter and a request slot, returning a
+// function that gives the slot back
+func (c *Client) acquire(ctx context.Context) (func(), error) {
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.requestSlots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case c.requestSlots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-c.requestSlots })
+	}, nil
+}
+
+// releasingBody gives the request slot back when the body is closed
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+// Close implements io.Closer
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
+
+// rateLimiter is a token bucket refilled at rate tokens per second
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter returns a full bucket
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait takes a token, sleeping until one is available or ctx ends
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	// Reserve the token now, so concurrent waiters queue up behind us
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Hand the reservation back
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,114 @@
+package urlmeta
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithMaxConcurrentRequests(t *testing.T) {
+	var inFlight, peak atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	urls := make([]string, 8)
+	for i := range urls {
+		urls[i] = server.URL
+	}
+
+	client := NewClient(WithMaxConcurrentRequests(2))
+	results, err := client.ExtractAll(context.Background(), urls, WithConcurrency(8))
+	if err != nil {
+		t.Fatalf("ExtractAll failed: %v", err)
+	}
+	for _, r := range results {
+		if r.Error != nil {
+			t.Fatalf("Extract failed: %v", r.Error)
+		}
+	}
+
+	if got := peak.Load(); got > 2 {
+		t.Errorf("Expected at most 2 requests in flight, got %d", got)
+	}
+}
+
+func TestWithMaxConcurrentRequestsContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithMaxConcurrentRequests(1))
+
+	// Hold the only slot
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := client.do(req)
+	if err != nil {
+		t.Fatalf("do failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.ExtractContext(ctx, server.URL); err == nil {
+		t.Error("Expected error while all request slots are taken")
+	}
+
+	// Closing the body frees the slot
+	resp.Body.Close()
+	if _, err := client.Extract(server.URL); err != nil {
+		t.Errorf("Extract failed after slot was released: %v", err)
+	}
+}
+
+func TestWithRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithRateLimit(20, 1))
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if _, err := client.Extract(server.URL); err != nil {
+			t.Fatalf("Extract failed: %v", err)
+		}
+	}
+
+	// The first request uses the burst token, the other three wait 50ms each
+	if elapsed := time.Since(start); elapsed < 140*time.Millisecond {
+		t.Errorf("Expected rate limiting to take at least 140ms, took %v", elapsed)
+	}
+}
+
+func TestRateLimiterContext(t *testing.T) {
+	limiter := newRateLimiter(1, 1)
+	if err := limiter.wait(context.Background()); err != nil {
+		t.Fatalf("Expected burst token to be available: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
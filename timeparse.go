@@ -0,0 +1,87 @@
+package urlmeta
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeLayouts are tried in order by parseTime. Layouts without a zone are
+// interpreted as UTC.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 -07:00",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	time.RFC850,
+	time.RFC822Z,
+	time.RFC822,
+	time.ANSIC,
+	time.UnixDate,
+	"January 2, 2006 15:04",
+	"January 2, 2006",
+	"Jan 2, 2006 15:04",
+	"Jan 2, 2006",
+	"2 January 2006",
+	"2 Jan 2006",
+	"20060102",
+}
+
+// parseTime parses the date formats found in the wild on web pages: RFC
+// 3339 with or without seconds, zone or "T", RFC 1123 and friends, written
+// out dates such as "May 5, 2024", and Unix timestamps in seconds or
+// milliseconds
+func parseTime(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+
+	// Unix timestamps: 10 digits are seconds, 13 are milliseconds
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		switch len(value) {
+		case 10:
+			return time.Unix(n, 0).UTC(), true
+		case 13:
+			return time.UnixMilli(n).UTC(), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// parseDates sets PublishedAt and ModifiedAt from the raw date strings
+func parseDates(metadata *Metadata) {
+	if t, ok := parseTime(metadata.PublishedTime); ok {
+		metadata.PublishedAt = &t
+	}
+
+	modified := metadata.ModifiedTime
+	if modified == "" {
+		modified = metadata.UpdatedTime
+	}
+	if t, ok := parseTime(modified); ok {
+		metadata.ModifiedAt = &t
+	}
+}
@@ -0,0 +1,77 @@
+package urlmeta
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	utc := func(year int, month time.Month, day, hour, min, sec int) time.Time {
+		return time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+	}
+
+	tests := []struct {
+		input    string
+		expected time.Time
+		ok       bool
+	}{
+		{"2024-05-12T08:30:00Z", utc(2024, 5, 12, 8, 30, 0), true},
+		{"2024-05-12T10:30:00+02:00", utc(2024, 5, 12, 8, 30, 0), true},
+		{"2024-05-12T10:30:00+0200", utc(2024, 5, 12, 8, 30, 0), true},
+		{"2024-05-12T08:30:00.123Z", time.Date(2024, 5, 12, 8, 30, 0, 123000000, time.UTC), true},
+		{"2024-05-12T08:30", utc(2024, 5, 12, 8, 30, 0), true},
+		{"2024-05-12 08:30:00", utc(2024, 5, 12, 8, 30, 0), true},
+		{"2024-05-12 10:30:00 +0200", utc(2024, 5, 12, 8, 30, 0), true},
+		{"2024-05-12", utc(2024, 5, 12, 0, 0, 0), true},
+		{"2024/05/12", utc(2024, 5, 12, 0, 0, 0), true},
+		{"Sun, 12 May 2024 08:30:00 GMT", utc(2024, 5, 12, 8, 30, 0), true},
+		{"Sun, 12 May 2024 10:30:00 +0200", utc(2024, 5, 12, 8, 30, 0), true},
+		{"May 12, 2024", utc(2024, 5, 12, 0, 0, 0), true},
+		{"12 May 2024", utc(2024, 5, 12, 0, 0, 0), true},
+		{"20240512", utc(2024, 5, 12, 0, 0, 0), true},
+		{"1715502600", utc(2024, 5, 12, 8, 30, 0), true},
+		{"1715502600000", utc(2024, 5, 12, 8, 30, 0), true},
+		{"  2024-05-12  ", utc(2024, 5, 12, 0, 0, 0), true},
+		{"last Tuesday", time.Time{}, false},
+		{"", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseTime(tt.input)
+		if ok != tt.ok || !got.Equal(tt.expected) {
+			t.Errorf("parseTime(%q) = %v, %v; expected %v, %v", tt.input, got, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestTypedDates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head>
+			<meta property="article:published_time" content="2024-05-12T08:30:00Z">
+			<meta property="og:updated_time" content="Mon, 13 May 2024 09:00:00 GMT">
+		</head></html>`))
+	}))
+	defer server.Close()
+
+	metadata, err := Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.PublishedAt == nil || !metadata.PublishedAt.Equal(time.Date(2024, 5, 12, 8, 30, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected PublishedAt %v", metadata.PublishedAt)
+	}
+
+	// ModifiedAt falls back to og:updated_time
+	if metadata.ModifiedAt == nil || !metadata.ModifiedAt.Equal(time.Date(2024, 5, 13, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected ModifiedAt %v", metadata.ModifiedAt)
+	}
+
+	// Raw strings are kept
+	if metadata.PublishedTime != "2024-05-12T08:30:00Z" {
+		t.Errorf("Expected raw published time to be kept, got %q", metadata.PublishedTime)
+	}
+}
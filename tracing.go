@@ -0,0 +1,65 @@
+package urlmeta
+
+import (
+	"context"
+	"net/url"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tracerName identifies the package as the instrumentation scope
+const tracerName = "github.com/alfarisi/urlmeta"
+
+// Span names
+const (
+	spanExtract        = "urlmeta.Extract"
+	spanFetchHTML      = "urlmeta.FetchHTML"
+	spanParseHTML      = "urlmeta.ParseHTML"
+	spanDiscoverOEmbed = "urlmeta.DiscoverOEmbed"
+	spanFetchOEmbed    = "urlmeta.FetchOEmbed"
+)
+
+// WithTracerProvider records OpenTelemetry spans for Extract, the HTML
+// fetch, HTML parsing, oEmbed discovery and the oEmbed fetch, carrying the
+// URL, host and response status. Spans are children of any span in the
+// context passed to ExtractContext. Tracing is disabled by default.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *Client) {
+		if tp == nil {
+			c.tracer = nil
+			return
+		}
+		c.tracer = tp.Tracer(tracerName)
+	}
+}
+
+// startSpan starts a span for rawURL, or returns a no-op span when tracing
+// is disabled
+func (c *Client) startSpan(ctx context.Context, name, rawURL string) (context.Context, trace.Span) {
+	if c.tracer == nil {
+		return ctx, noop.Span{}
+	}
+
+	attrs := []attribute.KeyValue{attribute.String("url.full", rawURL)}
+	if u, err := url.Parse(rawURL); err == nil && u.Hostname() != "" {
+		attrs = append(attrs, attribute.String("server.address", u.Hostname()))
+	}
+	return c.tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// setSpanStatusCode records the HTTP response status on span
+func setSpanStatusCode(span trace.Span, code int) {
+	span.SetAttributes(attribute.Int("http.response.status_code", code))
+}
+
+// endSpan ends span, marking it failed when err is set
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
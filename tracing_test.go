@@ -0,0 +1,145 @@
+package urlmeta
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newTracedClient returns a client recording its spans in the returned
+// recorder
+func newTracedClient(opts ...Option) (*Client, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	return NewClient(append(opts, WithTracerProvider(tp))...), recorder
+}
+
+// spanAttr returns the value of attribute key on span
+func spanAttr(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestTracingSpans(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Traced</title>
+			<link rel="alternate" type="application/json+oembed" href="/oembed">
+		</head></html>`))
+	})
+	mux.HandleFunc("/oembed", func(w http.ResponseWriter, r *http.Request) {
+		// Fail so extraction falls back to the HTML page
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	client, recorder := newTracedClient(WithStrategy(StrategyOEmbedFirst))
+	if _, err := client.Extract(server.URL + "/page"); err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	parseSpans := 0
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+		if span.Name() == spanParseHTML {
+			parseSpans++
+		}
+	}
+
+	root, ok := spans[spanExtract]
+	if !ok {
+		t.Fatalf("Expected a %s span, got %v", spanExtract, recorder.Ended())
+	}
+	if v, _ := spanAttr(root, "url.full"); v.AsString() != server.URL+"/page" {
+		t.Errorf("Expected url.full on root span, got %q", v.AsString())
+	}
+	if v, _ := spanAttr(root, "server.address"); v.AsString() != "127.0.0.1" {
+		t.Errorf("Expected server.address 127.0.0.1, got %q", v.AsString())
+	}
+
+	if root.Status().Code == codes.Error {
+		t.Errorf("Expected %s to succeed after falling back to HTML", spanExtract)
+	}
+
+	statuses := map[string]int64{
+		spanDiscoverOEmbed: http.StatusOK,
+		spanFetchOEmbed:    http.StatusInternalServerError,
+		spanFetchHTML:      http.StatusOK,
+	}
+	for name, status := range statuses {
+		span, ok := spans[name]
+		if !ok {
+			t.Errorf("Expected a %s span", name)
+			continue
+		}
+		if span.Parent().SpanID() != root.SpanContext().SpanID() {
+			t.Errorf("Expected %s to be a child of %s", name, spanExtract)
+		}
+		if v, ok := spanAttr(span, "http.response.status_code"); !ok || v.AsInt64() != status {
+			t.Errorf("Expected %s to record status %d, got %d", name, status, v.AsInt64())
+		}
+	}
+	if spans[spanFetchOEmbed].Status().Code != codes.Error {
+		t.Errorf("Expected failed %s span to have error status", spanFetchOEmbed)
+	}
+
+	// One parse during discovery, one for the page itself
+	if parseSpans != 2 {
+		t.Errorf("Expected 2 %s spans, got %d", spanParseHTML, parseSpans)
+	}
+	if parse := spans[spanParseHTML]; parse != nil && parse.Parent().SpanID() != spans[spanFetchHTML].SpanContext().SpanID() {
+		t.Errorf("Expected the last %s to be a child of %s", spanParseHTML, spanFetchHTML)
+	}
+}
+
+func TestTracingRecordsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, recorder := newTracedClient(WithAutoOEmbed(false))
+	if _, err := client.ExtractContext(context.Background(), server.URL); err == nil {
+		t.Fatal("Expected an error for a 404 page")
+	}
+
+	for _, span := range recorder.Ended() {
+		if span.Status().Code != codes.Error {
+			t.Errorf("Expected %s to have error status, got %v", span.Name(), span.Status().Code)
+		}
+		if span.Name() == spanFetchHTML {
+			if v, _ := spanAttr(span, "http.response.status_code"); v.AsInt64() != http.StatusNotFound {
+				t.Errorf("Expected status 404 on %s, got %d", spanFetchHTML, v.AsInt64())
+			}
+		}
+	}
+	if len(recorder.Ended()) != 2 {
+		t.Errorf("Expected Extract and FetchHTML spans, got %d spans", len(recorder.Ended()))
+	}
+}
+
+func TestTracingDisabledByDefault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	if _, err := NewClient(WithTracerProvider(nil)).Extract(server.URL); err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+}
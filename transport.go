@@ -0,0 +1,99 @@
+package urlmeta
+
+import (
+	"net/http"
+	"time"
+)
+
+// Connection pool settings of the shared default transport
+const (
+	defaultMaxIdleConns        = 256
+	defaultMaxIdleConnsPerHost = 16
+	defaultIdleConnTimeout     = 90 * time.Second
+)
+
+// sharedTransport is used by every client that doesn't bring or tune its
+// own, so connections are pooled across clients. Compared to
+// http.DefaultTransport it keeps more idle connections per host, which
+// batch workloads hitting the same hosts benefit from.
+var sharedTransport = newSharedTransport()
+
+// newSharedTransport returns http.DefaultTransport tuned for many requests
+// to a moderate number of hosts
+func newSharedTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConns = defaultMaxIdleConns
+	t.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	t.IdleConnTimeout = defaultIdleConnTimeout
+	t.ForceAttemptHTTP2 = true
+	return t
+}
+
+// WithTransport sets the transport used for all requests, replacing the
+// shared default. Options that need to adjust the transport (such as
+// WithProxy, WithSSRFProtection or the tuning options below) work on a
+// copy, leaving t itself untouched.
+func WithTransport(t *http.Transport) Option {
+	return func(c *Client) {
+		c.transport = t
+	}
+}
+
+// WithMaxIdleConnsPerHost sets how many idle connections are kept per host
+// (default: 16)
+func WithMaxIdleConnsPerHost(n int) Option {
+	return func(c *Client) {
+		c.transportTuning = append(c.transportTuning, func(t *http.Transport) {
+			t.MaxIdleConnsPerHost = n
+			if t.MaxIdleConns > 0 && t.MaxIdleConns < n {
+				t.MaxIdleConns = n
+			}
+		})
+	}
+}
+
+// WithIdleConnTimeout sets how long an idle connection is kept before it
+// is closed (default: 90s)
+func WithIdleConnTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.transportTuning = append(c.transportTuning, func(t *http.Transport) {
+			t.IdleConnTimeout = d
+		})
+	}
+}
+
+// WithForceHTTP2 controls whether HTTP/2 is attempted even with a custom
+// dialer or TLS config, as set by WithSSRFProtection (default: true)
+func WithForceHTTP2(force bool) Option {
+	return func(c *Client) {
+		c.transportTuning = append(c.transportTuning, func(t *http.Transport) {
+			t.ForceAttemptHTTP2 = force
+		})
+	}
+}
+
+// configureTransport picks the base transport and applies tuning options.
+// A transport set via WithHTTPClient is kept; it can only be tuned when it
+// is an *http.Transport.
+func (c *Client) configureTransport() {
+	if c.transport != nil {
+		c.httpClient.Transport = c.transport
+	} else if c.httpClient.Transport == nil {
+		c.httpClient.Transport = sharedTransport
+	}
+
+	if len(c.transportTuning) == 0 {
+		return
+	}
+
+	base, ok := c.httpClient.Transport.(*http.Transport)
+	if !ok {
+		return
+	}
+
+	t := base.Clone()
+	for _, tune := range c.transportTuning {
+		tune(t)
+	}
+	c.httpClient.Transport = t
+}
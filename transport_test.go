@@ -0,0 +1,92 @@
+package urlmeta
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// baseTransport returns the transport under the client's decoding layer
+func baseTransport(t *testing.T, c *Client) http.RoundTripper {
+	t.Helper()
+	dt, ok := c.httpClient.Transport.(*decodingTransport)
+	if !ok {
+		t.Fatalf("Expected decodingTransport, got %T", c.httpClient.Transport)
+	}
+	return dt.next
+}
+
+func TestSharedTransport(t *testing.T) {
+	a, b := NewClient(), NewClient(WithTimeout(time.Second))
+	if baseTransport(t, a) != sharedTransport || baseTransport(t, b) != sharedTransport {
+		t.Error("Expected default clients to share the package transport")
+	}
+	if sharedTransport.MaxIdleConnsPerHost != defaultMaxIdleConnsPerHost {
+		t.Errorf("Expected MaxIdleConnsPerHost %d, got %d", defaultMaxIdleConnsPerHost, sharedTransport.MaxIdleConnsPerHost)
+	}
+}
+
+func TestWithTransport(t *testing.T) {
+	custom := &http.Transport{MaxIdleConnsPerHost: 3}
+	if got := baseTransport(t, NewClient(WithTransport(custom))); got != custom {
+		t.Errorf("Expected custom transport, got %v", got)
+	}
+}
+
+func TestTransportTuning(t *testing.T) {
+	custom := &http.Transport{MaxIdleConns: 10, MaxIdleConnsPerHost: 3}
+	client := NewClient(
+		WithTransport(custom),
+		WithMaxIdleConnsPerHost(32),
+		WithIdleConnTimeout(time.Minute),
+		WithForceHTTP2(true),
+	)
+
+	tuned, ok := baseTransport(t, client).(*http.Transport)
+	if !ok || tuned == custom {
+		t.Fatalf("Expected a tuned copy of the custom transport, got %v", baseTransport(t, client))
+	}
+	if tuned.MaxIdleConnsPerHost != 32 || tuned.MaxIdleConns != 32 {
+		t.Errorf("Expected 32 idle connections, got %d per host, %d total", tuned.MaxIdleConnsPerHost, tuned.MaxIdleConns)
+	}
+	if tuned.IdleConnTimeout != time.Minute || !tuned.ForceAttemptHTTP2 {
+		t.Errorf("Expected tuning to apply, got timeout %v, HTTP/2 %v", tuned.IdleConnTimeout, tuned.ForceAttemptHTTP2)
+	}
+	if custom.MaxIdleConnsPerHost != 3 {
+		t.Error("Expected the caller's transport to be left untouched")
+	}
+
+	// Tuning the default clones the shared transport
+	tuned = baseTransport(t, NewClient(WithMaxIdleConnsPerHost(4))).(*http.Transport)
+	if tuned == sharedTransport || tuned.MaxIdleConnsPerHost != 4 || sharedTransport.MaxIdleConnsPerHost != defaultMaxIdleConnsPerHost {
+		t.Error("Expected tuning to work on a copy of the shared transport")
+	}
+}
+
+func TestWithHTTPClientTransportKept(t *testing.T) {
+	custom := &http.Transport{}
+	client := NewClient(WithHTTPClient(&http.Client{Transport: custom}))
+	if got := baseTransport(t, client); got != custom {
+		t.Errorf("Expected the HTTP client's transport to be kept, got %v", got)
+	}
+}
+
+func TestWithHTTPClientNotModified(t *testing.T) {
+	custom := &http.Transport{}
+	shared := &http.Client{Transport: custom}
+
+	first := NewClient(WithHTTPClient(shared), WithSSRFProtection(true), WithTimeout(time.Second))
+	second := NewClient(WithHTTPClient(shared), WithSSRFProtection(true), WithCookies("https://example.com", []*http.Cookie{{Name: "a", Value: "b"}}))
+
+	if shared.Transport != custom || shared.CheckRedirect != nil || shared.Jar != nil || shared.Timeout != 0 {
+		t.Errorf("Expected the caller's client to be left alone, got %+v", shared)
+	}
+
+	// Each client guards the caller's transport directly, rather than
+	// wrapping the other client's stack
+	for _, client := range []*Client{first, second} {
+		if _, ok := baseTransport(t, client).(*http.Transport); !ok {
+			t.Errorf("Expected a dial-guarded *http.Transport, got %T", baseTransport(t, client))
+		}
+	}
+}
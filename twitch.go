@@ -0,0 +1,104 @@
+package urlmeta
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Twitch player size, as in Twitch's own embed snippets
+const (
+	twitchEmbedWidth  = 620
+	twitchEmbedHeight = 378
+)
+
+// twitchName matches Twitch channel names and clip slugs
+var twitchName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// twitchVideoID matches the IDs of past broadcasts
+var twitchVideoID = regexp.MustCompile(`^\d+$`)
+
+// twitchReserved are www.twitch.tv paths that aren't channels
+var twitchReserved = map[string]bool{
+	"directory": true, "downloads": true, "friends": true, "inventory": true,
+	"jobs": true, "login": true, "p": true, "prime": true, "search": true,
+	"settings": true, "signup": true, "subscriptions": true, "turbo": true,
+	"videos": true, "wallet": true,
+}
+
+// WithTwitchParent sets the domains of the pages Twitch embeds will be
+// shown on. Twitch players only load when the embedding domain is passed
+// as a parent parameter, and Twitch no longer serves oEmbed, so with
+// parents set the oEmbed for Twitch clips, videos and channels is built
+// from the URL instead: a "video" embed of the Twitch player, without a
+// title (see WithOEmbedEnrichHTML). With StrategyAuto such URLs use the
+// oEmbed-first strategy. Without parents Twitch URLs are extracted from
+// their HTML like any other page. Not set by default.
+func WithTwitchParent(domains ...string) Option {
+	return func(c *Client) {
+		c.twitchParents = nil
+		for _, domain := range domains {
+			if domain = strings.TrimSpace(domain); domain != "" {
+				c.twitchParents = append(c.twitchParents, domain)
+			}
+		}
+	}
+}
+
+// twitchEmbed builds the oEmbed for a Twitch URL, or returns nil when u
+// isn't one or no parent domains are set
+func (c *Client) twitchEmbed(u *url.URL) *OEmbed {
+	if len(c.twitchParents) == 0 {
+		return nil
+	}
+	src := twitchPlayerURL(u)
+	if src == nil {
+		return nil
+	}
+
+	query := src.Query()
+	for _, parent := range c.twitchParents {
+		query.Add("parent", parent)
+	}
+	src.RawQuery = query.Encode()
+
+	return &OEmbed{
+		Type:         "video",
+		Version:      "1.0",
+		ProviderName: "Twitch",
+		ProviderURL:  "https://www.twitch.tv",
+		Width:        twitchEmbedWidth,
+		Height:       twitchEmbedHeight,
+		HTML: fmt.Sprintf(`<iframe src="%s" width="%d" height="%d" frameborder="0" scrolling="no" allowfullscreen="true"></iframe>`,
+			html.EscapeString(src.String()), twitchEmbedWidth, twitchEmbedHeight),
+	}
+}
+
+// twitchPlayerURL returns the player URL, without parents, for a Twitch
+// clip (clips.twitch.tv/<slug>, twitch.tv/<channel>/clip/<slug>), video
+// (twitch.tv/videos/<id>) or channel (twitch.tv/<channel>), or nil
+func twitchPlayerURL(u *url.URL) *url.URL {
+	host := strings.ToLower(u.Hostname())
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	switch {
+	case host == "clips.twitch.tv" && len(parts) == 1 && twitchName.MatchString(parts[0]):
+		return twitchClipURL(parts[0])
+	case host != "twitch.tv" && host != "www.twitch.tv" && host != "m.twitch.tv":
+		return nil
+	case len(parts) == 3 && parts[1] == "clip" && twitchName.MatchString(parts[2]):
+		return twitchClipURL(parts[2])
+	case len(parts) == 2 && parts[0] == "videos" && twitchVideoID.MatchString(parts[1]):
+		return &url.URL{Scheme: "https", Host: "player.twitch.tv", Path: "/", RawQuery: "video=" + parts[1]}
+	case len(parts) == 1 && twitchName.MatchString(parts[0]) && !twitchReserved[strings.ToLower(parts[0])]:
+		return &url.URL{Scheme: "https", Host: "player.twitch.tv", Path: "/", RawQuery: "channel=" + strings.ToLower(parts[0])}
+	}
+	return nil
+}
+
+// twitchClipURL returns the clip player URL for slug
+func twitchClipURL(slug string) *url.URL {
+	return &url.URL{Scheme: "https", Host: "clips.twitch.tv", Path: "/embed", RawQuery: "clip=" + url.QueryEscape(slug)}
+}
@@ -0,0 +1,76 @@
+package urlmeta
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTwitchEmbed(t *testing.T) {
+	client := NewClient(WithTwitchParent("example.com", " ", "www.example.com"))
+
+	tests := []struct {
+		url  string
+		src  string
+		want bool
+	}{
+		{"https://clips.twitch.tv/AwkwardHelplessSalamander", "https://clips.twitch.tv/embed?clip=AwkwardHelplessSalamander&amp;parent=example.com&amp;parent=www.example.com", true},
+		{"https://www.twitch.tv/somechannel/clip/AwkwardHelplessSalamander", "https://clips.twitch.tv/embed?clip=AwkwardHelplessSalamander&amp;parent=example.com", true},
+		{"https://www.twitch.tv/videos/123456789", "https://player.twitch.tv/?parent=example.com&amp;parent=www.example.com&amp;video=123456789", true},
+		{"https://twitch.tv/SomeChannel", "https://player.twitch.tv/?channel=somechannel&amp;parent=example.com", true},
+		{"https://www.twitch.tv/directory", "", false},
+		{"https://www.twitch.tv/videos/abc", "", false},
+		{"https://www.twitch.tv/somechannel/videos", "", false},
+		{"https://example.com/somechannel", "", false},
+	}
+
+	for _, tt := range tests {
+		oembed := client.embedFromURL(tt.url)
+		if (oembed != nil) != tt.want {
+			t.Errorf("embedFromURL(%s) = %+v, want embed: %v", tt.url, oembed, tt.want)
+			continue
+		}
+		if oembed == nil {
+			continue
+		}
+		if !strings.Contains(oembed.HTML, `src="`+tt.src) {
+			t.Errorf("embedFromURL(%s): expected src %s, got %s", tt.url, tt.src, oembed.HTML)
+		}
+		if oembed.Type != "video" || oembed.ProviderName != "Twitch" || oembed.Width == 0 || oembed.Height == 0 {
+			t.Errorf("embedFromURL(%s): unexpected oEmbed %+v", tt.url, oembed)
+		}
+	}
+}
+
+func TestTwitchEmbedNeedsParent(t *testing.T) {
+	if oembed := NewClient().embedFromURL("https://clips.twitch.tv/AwkwardHelplessSalamander"); oembed != nil {
+		t.Errorf("Expected no embed without parent domains, got %+v", oembed)
+	}
+}
+
+func TestExtractOEmbedTwitch(t *testing.T) {
+	client := NewClient(WithTwitchParent("example.com"))
+
+	// Built from the URL, without a request
+	oembed, err := client.ExtractOEmbed("https://www.twitch.tv/videos/123456789")
+	if err != nil {
+		t.Fatalf("ExtractOEmbed failed: %v", err)
+	}
+	if !strings.Contains(oembed.HTML, "player.twitch.tv") {
+		t.Errorf("Expected a Twitch player embed, got %q", oembed.HTML)
+	}
+}
+
+func TestExtractTwitchAutoStrategy(t *testing.T) {
+	if IsOEmbedSupported("https://www.twitch.tv/videos/123456789") {
+		t.Error("Expected no oEmbed provider for Twitch")
+	}
+
+	// With parents set, StrategyAuto builds the embed without fetching
+	metadata, err := NewClient(WithTwitchParent("example.com")).Extract("https://www.twitch.tv/videos/123456789")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if metadata.OEmbed == nil || metadata.ProviderName != "Twitch" {
+		t.Errorf("Expected a Twitch player embed, got %+v", metadata.OEmbed)
+	}
+}
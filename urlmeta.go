@@ -3,15 +3,20 @@
 package urlmeta
 
 import (
+	"context"
 	"fmt"
-	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
 )
 
 // Metadata represents extracted information from a web page
@@ -21,6 +26,10 @@ type Metadata struct {
 	Description  string `json:"description"`
 	URL          string `json:"url"`
 	CanonicalURL string `json:"canonical_url,omitempty"`
+	AMPURL       string `json:"amp_url,omitempty"`
+
+	// Heading is the text of the first <h1>
+	Heading string `json:"heading,omitempty"`
 
 	// Provider Info
 	ProviderName    string `json:"provider_name"`
@@ -37,23 +46,97 @@ type Metadata struct {
 	Locale   string `json:"locale,omitempty"`
 	OGTitle  string `json:"og_title,omitempty"`
 
+	// og:description and twitter:description as written; Description
+	// holds whichever description the page declares first
+	OGDescription      string `json:"og_description,omitempty"`
+	TwitterDescription string `json:"twitter_description,omitempty"`
+
+	// Language is the page language as a BCP 47 tag (e.g. "en-US"), from
+	// <html lang>, Content-Language or og:locale
+	Language string `json:"language,omitempty"`
+
+	// og:updated_time, kept as written
+	UpdatedTime string `json:"updated_time,omitempty"`
+
+	// Facebook
+	FacebookAppID string   `json:"fb_app_id,omitempty"`
+	FacebookPages []string `json:"fb_pages,omitempty"`
+
 	// Additional Meta
 	Author        string   `json:"author,omitempty"`
 	PublishedTime string   `json:"published_time,omitempty"`
 	ModifiedTime  string   `json:"modified_time,omitempty"`
 	Keywords      []string `json:"keywords,omitempty"`
 
+	// Generator is the publishing platform, from <meta name="generator">
+	// (e.g. "WordPress 6.4") or detected from headers and asset URLs
+	Generator string `json:"generator,omitempty"`
+
+	// PublishedTimeSource tells where PublishedTime was found, to gauge
+	// its reliability
+	PublishedTimeSource DateSource `json:"published_time_source,omitempty"`
+
+	// PublishedTime and ModifiedTime (or UpdatedTime) parsed, when valid
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	ModifiedAt  *time.Time `json:"modified_at,omitempty"`
+
+	// Content analysis (only with WithContentAnalysis)
+	WordCount   int           `json:"word_count,omitempty"`
+	ReadingTime time.Duration `json:"reading_time,omitempty"`
+
+	// Main article content (only with WithFullContent)
+	Content *Content `json:"content,omitempty"`
+
 	// Twitter Card
 	TwitterCard    string `json:"twitter_card,omitempty"`
 	TwitterSite    string `json:"twitter_site,omitempty"`
 	TwitterCreator string `json:"twitter_creator,omitempty"`
 	TwitterTitle   string `json:"twitter_title,omitempty"`
 
+	// Open Graph video:/music: namespaces
+	VideoInfo *VideoInfo `json:"video_info,omitempty"`
+	Music     *MusicInfo `json:"music,omitempty"`
+
+	// Open Graph book:/profile: namespaces
+	Book    *Book    `json:"book,omitempty"`
+	Profile *Profile `json:"profile,omitempty"`
+
+	// Open Graph commerce properties (price, availability, brand)
+	Product *Product `json:"product,omitempty"`
+
+	// Site-specific details from site extractors (see WithSiteExtractors)
+	Reddit     *RedditPost     `json:"reddit,omitempty"`
+	GitHub     *GitHubInfo     `json:"github,omitempty"`
+	HackerNews *HackerNewsItem `json:"hacker_news,omitempty"`
+
 	// Favicon
 	Favicon string `json:"favicon,omitempty"`
+	Icons   []Icon `json:"icons,omitempty"`
+
+	// Browser chrome colors (theme-color, msapplication-*, mask-icon)
+	Branding *Branding `json:"branding,omitempty"`
+
+	// RSS/Atom/JSON Feed links advertised by the page
+	Feeds []FeedLink `json:"feeds,omitempty"`
+
+	// Schema.org microdata items found anywhere in the page
+	Microdata []MicrodataItem `json:"microdata,omitempty"`
+
+	// Robots directives from <meta name="robots"> and X-Robots-Tag
+	Robots *RobotsDirectives `json:"robots,omitempty"`
 
 	// oEmbed (automatically included if available)
 	OEmbed *OEmbed `json:"oembed,omitempty"`
+
+	// Warnings lists non-fatal problems hit while extracting
+	Warnings []Warning `json:"warnings,omitempty"`
+
+	// Debug holds the raw artifacts behind this result (only with WithDebug)
+	Debug *DebugInfo `json:"debug,omitempty"`
+
+	// CacheExpiresAt is when the cached copy of this result expires
+	// (nil when caching is disabled or the entry has no expiry)
+	CacheExpiresAt *time.Time `json:"cache_expires_at,omitempty"`
 }
 
 // Image represents an image from the page
@@ -62,6 +145,18 @@ type Image struct {
 	Width  int    `json:"width,omitempty"`
 	Height int    `json:"height,omitempty"`
 	Alt    string `json:"alt,omitempty"`
+	Type   string `json:"type,omitempty"`
+
+	// Source records where the image was declared (og, twitter, ...)
+	Source ImageSource `json:"source,omitempty"`
+
+	// Reported by the image server (only with WithValidateImages)
+	ContentType   string `json:"content_type,omitempty"`
+	ContentLength int64  `json:"content_length,omitempty"`
+
+	// Colors of the preview image (only with WithImagePalette)
+	DominantColor string   `json:"dominant_color,omitempty"`
+	Palette       []string `json:"palette,omitempty"`
 }
 
 // Video represents a video from the page
@@ -72,6 +167,9 @@ type Video struct {
 	Height int    `json:"height,omitempty"`
 }
 
+// defaultMaxBodySize is the default response body limit (10MB)
+const defaultMaxBodySize = 10 * 1024 * 1024
+
 // ExtractionStrategy determines how metadata is extracted
 type ExtractionStrategy int
 
@@ -82,8 +180,25 @@ const (
 	StrategyOEmbedFirst
 	// StrategyHTMLOnly only extracts from HTML (fastest for non-embed sites)
 	StrategyHTMLOnly
+	// StrategyParallel fetches oEmbed and HTML concurrently and merges both
+	StrategyParallel
 )
 
+// String returns the strategy name, as used in logs
+func (s ExtractionStrategy) String() string {
+	switch s {
+	case StrategyAuto:
+		return "auto"
+	case StrategyOEmbedFirst:
+		return "oembed_first"
+	case StrategyHTMLOnly:
+		return "html_only"
+	case StrategyParallel:
+		return "parallel"
+	}
+	return fmt.Sprintf("ExtractionStrategy(%d)", int(s))
+}
+
 // Client handles URL metadata extraction
 type Client struct {
 	httpClient   *http.Client
@@ -91,6 +206,75 @@ type Client struct {
 	maxRedirects int
 	autoOEmbed   bool
 	strategy     ExtractionStrategy
+	enrichOEmbed bool
+
+	ssrfProtection  bool
+	allowedHosts    []string
+	blockedHosts    []string
+	maxBodySize     int64
+	headOnly        bool
+	preferAMP       bool
+	verifyFavicon   bool
+	contentAnalysis bool
+	fullContent     bool
+	strict          bool
+
+	descriptionMinLength int
+	titleFallbacks       []TitleFallback
+	cleanTitle           bool
+	maxDescriptionLength int
+	bodyImageFallback    int
+	imageFilter          *ImageCriteria
+	probeImages          bool
+	validateImages       bool
+	paletteSize          int
+	detectLanguage       bool
+	respectRobots        bool
+
+	requestSlots  chan struct{}
+	rateLimiter   *rateLimiter
+	retryAttempts int
+	retryBackoff  BackoffFunc
+	breaker       *circuitBreaker
+	proxy         func(*http.Request) (*url.URL, error)
+	cookieJar     http.CookieJar
+	cookies       []presetCookies
+	credentials   []hostCredential
+
+	providerCredentials map[string]Credential
+	siteExtractors      []SiteExtractor
+
+	renderer        Renderer
+	renderFallback  bool
+	fallbackSources []FallbackSource
+
+	requestHooks  []func(*http.Request)
+	responseHooks []func(*http.Response, time.Duration)
+	errorHooks    []func(url string, err error)
+
+	logger  *slog.Logger
+	tracer  trace.Tracer
+	metrics Metrics
+	debug   bool
+
+	transport       *http.Transport
+	transportTuning []func(*http.Transport)
+
+	cache         CacheStore
+	cacheTTL      time.Duration
+	normalizeURLs bool
+	cacheHits     atomic.Uint64
+	cacheMisses   atomic.Uint64
+
+	endpointCache *endpointCache
+	fediverse     *endpointCache
+	twitchParents []string
+
+	providerRefresh     time.Duration
+	providerRegistryURL string
+	stopRefresh         context.CancelFunc
+	refreshDone         chan struct{}
+	refreshed           atomic.Pointer[[]OEmbedProvider]
 }
 
 // Option is a function that configures a Client
@@ -117,10 +301,16 @@ func WithMaxRedirects(max int) Option {
 	}
 }
 
-// WithHTTPClient sets custom HTTP client
+// WithHTTPClient sets custom HTTP client. The client is copied: the
+// redirect policy, cookie jar and transport wrappers are installed on the
+// copy, so the caller's client is left unchanged and can be shared.
 func WithHTTPClient(client *http.Client) Option {
 	return func(c *Client) {
-		c.httpClient = client
+		if client == nil {
+			return
+		}
+		hc := *client
+		c.httpClient = &hc
 	}
 }
 
@@ -138,6 +328,52 @@ func WithStrategy(strategy ExtractionStrategy) Option {
 	}
 }
 
+// WithOEmbedEnrichHTML also fetches the page when oEmbed succeeds, and
+// fills the fields oEmbed doesn't provide (Description, Keywords, Favicon,
+// CanonicalURL, ...) from its HTML, as Metadata.Merge does with the oEmbed
+// values winning. This costs the HTTP call the oEmbed-first strategy
+// saves. If the page fails, the oEmbed result is returned with a
+// WarningEnrichFailed warning. Disabled by default.
+func WithOEmbedEnrichHTML(enrich bool) Option {
+	return func(c *Client) {
+		c.enrichOEmbed = enrich
+	}
+}
+
+// WithMaxBodySize limits how many bytes are read from HTML and oEmbed
+// responses (default: 10MB). Larger bodies fail with ErrBodyTooLarge.
+// A value <= 0 disables the limit.
+func WithMaxBodySize(bytes int64) Option {
+	return func(c *Client) {
+		c.maxBodySize = bytes
+	}
+}
+
+// WithCache enables an in-memory LRU cache holding up to size results for ttl
+// each, so repeated Extract calls for the same URL skip the network.
+// A zero ttl keeps entries until they are evicted by newer ones.
+func WithCache(size int, ttl time.Duration) Option {
+	return func(c *Client) {
+		c.cache = NewMemoryCache(size)
+		c.cacheTTL = ttl
+	}
+}
+
+// WithCacheStore caches results in a custom backend (e.g. Redis) shared across
+// instances. Entries are stored with the ttl given to WithCacheTTL.
+func WithCacheStore(store CacheStore) Option {
+	return func(c *Client) {
+		c.cache = store
+	}
+}
+
+// WithCacheTTL sets the lifetime of cached results (default: no expiry)
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.cacheTTL = ttl
+	}
+}
+
 // NewClient creates a new metadata extraction client with options
 func NewClient(opts ...Option) *Client {
 	c := &Client{
@@ -148,6 +384,8 @@ func NewClient(opts ...Option) *Client {
 		maxRedirects: 10,
 		autoOEmbed:   true,
 		strategy:     StrategyAuto,
+		maxBodySize:  defaultMaxBodySize,
+		strict:       true,
 	}
 
 	for _, opt := range opts {
@@ -157,60 +395,261 @@ func NewClient(opts ...Option) *Client {
 	// Configure redirect policy
 	c.httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
 		if len(via) >= c.maxRedirects {
-			return fmt.Errorf("stopped after %d redirects", c.maxRedirects)
+			return fmt.Errorf("%w: stopped after %d redirects", ErrTooManyRedirects, c.maxRedirects)
 		}
-		return nil
+		c.logDebug(req.Context(), "urlmeta: following redirect", "from", via[len(via)-1].URL.String(), "to", req.URL.String(), "hop", len(via))
+		recordRedirect(req, via)
+		return c.checkHost(req.URL)
+	}
+
+	c.configureTransport()
+	c.configureCookies()
+
+	if c.proxy != nil {
+		c.httpClient.Transport = proxyTransport(c.httpClient.Transport, c.proxy)
 	}
 
+	if c.ssrfProtection {
+		c.httpClient.Transport = guardTransport(c.httpClient.Transport)
+	}
+
+	c.httpClient.Transport = &decodingTransport{next: transportOrDefault(c.httpClient.Transport)}
+
+	c.startProviderRefresh()
+
 	return c
 }
 
 // Extract extracts metadata from the given URL using optimal strategy
 func (c *Client) Extract(targetURL string) (*Metadata, error) {
+	return c.ExtractContext(context.Background(), targetURL)
+}
+
+// ExtractContext extracts metadata from the given URL, aborting outbound
+// requests when ctx is cancelled or its deadline expires
+func (c *Client) ExtractContext(ctx context.Context, targetURL string) (*Metadata, error) {
+	ctx, span := c.startSpan(ctx, spanExtract, targetURL)
+	metadata, err := c.extractCached(ctx, targetURL)
+	endSpan(span, err)
+	if err != nil {
+		c.logInfo(ctx, "urlmeta: extraction failed", "url", targetURL, "error", err)
+		c.runErrorHooks(targetURL, err)
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// extractCached validates targetURL and extracts it, going through the
+// cache when one is configured
+func (c *Client) extractCached(ctx context.Context, targetURL string) (*Metadata, error) {
 	// Normalize URL
 	targetURL = normalizeURL(targetURL)
 
 	parsedURL, err := url.Parse(targetURL)
 	if err != nil {
-		return nil, fmt.Errorf("invalid URL: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidURL, err)
 	}
 
 	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
-		return nil, fmt.Errorf("unsupported protocol: %s (only http and https are supported)", parsedURL.Scheme)
+		return nil, fmt.Errorf("%w: %s (only http and https are supported)", ErrUnsupportedScheme, parsedURL.Scheme)
 	}
 
+	cacheKey := c.urlKey(targetURL)
+	useCache := c.cache != nil && !skipCache(ctx)
+	if useCache && !bypassCache(ctx) {
+		// A failing cache backend must not fail extraction; treat it as a miss
+		if cached, ok, cacheErr := c.cache.Get(ctx, cacheKey); cacheErr == nil && ok {
+			c.cacheHits.Add(1)
+			c.observeCacheLookup(true)
+			c.logDebug(ctx, "urlmeta: cache hit", "url", targetURL)
+			return cached, nil
+		} else if cacheErr != nil {
+			c.logInfo(ctx, "urlmeta: cache lookup failed", "url", targetURL, "error", cacheErr)
+		}
+		c.cacheMisses.Add(1)
+		c.observeCacheLookup(false)
+	}
+
+	metadata, err := c.extract(ctx, targetURL, parsedURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if useCache {
+		ttl := c.effectiveCacheTTL(metadata)
+		if ttl > 0 {
+			expiresAt := time.Now().Add(ttl)
+			metadata.CacheExpiresAt = &expiresAt
+		}
+		_ = c.cache.Set(ctx, cacheKey, metadata, ttl)
+	}
+
+	return metadata, nil
+}
+
+// effectiveCacheTTL returns the provider-suggested oEmbed cache_age when
+// present, falling back to the client-wide cache TTL
+func (c *Client) effectiveCacheTTL(metadata *Metadata) time.Duration {
+	if metadata.OEmbed != nil && metadata.OEmbed.CacheAge > 0 {
+		return time.Duration(metadata.OEmbed.CacheAge) * time.Second
+	}
+	return c.cacheTTL
+}
+
+// CacheStats returns hit/miss counters for the client cache
+func (c *Client) CacheStats() CacheStats {
+	stats := CacheStats{
+		Hits:   c.cacheHits.Load(),
+		Misses: c.cacheMisses.Load(),
+	}
+	if counter, ok := c.cache.(interface{ Len() int }); ok {
+		stats.Entries = counter.Len()
+	}
+	return stats
+}
+
+// extract runs the configured extraction strategy for a validated URL
+func (c *Client) extract(ctx context.Context, targetURL string, parsedURL *url.URL) (*Metadata, error) {
 	// Choose extraction strategy
 	strategy := c.strategy
+	if cfg := requestConfigFrom(ctx); cfg != nil && cfg.strategy != nil {
+		strategy = *cfg.strategy
+	}
 	if strategy == StrategyAuto {
 		// Auto-detect: if oEmbed supported, use oEmbed-first strategy
-		if c.autoOEmbed && IsOEmbedSupported(targetURL) {
+		if c.autoOEmbed && (c.findOEmbedEndpoint(targetURL) != "" || c.embedFromURL(targetURL) != nil || c.isFediversePost(ctx, parsedURL)) {
 			strategy = StrategyOEmbedFirst
 		} else {
 			strategy = StrategyHTMLOnly
 		}
 	}
+	c.logDebug(ctx, "urlmeta: extraction strategy selected", "url", targetURL, "strategy", strategy.String())
+	start := time.Now()
 
-	// Execute strategy
-	switch strategy {
-	case StrategyOEmbedFirst:
-		return c.extractOEmbedFirst(targetURL, parsedURL)
-	case StrategyHTMLOnly:
-		return c.extractHTMLOnly(targetURL, parsedURL)
-	default:
-		return c.extractHTMLOnly(targetURL, parsedURL)
+	var debug *DebugInfo
+	if c.debug {
+		debug = &DebugInfo{}
+		ctx = withDebugInfo(ctx, debug)
+	}
+
+	// Execute strategy, unless a site extractor handles the URL
+	var err error
+	metadata, extractor, siteErr := c.extractSite(ctx, targetURL, parsedURL)
+	if metadata == nil {
+		switch strategy {
+		case StrategyOEmbedFirst:
+			metadata, err = c.extractOEmbedFirst(ctx, targetURL, parsedURL)
+		case StrategyHTMLOnly:
+			metadata, err = c.extractHTMLOnly(ctx, targetURL, parsedURL)
+		case StrategyParallel:
+			metadata, err = c.extractParallel(ctx, targetURL, parsedURL)
+		default:
+			metadata, err = c.extractHTMLOnly(ctx, targetURL, parsedURL)
+		}
+		if siteErr != nil && err == nil {
+			metadata.addWarning(WarningSiteExtractorFailed, "%s: %v", extractor, siteErr)
+		}
 	}
+	if c.needsFallback(ctx, metadata, err) {
+		cause := "no metadata found"
+		if err != nil {
+			cause = err.Error()
+		}
+		if fallback, fallbackErr := c.extractFallback(ctx, targetURL, parsedURL, cause); fallbackErr == nil {
+			metadata, err = fallback, nil
+		} else {
+			c.logInfo(ctx, "urlmeta: fallback sources failed", "url", targetURL, "error", fallbackErr)
+		}
+	}
+	if err != nil {
+		c.observeExtraction(strategy, start, err)
+		return nil, err
+	}
+
+	metadata.Debug = debug
+	c.processImages(ctx, metadata)
+	c.logWarnings(ctx, targetURL, metadata)
+	c.observeExtraction(strategy, start, nil)
+	return metadata, nil
 }
 
 // extractOEmbedFirst tries oEmbed first, optionally fetches HTML for additional data
-func (c *Client) extractOEmbedFirst(targetURL string, parsedURL *url.URL) (*Metadata, error) {
+func (c *Client) extractOEmbedFirst(ctx context.Context, targetURL string, parsedURL *url.URL) (*Metadata, error) {
 	// Step 1: Get oEmbed data (ONLY 1 HTTP call!)
-	oembed, err := c.ExtractOEmbed(targetURL)
+	oembed, err := c.ExtractOEmbedContext(ctx, targetURL)
 	if err != nil {
 		// oEmbed failed, fall back to HTML
-		return c.extractHTMLOnly(targetURL, parsedURL)
+		metadata, htmlErr := c.extractHTMLOnly(ctx, targetURL, parsedURL)
+		if htmlErr != nil {
+			return nil, htmlErr
+		}
+		metadata.addWarning(WarningOEmbedFailed, "%v", err)
+		return metadata, nil
 	}
 
 	// Step 2: Build metadata from oEmbed (no HTML parsing needed!)
+	metadata := oembedMetadata(targetURL, parsedURL, oembed)
+
+	// OPTIMIZATION: We already have enough data from oEmbed!
+	// Skip HTML fetching unless user explicitly needs it
+	// This saves 1 HTTP call and parsing time!
+	if c.enrichOEmbed {
+		page, err := c.extractHTMLOnly(ctx, targetURL, parsedURL)
+		if err != nil {
+			metadata.addWarning(WarningEnrichFailed, "%v", err)
+			return metadata, nil
+		}
+		metadata = metadata.Merge(page, MergePolicy{})
+	}
+
+	return metadata, nil
+}
+
+// extractParallel runs the oEmbed lookup and the HTML extraction
+// concurrently and merges the results, oEmbed values winning except for
+// the page's final URL. Only endpoints known without the page are looked
+// up concurrently; otherwise the oEmbed link is taken from the fetched
+// page, so the page is requested once. Either side failing leaves the
+// other's result, with a warning.
+func (c *Client) extractParallel(ctx context.Context, targetURL string, parsedURL *url.URL) (*Metadata, error) {
+	var (
+		oembed    *OEmbed
+		oembedErr error
+		wg        sync.WaitGroup
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		oembed, oembedErr = c.extractOEmbed(ctx, targetURL, false)
+	}()
+
+	page, fetched, pageErr := c.extractHTMLPage(ctx, targetURL, parsedURL)
+	wg.Wait()
+
+	if oembedErr != nil && pageErr == nil {
+		if endpoint := pageOEmbedLink(fetched.doc, fetched.finalURL); endpoint != "" {
+			oembed, oembedErr = c.discoveredOEmbed(ctx, targetURL, endpoint)
+		}
+	}
+
+	switch {
+	case oembedErr != nil && pageErr != nil:
+		return nil, pageErr
+	case oembedErr != nil:
+		page.addWarning(WarningOEmbedFailed, "%v", oembedErr)
+		return page, nil
+	case pageErr != nil:
+		metadata := oembedMetadata(targetURL, parsedURL, oembed)
+		metadata.addWarning(WarningEnrichFailed, "%v", pageErr)
+		return metadata, nil
+	}
+	return oembedMetadata(targetURL, parsedURL, oembed).Merge(page, MergePolicy{
+		Fields: map[string]Precedence{"url": PreferOther},
+	}), nil
+}
+
+// oembedMetadata builds metadata from an oEmbed response alone
+func oembedMetadata(targetURL string, parsedURL *url.URL, oembed *OEmbed) *Metadata {
 	metadata := &Metadata{
 		URL:             targetURL,
 		ProviderURL:     fmt.Sprintf("%s://%s", parsedURL.Scheme, parsedURL.Host),
@@ -244,6 +683,7 @@ func (c *Client) extractOEmbedFirst(targetURL string, parsedURL *url.URL) (*Meta
 			URL:    oembed.ThumbnailURL,
 			Width:  oembed.ThumbnailWidth,
 			Height: oembed.ThumbnailHeight,
+			Source: ImageSourceOEmbed,
 		})
 	}
 
@@ -253,22 +693,73 @@ func (c *Client) extractOEmbedFirst(targetURL string, parsedURL *url.URL) (*Meta
 			URL:    oembed.URL,
 			Width:  oembed.Width,
 			Height: oembed.Height,
+			Type:   imageTypeFromURL(oembed.URL),
+			Source: ImageSourceOEmbed,
 		})
 	}
 
 	// Set type based on oEmbed
 	metadata.Type = oembed.Type
 
-	// OPTIMIZATION: We already have enough data from oEmbed!
-	// Skip HTML fetching unless user explicitly needs it
-	// This saves 1 HTTP call and parsing time!
-
-	return metadata, nil
+	return metadata
 }
 
 // extractHTMLOnly extracts metadata from HTML only
-func (c *Client) extractHTMLOnly(targetURL string, parsedURL *url.URL) (*Metadata, error) {
-	req, err := http.NewRequest("GET", targetURL, nil)
+func (c *Client) extractHTMLOnly(ctx context.Context, targetURL string, parsedURL *url.URL) (*Metadata, error) {
+	metadata, _, err := c.extractHTMLPage(ctx, targetURL, parsedURL)
+	return metadata, err
+}
+
+// extractHTMLPage is extractHTMLOnly also returning the page the metadata
+// was built from
+func (c *Client) extractHTMLPage(ctx context.Context, targetURL string, parsedURL *url.URL) (*Metadata, *htmlPage, error) {
+	page, err := c.loadPage(ctx, targetURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	metadata := c.buildMetadata(page, parsedURL)
+	recordPageDebug(ctx, page)
+
+	if c.needsRendering(metadata) {
+		if rendered, err := c.renderPage(ctx, targetURL); err != nil {
+			metadata.addWarning(WarningRenderFailed, "%v", err)
+		} else {
+			page = rendered
+			metadata = c.buildMetadata(page, parsedURL)
+			recordPageDebug(ctx, page)
+		}
+	}
+
+	if c.preferAMP && metadata.AMPURL != "" && metadata.AMPURL != metadata.URL {
+		metadata = c.extractAMP(ctx, metadata, parsedURL)
+	}
+
+	if c.verifyFavicon {
+		c.verifyFaviconURL(ctx, metadata)
+	}
+
+	return metadata, page, nil
+}
+
+// htmlPage is a fetched and parsed HTML document
+type htmlPage struct {
+	doc       *html.Node
+	finalURL  *url.URL
+	header    http.Header
+	truncated bool
+
+	// rawHead is the page source up to </head> (only with WithDebug)
+	rawHead []byte
+}
+
+// fetchHTML downloads and parses targetURL, enforcing status, content type
+// and size checks
+func (c *Client) fetchHTML(ctx context.Context, targetURL string) (page *htmlPage, err error) {
+	ctx, span := c.startSpan(ctx, spanFetchHTML, targetURL)
+	defer func() { endSpan(span, err) }()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", targetURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -277,36 +768,62 @@ func (c *Client) extractHTMLOnly(targetURL string, parsedURL *url.URL) (*Metadat
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
 	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+		return nil, fmt.Errorf("failed to fetch URL: %w", classifyFetchError(err))
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
 			_ = closeErr
 		}
 	}()
+	setSpanStatusCode(span, resp.StatusCode)
+
+	return c.readResponse(ctx, resp)
+}
 
+// readResponse checks and parses an HTML response: status, content type,
+// size limit and charset. The body is left for the caller to close.
+func (c *Client) readResponse(ctx context.Context, resp *http.Response) (*htmlPage, error) {
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP error: %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+		return nil, &ErrHTTPStatus{Code: resp.StatusCode}
 	}
 
 	// Check content type
 	contentType := resp.Header.Get("Content-Type")
 	if !strings.Contains(contentType, "text/html") && !strings.Contains(contentType, "application/xhtml") {
-		return nil, fmt.Errorf("unsupported content type: %s", contentType)
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedContentType, contentType)
 	}
 
 	// Limit response body size to prevent memory issues
-	limitedBody := io.LimitReader(resp.Body, 10*1024*1024) // 10MB limit
+	limitedBody, err := c.limitBody(resp)
+	if err != nil {
+		return nil, err
+	}
 
-	doc, err := html.Parse(limitedBody)
+	body, capture := c.debugReader(limitedBody)
+
+	_, parseSpan := c.startSpan(ctx, spanParseHTML, resp.Request.URL.String())
+	doc, err := c.parseHTML(charsetReader(body, contentType))
+	endSpan(parseSpan, err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
 	}
 
+	return &htmlPage{
+		doc:       doc,
+		finalURL:  resp.Request.URL,
+		header:    resp.Header,
+		truncated: limitedBody.truncated,
+		rawHead:   capture.head(),
+	}, nil
+}
+
+// buildMetadata extracts metadata from a parsed page. Relative URLs are
+// resolved against the final (post-redirect) page URL.
+func (c *Client) buildMetadata(page *htmlPage, parsedURL *url.URL) *Metadata {
 	metadata := &Metadata{
-		URL:             resp.Request.URL.String(),
+		URL:             page.finalURL.String(),
 		ProviderURL:     fmt.Sprintf("%s://%s", parsedURL.Scheme, parsedURL.Host),
 		ProviderDisplay: parsedURL.Host,
 		Images:          []Image{},
@@ -314,7 +831,37 @@ func (c *Client) extractHTMLOnly(targetURL string, parsedURL *url.URL) (*Metadat
 		Keywords:        []string{},
 	}
 
-	extractFromNode(doc, metadata, parsedURL)
+	if page.truncated {
+		metadata.addWarning(WarningBodyTruncated, "only the first %d bytes of the page were parsed", c.maxBodySize)
+	}
+
+	extractFromNode(page.doc, metadata, page.finalURL)
+	metadata.Microdata = extractMicrodata(page.doc, page.finalURL)
+	detectPublishedTime(page.doc, metadata, page.finalURL)
+	parseDates(metadata)
+
+	detectGenerator(page.doc, page.header, metadata)
+
+	metadata.Language = pageLanguage(page.doc, page.header, metadata.Locale)
+	if metadata.Language == "" && c.detectLanguage {
+		metadata.Language = detectTextLanguage(page.doc)
+	}
+
+	if len(metadata.Images) == 0 && c.bodyImageFallback > 0 {
+		metadata.Images = scanBodyImages(page.doc, page.finalURL, c.bodyImageFallback)
+	}
+
+	if c.contentAnalysis {
+		analyzeContent(page.doc, metadata)
+	}
+
+	if c.fullContent {
+		metadata.Content = extractContent(page.doc, page.finalURL)
+	}
+
+	if h1 := findElement(page.doc, atom.H1); h1 != nil {
+		metadata.Heading = strings.Join(strings.Fields(textContent(h1)), " ")
+	}
 
 	// Post-processing
 	if metadata.OGTitle != "" {
@@ -324,15 +871,91 @@ func (c *Client) extractHTMLOnly(targetURL string, parsedURL *url.URL) (*Metadat
 	}
 
 	metadata.Title = strings.TrimSpace(metadata.Title)
+	if len(c.titleFallbacks) > 0 && isGenericTitle(metadata.Title) {
+		if title := fallbackTitle(c.titleFallbacks, page.doc, page.finalURL); title != "" {
+			metadata.Title = title
+		}
+	}
+	if c.cleanTitle && metadata.SiteName != "" {
+		metadata.Title = CleanTitle(metadata.Title, metadata.SiteName)
+		metadata.OGTitle = CleanTitle(metadata.OGTitle, metadata.SiteName)
+		metadata.TwitterTitle = CleanTitle(metadata.TwitterTitle, metadata.SiteName)
+	}
 	metadata.Description = strings.TrimSpace(metadata.Description)
 
+	if metadata.Description == "" && c.descriptionMinLength > 0 {
+		paragraph := firstParagraph(page.doc, c.descriptionMinLength)
+		metadata.Description = truncateAtWord(paragraph, maxFallbackDescriptionLength)
+	}
+
+	if c.maxDescriptionLength > 0 {
+		metadata.Description = TruncateDescription(metadata.Description, c.maxDescriptionLength)
+		metadata.OGDescription = TruncateDescription(metadata.OGDescription, c.maxDescriptionLength)
+		metadata.TwitterDescription = TruncateDescription(metadata.TwitterDescription, c.maxDescriptionLength)
+	}
+
 	if metadata.SiteName != "" {
 		metadata.ProviderName = metadata.SiteName
 	} else {
 		metadata.ProviderName = parsedURL.Host
 	}
 
-	return metadata, nil
+	parseRobotsHeader(page.header, metadata)
+	if c.respectRobots {
+		applyRobots(metadata)
+	}
+
+	return metadata
+}
+
+// do sends req through the client's HTTP stack after applying host policy,
+// per-call overrides, credentials, the circuit breaker and throttling,
+// retrying transient failures (see WithRetry).
+// Every outbound request made by the package goes through here.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if err := c.checkHost(req.URL); err != nil {
+		return nil, err
+	}
+	applyRequestConfig(req)
+	c.applyCredentials(req)
+
+	if c.breaker == nil {
+		return c.doWithRetry(req)
+	}
+
+	if err := c.breaker.allow(req); err != nil {
+		c.logDebug(req.Context(), "urlmeta: request rejected by circuit breaker", "url", req.URL.String(), "error", err)
+		return nil, err
+	}
+	resp, err := c.doWithRetry(req)
+	c.breaker.record(req, resp, err)
+	return resp, err
+}
+
+// send makes a single attempt at req, running the request and response
+// hooks and holding a throttling slot until the response body is closed
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	release, err := c.acquire(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	c.runRequestHooks(req)
+	start := time.Now()
+
+	resp, err := c.httpClient.Do(req)
+	c.observeFetch(resp, start)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	c.runResponseHooks(resp, time.Since(start))
+
+	if c.metrics != nil {
+		resp.Body = &countingBody{ReadCloser: resp.Body, metrics: c.metrics}
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
 }
 
 // Extract is a convenience function using default client
@@ -403,6 +1026,8 @@ func processMeta(n *html.Node, metadata *Metadata, baseURL *url.URL) {
 	if name != "" {
 		processTwitterCard(name, content, metadata, baseURL)
 		processStandardMeta(name, content, metadata)
+		processBrandingMeta(name, content, metadata, baseURL)
+		processFacebook(name, content, metadata)
 	}
 
 	if itemProp != "" {
@@ -419,6 +1044,7 @@ func processOpenGraph(property, content string, metadata *Metadata, baseURL *url
 		"og:locale":              &metadata.Locale,
 		"article:published_time": &metadata.PublishedTime,
 		"article:modified_time":  &metadata.ModifiedTime,
+		"og:updated_time":        &metadata.UpdatedTime,
 	}
 
 	// Handle simple string assignments
@@ -438,6 +1064,7 @@ func processOpenGraph(property, content string, metadata *Metadata, baseURL *url
 
 	// Handle description with fallback
 	if property == "og:description" {
+		metadata.OGDescription = content
 		if metadata.Description == "" {
 			metadata.Description = content
 		}
@@ -460,6 +1087,26 @@ func processOpenGraph(property, content string, metadata *Metadata, baseURL *url
 		return
 	}
 
+	// Handle Facebook properties
+	if processFacebook(property, content, metadata) {
+		return
+	}
+
+	// Handle video:/music: namespaces
+	if processMediaInfo(property, content, metadata, baseURL) {
+		return
+	}
+
+	// Handle book:/profile: namespaces
+	if processBook(property, content, metadata, baseURL) || processProfile(property, content, metadata) {
+		return
+	}
+
+	// Handle product/commerce properties
+	if processProduct(property, content, metadata) {
+		return
+	}
+
 	// Handle images
 	if processOpenGraphImage(property, content, metadata, baseURL) {
 		return
@@ -473,7 +1120,7 @@ func processOpenGraph(property, content string, metadata *Metadata, baseURL *url
 func processOpenGraphImage(property, content string, metadata *Metadata, baseURL *url.URL) bool {
 	switch property {
 	case "og:image", "og:image:url":
-		metadata.Images = append(metadata.Images, Image{URL: resolveURL(content, baseURL)})
+		metadata.Images = append(metadata.Images, Image{URL: resolveImageURL(content, metadata, baseURL), Source: ImageSourceOpenGraph})
 		return true
 	case "og:image:width":
 		processImageDimension(metadata, content, true)
@@ -481,10 +1128,42 @@ func processOpenGraphImage(property, content string, metadata *Metadata, baseURL
 	case "og:image:height":
 		processImageDimension(metadata, content, false)
 		return true
+	case "og:image:secure_url":
+		processImageSecureURL(metadata, resolveImageURL(content, metadata, baseURL))
+		return true
+	case "og:image:alt":
+		if len(metadata.Images) > 0 {
+			metadata.Images[len(metadata.Images)-1].Alt = content
+		}
+		return true
+	case "og:image:type":
+		if len(metadata.Images) > 0 {
+			metadata.Images[len(metadata.Images)-1].Type = content
+		}
+		return true
 	}
 	return false
 }
 
+// processImageSecureURL applies og:image:secure_url to the most recent image,
+// replacing its URL unless that is already HTTPS. A secure_url without a
+// preceding og:image starts a new image.
+func processImageSecureURL(metadata *Metadata, secureURL string) {
+	if !strings.HasPrefix(strings.ToLower(secureURL), "https://") {
+		return
+	}
+
+	if len(metadata.Images) == 0 {
+		metadata.Images = append(metadata.Images, Image{URL: secureURL, Source: ImageSourceOpenGraph})
+		return
+	}
+
+	image := &metadata.Images[len(metadata.Images)-1]
+	if !strings.HasPrefix(strings.ToLower(image.URL), "https://") {
+		image.URL = secureURL
+	}
+}
+
 // processOpenGraphVideo handles video-related Open Graph properties
 func processOpenGraphVideo(property, content string, metadata *Metadata, baseURL *url.URL) bool {
 	switch property {
@@ -500,6 +1179,26 @@ func processOpenGraphVideo(property, content string, metadata *Metadata, baseURL
 	return false
 }
 
+// processFacebook handles fb:app_id and fb:pages, which sites emit with
+// either the property or the name attribute
+func processFacebook(key, content string, metadata *Metadata) bool {
+	switch key {
+	case "fb:app_id":
+		if metadata.FacebookAppID == "" {
+			metadata.FacebookAppID = content
+		}
+	case "fb:pages":
+		for _, page := range strings.Split(content, ",") {
+			if page = strings.TrimSpace(page); page != "" {
+				metadata.FacebookPages = append(metadata.FacebookPages, page)
+			}
+		}
+	default:
+		return false
+	}
+	return true
+}
+
 // processImageDimension handles image width/height
 func processImageDimension(metadata *Metadata, content string, isWidth bool) {
 	if len(metadata.Images) > 0 {
@@ -529,11 +1228,12 @@ func processTwitterCard(name, content string, metadata *Metadata, baseURL *url.U
 			metadata.Title = content
 		}
 	case "twitter:description":
+		metadata.TwitterDescription = content
 		if metadata.Description == "" {
 			metadata.Description = content
 		}
 	case "twitter:image", "twitter:image:src":
-		metadata.Images = append(metadata.Images, Image{URL: resolveURL(content, baseURL)})
+		metadata.Images = append(metadata.Images, Image{URL: resolveImageURL(content, metadata, baseURL), Source: ImageSourceTwitter})
 	}
 }
 
@@ -548,6 +1248,12 @@ func processStandardMeta(name, content string, metadata *Metadata) {
 		if metadata.Author == "" {
 			metadata.Author = content
 		}
+	case "generator":
+		if metadata.Generator == "" {
+			metadata.Generator = content
+		}
+	case "robots":
+		parseRobotsDirectives(content, metadata)
 	case "keywords":
 		keywords := strings.Split(content, ",")
 		for _, kw := range keywords {
@@ -571,13 +1277,13 @@ func processItemProp(itemProp, content string, metadata *Metadata) {
 			metadata.Description = content
 		}
 	case "image":
-		metadata.Images = append(metadata.Images, Image{URL: content})
+		metadata.Images = append(metadata.Images, Image{URL: content, Source: ImageSourceMicrodata})
 	}
 }
 
-// processLink handles link tags (favicon, canonical)
+// processLink handles link tags (icons, canonical, AMP, feeds)
 func processLink(n *html.Node, metadata *Metadata, baseURL *url.URL) {
-	var rel, href string
+	var rel, href, linkType, title, sizes, color string
 
 	for _, attr := range n.Attr {
 		switch attr.Key {
@@ -585,6 +1291,14 @@ func processLink(n *html.Node, metadata *Metadata, baseURL *url.URL) {
 			rel = attr.Val
 		case "href":
 			href = attr.Val
+		case "type":
+			linkType = attr.Val
+		case "title":
+			title = attr.Val
+		case "sizes":
+			sizes = attr.Val
+		case "color":
+			color = attr.Val
 		}
 	}
 
@@ -602,7 +1316,15 @@ func processLink(n *html.Node, metadata *Metadata, baseURL *url.URL) {
 		if metadata.CanonicalURL == "" {
 			metadata.CanonicalURL = resolveURL(href, baseURL)
 		}
+	case "amphtml":
+		if metadata.AMPURL == "" {
+			metadata.AMPURL = resolveURL(href, baseURL)
+		}
 	}
+
+	processIconLink(rel, linkType, sizes, href, metadata, baseURL)
+	processFeedLink(rel, linkType, title, href, metadata, baseURL)
+	processBrandingLink(rel, color, metadata)
 }
 
 // resolveURL resolves relative URLs to absolute
@@ -3,7 +3,9 @@ package urlmeta
 import (
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -186,6 +188,72 @@ func TestExtractOpenGraphMetadata(t *testing.T) {
 	}
 }
 
+func TestExtractOpenGraphImageProperties(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head>
+			<meta property="og:image" content="http://example.com/first.jpg">
+			<meta property="og:image:secure_url" content="https://secure.example.com/first.jpg">
+			<meta property="og:image:type" content="image/jpeg">
+			<meta property="og:image:alt" content="First image">
+			<meta property="og:image" content="https://example.com/second.png">
+			<meta property="og:image:secure_url" content="https://cdn.example.com/second.png">
+			<meta property="og:image:type" content="image/png">
+		</head></html>`))
+	}))
+	defer server.Close()
+
+	metadata, err := Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	expected := []Image{
+		{URL: "https://secure.example.com/first.jpg", Type: "image/jpeg", Alt: "First image", Source: ImageSourceOpenGraph},
+		{URL: "https://example.com/second.png", Type: "image/png", Source: ImageSourceOpenGraph},
+	}
+
+	if len(metadata.Images) != len(expected) {
+		t.Fatalf("Expected %d images, got %d: %+v", len(expected), len(metadata.Images), metadata.Images)
+	}
+
+	for i, img := range metadata.Images {
+		if !reflect.DeepEqual(img, expected[i]) {
+			t.Errorf("Image %d: expected %+v, got %+v", i, expected[i], img)
+		}
+	}
+}
+
+func TestExtractFacebookMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head>
+			<meta property="fb:app_id" content="1234567890">
+			<meta property="fb:pages" content="111, 222">
+			<meta name="fb:pages" content="333">
+			<meta property="og:updated_time" content="2025-02-03T04:05:06Z">
+		</head></html>`))
+	}))
+	defer server.Close()
+
+	metadata, err := Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.FacebookAppID != "1234567890" {
+		t.Errorf("Expected fb:app_id '1234567890', got '%s'", metadata.FacebookAppID)
+	}
+
+	if strings.Join(metadata.FacebookPages, ",") != "111,222,333" {
+		t.Errorf("Expected fb:pages [111 222 333], got %v", metadata.FacebookPages)
+	}
+
+	if metadata.UpdatedTime != "2025-02-03T04:05:06Z" {
+		t.Errorf("Expected updated time '2025-02-03T04:05:06Z', got '%s'", metadata.UpdatedTime)
+	}
+}
+
 func TestExtractTwitterCardMetadata(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/html")
@@ -426,6 +494,179 @@ func TestAutoOEmbedEnabled(t *testing.T) {
 	}
 }
 
+func newEnrichServer(t *testing.T, pageFails func(fetch int) bool) *httptest.Server {
+	t.Helper()
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oembed" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"type":"video","version":"1.0","title":"oEmbed Title","html":"<iframe></iframe>","thumbnail_url":"https://cdn.example.com/thumb.jpg"}`))
+			return
+		}
+		fetches++
+		if pageFails(fetches) {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head>
+			<title>HTML Title</title>
+			<meta name="description" content="From the page">
+			<meta name="keywords" content="go, video">
+			<link rel="canonical" href="https://example.com/video">
+			<link rel="alternate" type="application/json+oembed" href="/oembed">
+		</head></html>`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOEmbedEnrichHTML(t *testing.T) {
+	server := newEnrichServer(t, func(int) bool { return false })
+
+	client := NewClient(WithStrategy(StrategyOEmbedFirst), WithOEmbedEnrichHTML(true))
+	metadata, err := client.Extract(server.URL + "/video")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.Title != "oEmbed Title" || metadata.OEmbed == nil {
+		t.Errorf("Expected oEmbed values to win, got %q", metadata.Title)
+	}
+	if metadata.Description != "From the page" || metadata.CanonicalURL != "https://example.com/video" {
+		t.Errorf("Expected fields filled from HTML, got %q, %q", metadata.Description, metadata.CanonicalURL)
+	}
+	if !reflect.DeepEqual(metadata.Keywords, []string{"go", "video"}) {
+		t.Errorf("Expected keywords from HTML, got %v", metadata.Keywords)
+	}
+
+	// Without the option the page isn't fetched again
+	metadata, err = NewClient(WithStrategy(StrategyOEmbedFirst)).Extract(server.URL + "/video")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if metadata.Description != "" {
+		t.Errorf("Expected no HTML fields by default, got %q", metadata.Description)
+	}
+}
+
+func TestOEmbedEnrichHTMLFailure(t *testing.T) {
+	// Discovery fetches the page once; the enrichment fetch fails
+	server := newEnrichServer(t, func(fetch int) bool { return fetch > 1 })
+
+	client := NewClient(WithStrategy(StrategyOEmbedFirst), WithOEmbedEnrichHTML(true))
+	metadata, err := client.Extract(server.URL + "/video")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if metadata.Title != "oEmbed Title" {
+		t.Errorf("Expected the oEmbed result, got %q", metadata.Title)
+	}
+	if !metadata.HasWarning(WarningEnrichFailed) {
+		t.Errorf("Expected enrich_failed warning, got %v", metadata.Warnings)
+	}
+}
+
+func TestStrategyParallel(t *testing.T) {
+	restoreProviders(t)
+
+	const delay = 100 * time.Millisecond
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		switch r.URL.Path {
+		case "/oembed":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"type":"video","version":"1.0","title":"oEmbed Title","html":"<iframe></iframe>"}`))
+		case "/video":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><head><title>HTML Title</title><meta name="description" content="From the page"></head></html>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	AddCustomProvider(OEmbedProvider{
+		Name:      "Parallel Test",
+		URL:       server.URL,
+		Endpoints: []OEmbedEndpoint{{Schemes: []string{server.URL + "/video*"}, URL: server.URL + "/oembed"}},
+	})
+
+	client := NewClient(WithStrategy(StrategyParallel))
+	start := time.Now()
+	metadata, err := client.Extract(server.URL + "/video")
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.Title != "oEmbed Title" || metadata.OEmbed == nil || metadata.Description != "From the page" {
+		t.Errorf("Expected oEmbed and HTML merged, got %q, %q", metadata.Title, metadata.Description)
+	}
+	if elapsed >= 2*delay {
+		t.Errorf("Expected both requests to run concurrently, took %v", elapsed)
+	}
+
+	// Both sides failing returns the page error
+	metadata, err = client.Extract(server.URL + "/other")
+	if err == nil {
+		t.Fatalf("Expected the HTML error when both sides fail, got %+v", metadata)
+	}
+}
+
+func TestStrategyParallelOEmbedFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>HTML Title</title></head></html>`))
+	}))
+	defer server.Close()
+
+	metadata, err := NewClient(WithStrategy(StrategyParallel)).Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if metadata.Title != "HTML Title" || !metadata.HasWarning(WarningOEmbedFailed) {
+		t.Errorf("Expected the HTML result with an oembed_failed warning, got %q %v", metadata.Title, metadata.Warnings)
+	}
+}
+
+func TestStrategyParallelDiscovery(t *testing.T) {
+	var pageRequests, oembedRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/article", http.StatusMovedPermanently)
+		case "/article":
+			pageRequests.Add(1)
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><head><title>HTML Title</title>
+				<link rel="alternate" type="application/json+oembed" href="/oembed"></head></html>`))
+		case "/oembed":
+			oembedRequests.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"type":"rich","version":"1.0","title":"oEmbed Title","html":"<div></div>"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	metadata, err := NewClient(WithStrategy(StrategyParallel)).Extract(server.URL + "/old")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.Title != "oEmbed Title" || metadata.OEmbed == nil {
+		t.Errorf("Expected the discovered oEmbed, got %q", metadata.Title)
+	}
+	if pageRequests.Load() != 1 || oembedRequests.Load() != 1 {
+		t.Errorf("Expected 1 page and 1 oEmbed request, got %d and %d", pageRequests.Load(), oembedRequests.Load())
+	}
+	if metadata.URL != server.URL+"/article" {
+		t.Errorf("Expected the final page URL, got %q", metadata.URL)
+	}
+}
+
 func TestClientWithMaxRedirects(t *testing.T) {
 	redirectCount := 0
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,88 @@
+package urlmeta
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// WarningCode identifies the kind of non-fatal problem hit during extraction
+type WarningCode string
+
+const (
+	// WarningOEmbedFailed means the oEmbed lookup failed and the result was
+	// built from HTML instead
+	WarningOEmbedFailed WarningCode = "oembed_failed"
+	// WarningBodyTruncated means the body exceeded the size limit and only
+	// its prefix was parsed (non-strict mode only)
+	WarningBodyTruncated WarningCode = "body_truncated"
+	// WarningInvalidImageURL means an image URL could not be parsed and was
+	// kept verbatim
+	WarningInvalidImageURL WarningCode = "invalid_image_url"
+	// WarningAMPFailed means the AMP version could not be fetched and the
+	// original page was used (see WithPreferAMP)
+	WarningAMPFailed WarningCode = "amp_failed"
+	// WarningRobotsRestricted means preview data was removed or shortened
+	// to honor the page's robots directives (see WithRespectRobotsMeta)
+	WarningRobotsRestricted WarningCode = "robots_restricted"
+	// WarningRenderFailed means the renderer fallback failed and the static
+	// HTML result was kept (see WithRenderFallback)
+	WarningRenderFailed WarningCode = "render_failed"
+	// WarningFallbackUsed means the page itself failed or had no metadata
+	// and a copy from a fallback source was used (see WithFallbackSources)
+	WarningFallbackUsed WarningCode = "fallback_used"
+	// WarningEnrichFailed means the page could not be fetched to complete
+	// an oEmbed result, which was kept as is (see WithOEmbedEnrichHTML)
+	WarningEnrichFailed WarningCode = "enrich_failed"
+	// WarningSiteExtractorFailed means a site extractor failed and the page
+	// was extracted as usual (see WithSiteExtractors)
+	WarningSiteExtractorFailed WarningCode = "site_extractor_failed"
+	// WarningLinkedArticleFailed means the article a post links to could
+	// not be extracted (see HackerNewsExtractor)
+	WarningLinkedArticleFailed WarningCode = "linked_article_failed"
+)
+
+// Warning describes a non-fatal problem encountered while extracting
+type Warning struct {
+	Code    WarningCode `json:"code"`
+	Message string      `json:"message"`
+}
+
+// String implements fmt.Stringer
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s", w.Code, w.Message)
+}
+
+// WithStrictMode controls how recoverable problems are handled (default: true).
+// In strict mode they fail extraction, as before. With strict mode off the
+// client returns best-effort metadata and records the problem in
+// Metadata.Warnings instead; e.g. an oversized body is parsed up to the
+// size limit rather than rejected with ErrBodyTooLarge.
+func WithStrictMode(strict bool) Option {
+	return func(c *Client) {
+		c.strict = strict
+	}
+}
+
+// addWarning appends a warning to metadata
+func (m *Metadata) addWarning(code WarningCode, format string, args ...interface{}) {
+	m.Warnings = append(m.Warnings, Warning{Code: code, Message: fmt.Sprintf(format, args...)})
+}
+
+// HasWarning reports whether extraction recorded a warning with the given code
+func (m *Metadata) HasWarning(code WarningCode) bool {
+	for _, w := range m.Warnings {
+		if w.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveImageURL resolves an image URL against baseURL, recording a
+// warning when it is malformed
+func resolveImageURL(href string, metadata *Metadata, baseURL *url.URL) string {
+	if _, err := url.Parse(href); err != nil {
+		metadata.addWarning(WarningInvalidImageURL, "could not resolve image URL %q: %v", href, err)
+	}
+	return resolveURL(href, baseURL)
+}
@@ -0,0 +1,91 @@
+package urlmeta
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNonStrictModeTruncatesBody(t *testing.T) {
+	page := mockHTMLBasic + strings.Repeat("<p>padding</p>", 200)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	limit := int64(strings.Index(page, "</head>") + len("</head>"))
+
+	// Strict mode (default) rejects the oversized body
+	_, err := NewClient(WithMaxBodySize(limit)).Extract(server.URL)
+	if !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("Expected ErrBodyTooLarge in strict mode, got: %v", err)
+	}
+
+	client := NewClient(WithMaxBodySize(limit), WithStrictMode(false))
+	metadata, err := client.Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Expected partial metadata in non-strict mode, got: %v", err)
+	}
+
+	if metadata.Title != "Test Page Title" {
+		t.Errorf("Expected title from truncated body, got '%s'", metadata.Title)
+	}
+
+	if !metadata.HasWarning(WarningBodyTruncated) {
+		t.Errorf("Expected body_truncated warning, got %v", metadata.Warnings)
+	}
+}
+
+func TestOEmbedFailureWarning(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mockHTMLBasic))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithStrategy(StrategyOEmbedFirst))
+	metadata, err := client.Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if metadata.OEmbed != nil {
+		t.Error("Expected no oEmbed data")
+	}
+
+	if !metadata.HasWarning(WarningOEmbedFailed) {
+		t.Errorf("Expected oembed_failed warning, got %v", metadata.Warnings)
+	}
+}
+
+func TestInvalidImageURLWarning(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><meta property="og:image" content="http://[bad"></head></html>`))
+	}))
+	defer server.Close()
+
+	metadata, err := Extract(server.URL)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if len(metadata.Images) != 1 || metadata.Images[0].URL != "http://[bad" {
+		t.Errorf("Expected malformed image URL kept verbatim, got %+v", metadata.Images)
+	}
+
+	if !metadata.HasWarning(WarningInvalidImageURL) {
+		t.Errorf("Expected invalid_image_url warning, got %v", metadata.Warnings)
+	}
+}
+
+func TestWarningString(t *testing.T) {
+	w := Warning{Code: WarningOEmbedFailed, Message: "endpoint not found"}
+	if w.String() != "oembed_failed: endpoint not found" {
+		t.Errorf("Unexpected string: %s", w.String())
+	}
+}